@@ -7,16 +7,23 @@ import (
 	"os/signal"
 	"sync"
 	"syscall"
+	"time"
 
 	"l0/internal/application/usecases"
 	"l0/internal/application/validation"
+	"l0/internal/domain/model"
+	"l0/internal/infrastructure/breaker"
 	"l0/internal/infrastructure/cache"
 	"l0/internal/infrastructure/config"
 	"l0/internal/infrastructure/db"
+	"l0/internal/infrastructure/health"
 	"l0/internal/infrastructure/http/handlers"
 	"l0/internal/infrastructure/http/server"
+	"l0/internal/infrastructure/messaging/codec"
 	"l0/internal/infrastructure/messaging/kafka"
+	"l0/internal/infrastructure/metrics"
 	"l0/internal/infrastructure/persistence/postgres"
+	"l0/internal/infrastructure/tracing"
 
 	"go.uber.org/zap"
 )
@@ -40,6 +47,18 @@ func main() {
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer cancel()
 
+	shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing, "order-service")
+	if err != nil {
+		logger.Fatal("Failed to set up tracing", zap.Error(err))
+	}
+	defer func() {
+		flushCtx, flushCancel := context.WithTimeout(context.Background(), cfg.HTTP.ShutdownTimeout)
+		defer flushCancel()
+		if err := shutdownTracing(flushCtx); err != nil {
+			logger.Error("Failed to flush traces", zap.Error(err))
+		}
+	}()
+
 	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable", cfg.Database.Host, cfg.Database.Port, cfg.Database.User, cfg.Database.Password, cfg.Database.Name)
 	sqldb, err := db.NewDB(ctx, dsn, logger)
 	if err != nil {
@@ -48,32 +67,127 @@ func main() {
 
 	db.RunMigrations(sqldb, logger)
 
-	redisCache := cache.NewCache(cfg.Redis.Addr, logger)
-	orderCache := cache.NewOrderCache(redisCache)
+	cacheOpts := []cache.Option{
+		cache.WithOrderTTL(cfg.Redis.OrderTTL),
+		cache.WithMissingTTL(cfg.Redis.MissingTTL),
+		cache.WithTimeouts(cfg.Redis.DialTimeout, cfg.Redis.IOTimeout, cfg.Redis.MaxRetries),
+	}
+	if cfg.Redis.SlidingTTL {
+		cacheOpts = append(cacheOpts, cache.WithSlidingExpiration())
+	}
+	redisCache := cache.NewCache(cfg.Redis.Addr, logger, cacheOpts...)
+	metrics.RegisterRedisStats(func(ctx context.Context) (metrics.RedisStats, error) {
+		stats, err := redisCache.Stats(ctx)
+		return metrics.RedisStats(stats), err
+	})
+	// The decorators wrap the Redis tier only, so its spans and hit ratio
+	// are not blurred by lookups served from memory.
+	redisTier := cache.NewOrderCache(redisCache)
+	orderCache := tracing.NewOrderCache(metrics.NewOrderCache(redisTier))
+	cacheBreaker := breaker.New("redis", redisCache.Ping, breaker.Options{
+		FailureThreshold: cfg.Redis.BreakerFailures,
+		ProbeInterval:    cfg.Redis.BreakerProbeInterval,
+		ProbeTimeout:     cfg.Redis.IOTimeout,
+	}, logger)
+	orderCache = breaker.NewOrderCache(orderCache, cacheBreaker, logger)
+	if cfg.LocalCache.Size > 0 {
+		localCache := cache.NewLocalCache(cfg.LocalCache.Size, cfg.LocalCache.TTL)
+		bus := cache.NewInvalidationBus(redisCache)
+		bus.SkipWhile(func() bool { return !cacheBreaker.Allow() })
+		metrics.RegisterLocalCacheEntries(localCache.Len)
+		orderCache = cache.NewTieredOrderCache(orderCache, localCache, bus, cache.TieredHooks{
+			OnLookup:       metrics.LocalCacheHit,
+			OnInvalidation: metrics.LocalCacheInvalidated,
+		}, logger)
+	}
 	defer func() {
 		if err := orderCache.Close(); err != nil {
 			logger.Error("Failed to close order cache", zap.Error(err))
 		}
 	}()
 
-	if err := redisCache.RestoreFromDB(ctx, sqldb); err != nil {
-		logger.Error("Failed to restore cache from DB", zap.Error(err))
-	}
-	logger.Info("Cache restoration attempted")
+	orderRepo := tracing.NewOrderRepository(metrics.NewOrderRepository(postgres.NewOrderRepository(sqldb, logger)))
+	outboxRepo := postgres.NewOutboxRepository(sqldb, logger)
 
-	orderRepo := postgres.NewOrderRepository(sqldb, logger)
+	validator, err := newValidator(cfg.Validation, logger)
+	if err != nil {
+		logger.Fatal("Invalid validation config", zap.Error(err))
+	}
 
-	validator := validation.NewValidator()
+	cacheRepairs := cache.NewRepairQueue(orderRepo, orderCache, cache.RepairOptions{
+		Workers:     cfg.CacheRepair.Workers,
+		MaxAttempts: cfg.CacheRepair.MaxAttempts,
+		Backoff:     cfg.CacheRepair.Backoff,
+		MaxBackoff:  cfg.CacheRepair.MaxBackoff,
+		Size:        cfg.CacheRepair.QueueSize,
+		OnOutcome: func(outcome cache.RepairOutcome) {
+			metrics.CacheRepairs.WithLabelValues(string(outcome)).Inc()
+		},
+		OnQueueSize: func(size int) {
+			metrics.CacheRepairQueueSize.Set(float64(size))
+		},
+	}, logger)
 
 	getOrderUC := usecases.NewGetOrderUseCase(orderRepo, orderCache, logger)
-	saveOrderUC := usecases.NewSaveOrderUseCase(orderRepo, orderCache, validator, logger)
+	listOrdersUC := usecases.NewListOrdersUseCase(orderRepo, logger)
+	getByTrackUC := usecases.NewGetOrdersByTrackUseCase(orderRepo, orderCache, logger)
+	saveOrderUC := metrics.NewOrderSaver(usecases.NewSaveOrderUseCase(orderRepo, orderCache, cacheRepairs, tracing.NewOrderValidator(validator), logger))
+	changeStatusUC := usecases.NewChangeOrderStatusUseCase(orderRepo, orderCache, cacheRepairs, logger)
 
-	wg := &sync.WaitGroup{}
-	wg.Add(1)
-	go kafka.ConsumeOrders(ctx, wg, cfg.Kafka.Broker, cfg.Kafka.Topic, cfg.Kafka.GroupID, saveOrderUC, logger)
+	kafkaClient, err := kafka.NewClientOptions(cfg.Kafka)
+	if err != nil {
+		logger.Fatal("Invalid Kafka config", zap.Error(err))
+	}
+	readerOpts, err := kafka.NewReaderOptions(cfg.Kafka.Reader)
+	if err != nil {
+		logger.Fatal("Invalid Kafka reader config", zap.Error(err))
+	}
+
+	// DLQ entries, retries and outbox events go through one Kafka writer;
+	// each message names its topic.
+	publisher := kafka.NewPublisher(kafkaClient)
+	defer func() {
+		if err := publisher.Close(); err != nil {
+			logger.Error("Failed to close Kafka writer", zap.Error(err))
+		}
+	}()
+	dlq := kafka.NewDeadLetterPublisher(publisher, cfg.Kafka.DLQTopic, logger)
+	retryTopics := kafka.RetryTopics(cfg.Kafka.Topic, cfg.Kafka.RetryAttempts)
+	retrier := kafka.NewRetryPublisher(publisher, retryTopics, cfg.Kafka.RetryBackoff, dlq, logger)
+	statusRetryTopics := kafka.RetryTopics(cfg.Kafka.StatusTopic, cfg.Kafka.RetryAttempts)
+	statusRetrier := kafka.NewRetryPublisher(publisher, statusRetryTopics, cfg.Kafka.RetryBackoff, dlq, logger)
+
+	codecs, err := codec.NewOrderRegistry(cfg.Schemas.Dir)
+	if err != nil {
+		logger.Fatal("Failed to load order schemas", zap.Error(err))
+	}
+	processor := kafka.NewOrderProcessor(saveOrderUC, codecs, retrier, dlq, logger)
+	statusProcessor := kafka.NewStatusProcessor(changeStatusUC, statusRetrier, dlq, logger)
+	batch := kafka.BatchOptions{Size: cfg.Kafka.BatchSize, Timeout: cfg.Kafka.BatchTimeout}
 
-	orderHandler := handlers.NewOrderHandler(getOrderUC, logger)
-	serverHTTP := server.NewServer(orderHandler, logger)
+	orderTopics := append([]string{cfg.Kafka.Topic}, retryTopics...)
+	statusTopics := append([]string{cfg.Kafka.StatusTopic}, statusRetryTopics...)
+	membership := kafka.NewMembership(append(orderTopics, statusTopics...)...)
+	restored := health.NewFlag("cache warm-up in progress")
+
+	readiness := health.NewChecker(cfg.HTTP.HealthTimeout)
+	readiness.Register("postgres", sqldb.PingContext)
+	// Without Redis the service keeps serving from the DB, so its state is
+	// reported without taking the instance out of rotation.
+	readiness.RegisterOptional("redis", redisCache.Ping)
+	readiness.RegisterOptional("redis_breaker", cacheBreaker.Check)
+	readiness.Register("kafka", membership.Check)
+	// Lookups fall back to the DB while the cache warms up, so the restore
+	// is reported but does not hold back readiness.
+	readiness.RegisterOptional("cache_restore", restored.Check)
+
+	// The server starts before the consumers, so /healthz answers and
+	// /readyz reports progress while they come up.
+	orderHandler := handlers.NewOrderHandler(getOrderUC, listOrdersUC, getByTrackUC, logger)
+	idempotencyStore := breaker.NewIdempotencyStore(cache.NewIdempotencyStore(redisCache, cfg.HTTP.IdempotencyTTL), cacheBreaker)
+	ingestHandler := handlers.NewIngestHandler(saveOrderUC, idempotencyStore, logger)
+	healthHandler := handlers.NewHealthHandler(readiness, logger)
+	serverHTTP := server.NewServer(orderHandler, ingestHandler, healthHandler, logger)
 
 	go func() {
 		if err := serverHTTP.Start(cfg.HTTP.Port); err != nil {
@@ -81,6 +195,73 @@ func main() {
 		}
 	}()
 
+	wg := &sync.WaitGroup{}
+
+	var restoreSince time.Time
+	if cfg.Redis.RestoreDays > 0 {
+		// Whole days, so a restart on the same day resumes the restore
+		// instead of starting a new window.
+		restoreSince = time.Now().UTC().AddDate(0, 0, -cfg.Redis.RestoreDays).Truncate(24 * time.Hour)
+	}
+	restoreUC := usecases.NewRestoreCacheUseCase(orderRepo, orderCache, cache.NewRestoreCheckpointStore(redisCache), logger)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer restored.Set()
+		if err := restoreUC.Execute(ctx, usecases.RestoreOptions{
+			Since:     restoreSince,
+			ChunkSize: cfg.Redis.RestoreChunkSize,
+			Workers:   cfg.Redis.RestoreWorkers,
+			OnProgress: func(p model.RestoreProgress) {
+				metrics.CacheRestoredOrders.Set(float64(p.Restored))
+			},
+		}); err != nil {
+			logger.Error("Failed to restore cache from DB", zap.Error(err))
+		}
+	}()
+
+	wg.Add(1)
+	go cacheRepairs.Run(ctx, wg)
+
+	if cfg.CacheRepair.ReconcileInterval > 0 {
+		// The reconciler peeks at Redis directly: going through the local
+		// tier would churn it, and through the decorators would count its
+		// reads as lookups.
+		reconcileUC := usecases.NewReconcileCacheUseCase(orderRepo, breaker.NewOrderCacheInspector(redisTier, cacheBreaker), cacheRepairs, logger)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			reconcileUC.Run(ctx, cfg.CacheRepair.ReconcileInterval, usecases.ReconcileOptions{
+				Window:    cfg.CacheRepair.ReconcileWindow,
+				ChunkSize: cfg.Redis.RestoreChunkSize,
+				OnPass: func(r model.ReconcileResult) {
+					metrics.CacheReconciledOrders.WithLabelValues("missing").Add(float64(r.Missing))
+					metrics.CacheReconciledOrders.WithLabelValues("stale").Add(float64(r.Stale))
+				},
+			})
+		}()
+	}
+
+	for _, topic := range orderTopics {
+		source := kafka.NewSource(kafkaClient, topic, cfg.Kafka.GroupID, readerOpts)
+		wg.Add(1)
+		go kafka.Consume(ctx, wg, source, source, topic, cfg.Kafka.Workers, batch, processor, membership, logger)
+	}
+	for _, topic := range statusTopics {
+		source := kafka.NewSource(kafkaClient, topic, cfg.Kafka.GroupID, readerOpts)
+		wg.Add(1)
+		go kafka.Consume(ctx, wg, source, source, topic, cfg.Kafka.Workers, kafka.BatchOptions{}, statusProcessor, membership, logger)
+	}
+
+	relay := kafka.NewOutboxRelay(publisher, cfg.Kafka.EventsTopic, outboxRepo, kafka.OutboxRelayOptions{
+		BatchSize:       cfg.Outbox.BatchSize,
+		PollInterval:    cfg.Outbox.PollInterval,
+		Retention:       cfg.Outbox.Retention,
+		CleanupInterval: cfg.Outbox.CleanupInterval,
+	}, logger)
+	wg.Add(1)
+	go relay.Run(ctx, wg)
+
 	logger.Info("Application started. Waiting for signals...")
 	<-ctx.Done()
 
@@ -95,9 +276,35 @@ func main() {
 		logger.Info("HTTP server stopped gracefully")
 	}
 
-	logger.Info("Waiting for Kafka consumer to stop...")
+	logger.Info("Waiting for Kafka consumers and background workers to stop...")
 	wg.Wait()
-	logger.Info("Kafka consumer stopped")
+	logger.Info("Kafka consumers and background workers stopped")
 
 	logger.Info("Application stopped successfully")
 }
+
+func newValidator(cfg config.ValidationConfig, logger *zap.Logger) (*validation.Validator, error) {
+	opts := []validation.Option{
+		validation.WithWarningHandler(func(order *model.Order, warnings []model.FieldViolation) {
+			logger.Warn("Order violates business rules, accepting",
+				zap.String("order_uid", order.OrderUID), zap.Any("violations", warnings))
+		}),
+	}
+	for _, r := range []struct {
+		rule validation.Rule
+		mode string
+	}{
+		{validation.GoodsTotalRule, cfg.GoodsTotal},
+		{validation.PaymentAmountRule, cfg.PaymentAmount},
+		{validation.ItemTotalRule, cfg.ItemTotal},
+		{validation.TransactionRule, cfg.Transaction},
+		{validation.ItemTrackNumberRule, cfg.ItemTrackNumber},
+	} {
+		mode, err := validation.ParseMode(r.mode)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: %w", r.rule.Name, err)
+		}
+		opts = append(opts, validation.WithRule(r.rule, mode))
+	}
+	return validation.NewValidator(opts...), nil
+}
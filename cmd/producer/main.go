@@ -2,28 +2,40 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"flag"
 	"fmt"
 	"math/rand/v2"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
 	"l0/internal/domain/model"
 	"l0/internal/infrastructure/config"
+	"l0/internal/infrastructure/messaging/codec"
+	"l0/internal/infrastructure/messaging/kafka"
+	"l0/internal/infrastructure/tracing"
 
 	"github.com/brianvoe/gofakeit/v7"
-	"github.com/segmentio/kafka-go"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
 var (
 	intervalFlag = flag.Duration("interval", 5*time.Second, "Time interval between messages")
 	badDataRate  = flag.Float64("bad-rate", 0.2, "Rate of bad data messages")
+	formatFlag   = flag.String("format", "json", "Order encoding: json, protobuf or avro")
 )
 
+var formats = map[string]string{
+	"json":     codec.ContentTypeJSON,
+	"protobuf": codec.ContentTypeProtobuf,
+	"avro":     codec.ContentTypeAvro,
+}
+
 func main() {
 	flag.Parse()
 
@@ -42,21 +54,45 @@ func main() {
 		logger.Fatal("Failed to load config", zap.Error(err))
 	}
 
-	writer := &kafka.Writer{
-		Addr:                   kafka.TCP(cfg.Kafka.Broker),
-		Topic:                  cfg.Kafka.Topic,
-		Balancer:               &kafka.Hash{},
-		AllowAutoTopicCreation: true,
+	contentType, ok := formats[*formatFlag]
+	if !ok {
+		logger.Fatal("Unknown format, want json, protobuf or avro", zap.String("format", *formatFlag))
+	}
+	codecs, err := codec.NewOrderRegistry(cfg.Schemas.Dir)
+	if err != nil {
+		logger.Fatal("Failed to load order schemas", zap.Error(err))
+	}
+	encoder, err := codecs.Lookup(contentType)
+	if err != nil {
+		logger.Fatal("Failed to pick order encoder", zap.Error(err))
+	}
+
+	kafkaClient, err := kafka.NewClientOptions(cfg.Kafka)
+	if err != nil {
+		logger.Fatal("Invalid Kafka config", zap.Error(err))
 	}
+	publisher := kafka.NewPublisher(kafkaClient)
 	defer func() {
-		if err := writer.Close(); err != nil {
+		if err := publisher.Close(); err != nil {
 			logger.Error("Failed to close Kafka writer", zap.Error(err))
 		}
 	}()
 
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer cancel()
-	logger.Info("Producer started", zap.String("broker", cfg.Kafka.Broker), zap.Float64("bad_rate", *badDataRate), zap.Duration("interval", *intervalFlag))
+
+	shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing, "order-producer")
+	if err != nil {
+		logger.Fatal("Failed to set up tracing", zap.Error(err))
+	}
+	defer func() {
+		flushCtx, flushCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer flushCancel()
+		if err := shutdownTracing(flushCtx); err != nil {
+			logger.Error("Failed to flush traces", zap.Error(err))
+		}
+	}()
+	logger.Info("Producer started", zap.Strings("brokers", cfg.Kafka.Brokers), zap.String("format", *formatFlag), zap.Float64("bad_rate", *badDataRate), zap.Duration("interval", *intervalFlag))
 
 	ticker := time.NewTicker(*intervalFlag)
 	defer ticker.Stop()
@@ -67,29 +103,30 @@ func main() {
 			logger.Info("Stopping producer...")
 			return
 		case <-ticker.C:
-			if err := produce(ctx, writer, logger); err != nil {
+			if err := produce(ctx, publisher, encoder, cfg.Kafka.Topic, logger); err != nil {
 				logger.Error("Failed to produce message", zap.Error(err))
 			}
 		}
 	}
 }
 
-func produce(ctx context.Context, writer *kafka.Writer, logger *zap.Logger) error {
+func produce(ctx context.Context, publisher *kafka.Publisher, encoder codec.Codec, topic string, logger *zap.Logger) error {
 	if rand.Float64() < *badDataRate {
-		return sendGarbage(ctx, writer)
+		return sendGarbage(ctx, publisher, encoder.ContentType(), topic)
 	}
-	return sendOrder(ctx, writer, logger)
+	return sendOrder(ctx, publisher, encoder, topic, logger)
 }
 
-func sendOrder(ctx context.Context, writer *kafka.Writer, logger *zap.Logger) error {
+func sendOrder(ctx context.Context, publisher *kafka.Publisher, encoder codec.Codec, topic string, logger *zap.Logger) error {
 	order := generateRealOrder()
 
-	payload, err := json.Marshal(order)
+	payload, err := encoder.Encode(&order)
 	if err != nil {
-		return fmt.Errorf("failed to marshal order: %w", err)
+		return fmt.Errorf("failed to encode order: %w", err)
 	}
 
-	if err := writer.WriteMessages(ctx, kafka.Message{Key: []byte(order.OrderUID), Value: payload}); err != nil {
+	msg := model.Message{Topic: topic, Key: []byte(order.OrderUID), Value: payload, Headers: payloadHeaders(encoder.ContentType())}
+	if err := publish(ctx, publisher, msg); err != nil {
 		return fmt.Errorf("failed to write message: %w", err)
 	}
 
@@ -97,22 +134,78 @@ func sendOrder(ctx context.Context, writer *kafka.Writer, logger *zap.Logger) er
 	return nil
 }
 
-func sendGarbage(ctx context.Context, writer *kafka.Writer) error {
+// sendGarbage sends a payload that fails to decode in any of the formats,
+// labelled with the producer's content type.
+func sendGarbage(ctx context.Context, publisher *kafka.Publisher, contentType, topic string) error {
 	garbage := []byte(fmt.Sprintf(`{"uid: "%s", "broken": true,`, gofakeit.UUID()))
 
-	return writer.WriteMessages(ctx, kafka.Message{
-		Key:   []byte(gofakeit.UUID()),
-		Value: garbage,
+	return publish(ctx, publisher, model.Message{
+		Topic:   topic,
+		Key:     []byte(gofakeit.UUID()),
+		Value:   garbage,
+		Headers: payloadHeaders(contentType),
 	})
 }
 
+// payloadHeaders tell the consumer how the payload is encoded.
+func payloadHeaders(contentType string) []model.MessageHeader {
+	return []model.MessageHeader{
+		{Key: kafka.HeaderContentType, Value: []byte(contentType)},
+		{Key: kafka.HeaderSchemaVersion, Value: []byte(strconv.Itoa(codec.CurrentSchemaVersion))},
+	}
+}
+
+// publish writes msg under a producer span and passes the span's context in
+// the message headers, so the consumer continues the same trace.
+func publish(ctx context.Context, publisher *kafka.Publisher, msg model.Message) error {
+	ctx, span := tracing.Tracer().Start(ctx, msg.Topic+" send",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKafka,
+			semconv.MessagingDestinationName(msg.Topic),
+			semconv.MessagingOperationTypeSend,
+			semconv.MessagingKafkaMessageKey(string(msg.Key)),
+		))
+	msg.Headers = tracing.Inject(ctx, msg.Headers)
+	err := publisher.Publish(ctx, msg)
+	tracing.End(span, err)
+	return err
+}
+
+// generateRealOrder builds an order that also passes the business rules:
+// totals add up, the transaction is the order UID and items share the
+// order's track number.
 func generateRealOrder() model.Order {
 	uid := gofakeit.UUID()
-	price := gofakeit.Price(100, 5000)
+	trackNumber := "WBIL" + strings.ToUpper(gofakeit.LetterN(10))
+	deliveryCost := 1500
+
+	items := make([]model.Item, gofakeit.Number(1, 3))
+	goodsTotal := 0
+	for i := range items {
+		price := gofakeit.Number(100, 5000)
+		sale := gofakeit.Number(0, 90)
+		totalPrice := price * (100 - sale) / 100
+		goodsTotal += totalPrice
+
+		items[i] = model.Item{
+			ChrtID:      gofakeit.Number(1000000, 9999999),
+			TrackNumber: trackNumber,
+			Price:       price,
+			Rid:         gofakeit.UUID(),
+			Name:        gofakeit.ProductName(),
+			Sale:        sale,
+			Size:        gofakeit.RandomString([]string{"S", "M", "L", "XL", "XXL", "0"}),
+			TotalPrice:  totalPrice,
+			NmID:        gofakeit.Number(100000, 99999999),
+			Brand:       gofakeit.Company(),
+			Status:      202,
+		}
+	}
 
 	return model.Order{
 		OrderUID:          uid,
-		TrackNumber:       gofakeit.UUID(),
+		TrackNumber:       trackNumber,
 		Entry:             gofakeit.RandomString([]string{"WBIL", "OZON", "SBER"}),
 		Locale:            "ru",
 		InternalSignature: "",
@@ -138,28 +231,14 @@ func generateRealOrder() model.Order {
 			RequestID:    gofakeit.UUID(),
 			Currency:     "RUB",
 			Provider:     gofakeit.RandomString([]string{"alfabank", "sberbank", "tinkoff"}),
-			Amount:       int(price * 100),
+			Amount:       goodsTotal + deliveryCost,
 			PaymentDt:    time.Now().Unix(),
 			Bank:         "alfabank",
-			DeliveryCost: 1500,
-			GoodsTotal:   int(price * 100),
+			DeliveryCost: deliveryCost,
+			GoodsTotal:   goodsTotal,
 			CustomFee:    0,
 		},
 
-		Items: []model.Item{
-			{
-				ChrtID:      gofakeit.Number(1000000, 9999999),
-				TrackNumber: gofakeit.UUID(),
-				Price:       int(price * 100),
-				Rid:         gofakeit.UUID(),
-				Name:        gofakeit.ProductName(),
-				Sale:        gofakeit.Number(0, 90),
-				Size:        gofakeit.RandomString([]string{"S", "M", "L", "XL", "XXL", "0"}),
-				TotalPrice:  int(price * 100),
-				NmID:        gofakeit.Number(100000, 99999999),
-				Brand:       gofakeit.Company(),
-				Status:      202,
-			},
-		},
+		Items: items,
 	}
 }
@@ -0,0 +1,72 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"l0/internal/domain/model"
+	"l0/internal/domain/repository"
+
+	"go.uber.org/zap"
+)
+
+type ChangeOrderStatusUseCase struct {
+	orderRepo  repository.OrderRepository
+	orderCache repository.OrderCache
+	repairs    repository.CacheRepairQueue
+	logger     *zap.Logger
+}
+
+func NewChangeOrderStatusUseCase(orderRepo repository.OrderRepository, orderCache repository.OrderCache, repairs repository.CacheRepairQueue, logger *zap.Logger) *ChangeOrderStatusUseCase {
+	return &ChangeOrderStatusUseCase{orderRepo: orderRepo, orderCache: orderCache, repairs: repairs, logger: logger}
+}
+
+// Execute moves the order to change.Status if the lifecycle allows it.
+// Repeating a change that has already been applied is a no-op, so redelivered
+// messages are safe. A failed cache write leaves the order to the repair
+// queue rather than failing a change the DB has already taken.
+func (uc *ChangeOrderStatusUseCase) Execute(ctx context.Context, change *model.StatusChange) error {
+	if change.OrderUID == "" {
+		return fmt.Errorf("%w: order_uid is required", model.ErrInvalidStatusUpdate)
+	}
+	if !change.Status.IsValid() {
+		return fmt.Errorf("%w: unknown status %q", model.ErrInvalidStatusUpdate, change.Status)
+	}
+	if n := utf8.RuneCountInString(change.Reason); n > model.MaxStatusReasonLength {
+		return fmt.Errorf("%w: reason is %d characters long, at most %d allowed", model.ErrInvalidStatusUpdate, n, model.MaxStatusReasonLength)
+	}
+
+	order, err := uc.orderRepo.GetByUID(ctx, change.OrderUID)
+	if err != nil {
+		if errors.Is(err, model.ErrOrderNotFound) {
+			return model.ErrOrderNotFound
+		}
+		return fmt.Errorf("failed to get order from DB: %w", err)
+	}
+
+	if order.Status != change.Status {
+		if !order.Status.CanTransitionTo(change.Status) {
+			uc.logger.Warn("Illegal order status transition",
+				zap.String("order_uid", change.OrderUID),
+				zap.String("from", string(order.Status)), zap.String("to", string(change.Status)))
+			return fmt.Errorf("%w: %s -> %s", model.ErrInvalidStatusTransition, order.Status, change.Status)
+		}
+
+		if err := uc.orderRepo.UpdateStatus(ctx, change.OrderUID, order.Status, change.Status, change.Reason); err != nil {
+			uc.logger.Error("Failed to update order status", zap.Error(err), zap.String("order_uid", change.OrderUID))
+			return fmt.Errorf("failed to update order status: %w", err)
+		}
+		uc.logger.Info("Order status changed",
+			zap.String("order_uid", change.OrderUID),
+			zap.String("from", string(order.Status)), zap.String("to", string(change.Status)))
+		order.Status = change.Status
+	}
+
+	if err := uc.orderCache.Set(ctx, order); err != nil {
+		uc.logger.Warn("Failed to save order to cache, queued for repair", zap.Error(err), zap.String("order_uid", change.OrderUID))
+		uc.repairs.Enqueue(change.OrderUID)
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"l0/internal/domain/model"
+	"l0/internal/domain/repository/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestChangeOrderStatusUseCase_Success(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+	mockCache := mocks.NewMockOrderCache(ctrl)
+	uc := NewChangeOrderStatusUseCase(mockRepo, mockCache, mocks.NewMockCacheRepairQueue(ctrl), zap.NewNop())
+
+	ctx := context.Background()
+	order := &model.Order{OrderUID: "order-1", Status: model.OrderStatusCreated}
+	change := &model.StatusChange{OrderUID: "order-1", Status: model.OrderStatusPaid, Reason: "payment received"}
+
+	mockRepo.EXPECT().GetByUID(ctx, "order-1").Return(order, nil)
+	mockRepo.EXPECT().
+		UpdateStatus(ctx, "order-1", model.OrderStatusCreated, model.OrderStatusPaid, "payment received").
+		Return(nil)
+	mockCache.EXPECT().
+		Set(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, o *model.Order) error {
+			assert.Equal(t, model.OrderStatusPaid, o.Status)
+			return nil
+		})
+
+	require.NoError(t, uc.Execute(ctx, change))
+}
+
+func TestChangeOrderStatusUseCase_AlreadyApplied(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+	mockCache := mocks.NewMockOrderCache(ctrl)
+	uc := NewChangeOrderStatusUseCase(mockRepo, mockCache, mocks.NewMockCacheRepairQueue(ctrl), zap.NewNop())
+
+	ctx := context.Background()
+	order := &model.Order{OrderUID: "order-1", Status: model.OrderStatusPaid}
+
+	mockRepo.EXPECT().GetByUID(ctx, "order-1").Return(order, nil)
+	mockRepo.EXPECT().UpdateStatus(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	mockCache.EXPECT().Set(ctx, order).Return(nil)
+
+	require.NoError(t, uc.Execute(ctx, &model.StatusChange{OrderUID: "order-1", Status: model.OrderStatusPaid}))
+}
+
+func TestChangeOrderStatusUseCase_LongestReasonFits(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+	mockCache := mocks.NewMockOrderCache(ctrl)
+	uc := NewChangeOrderStatusUseCase(mockRepo, mockCache, mocks.NewMockCacheRepairQueue(ctrl), zap.NewNop())
+
+	// The limit counts characters, as VARCHAR does, not bytes.
+	reason := strings.Repeat("я", model.MaxStatusReasonLength)
+	mockRepo.EXPECT().GetByUID(gomock.Any(), "order-1").
+		Return(&model.Order{OrderUID: "order-1", Status: model.OrderStatusCreated}, nil)
+	mockRepo.EXPECT().UpdateStatus(gomock.Any(), "order-1", model.OrderStatusCreated, model.OrderStatusPaid, reason).Return(nil)
+	mockCache.EXPECT().Set(gomock.Any(), gomock.Any()).Return(nil)
+
+	require.NoError(t, uc.Execute(context.Background(), &model.StatusChange{OrderUID: "order-1", Status: model.OrderStatusPaid, Reason: reason}))
+}
+
+func TestChangeOrderStatusUseCase_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		change     *model.StatusChange
+		setupMocks func(*mocks.MockOrderRepository, *mocks.MockOrderCache)
+		wantErr    error
+	}{
+		{
+			name:       "missing_order_uid",
+			change:     &model.StatusChange{Status: model.OrderStatusPaid},
+			setupMocks: func(repo *mocks.MockOrderRepository, cache *mocks.MockOrderCache) {},
+			wantErr:    model.ErrInvalidStatusUpdate,
+		},
+		{
+			name:       "unknown_status",
+			change:     &model.StatusChange{OrderUID: "order-1", Status: "lost"},
+			setupMocks: func(repo *mocks.MockOrderRepository, cache *mocks.MockOrderCache) {},
+			wantErr:    model.ErrInvalidStatusUpdate,
+		},
+		{
+			name:       "reason_too_long",
+			change:     &model.StatusChange{OrderUID: "order-1", Status: model.OrderStatusPaid, Reason: strings.Repeat("я", model.MaxStatusReasonLength+1)},
+			setupMocks: func(repo *mocks.MockOrderRepository, cache *mocks.MockOrderCache) {},
+			wantErr:    model.ErrInvalidStatusUpdate,
+		},
+		{
+			name:   "order_not_found",
+			change: &model.StatusChange{OrderUID: "order-1", Status: model.OrderStatusPaid},
+			setupMocks: func(repo *mocks.MockOrderRepository, cache *mocks.MockOrderCache) {
+				repo.EXPECT().GetByUID(gomock.Any(), "order-1").Return(nil, model.ErrOrderNotFound)
+			},
+			wantErr: model.ErrOrderNotFound,
+		},
+		{
+			name:   "illegal_transition",
+			change: &model.StatusChange{OrderUID: "order-1", Status: model.OrderStatusDelivered},
+			setupMocks: func(repo *mocks.MockOrderRepository, cache *mocks.MockOrderCache) {
+				repo.EXPECT().GetByUID(gomock.Any(), "order-1").
+					Return(&model.Order{OrderUID: "order-1", Status: model.OrderStatusCreated}, nil)
+				repo.EXPECT().UpdateStatus(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				cache.EXPECT().Set(gomock.Any(), gomock.Any()).Times(0)
+			},
+			wantErr: model.ErrInvalidStatusTransition,
+		},
+		{
+			name:   "concurrent_change",
+			change: &model.StatusChange{OrderUID: "order-1", Status: model.OrderStatusPaid},
+			setupMocks: func(repo *mocks.MockOrderRepository, cache *mocks.MockOrderCache) {
+				repo.EXPECT().GetByUID(gomock.Any(), "order-1").
+					Return(&model.Order{OrderUID: "order-1", Status: model.OrderStatusCreated}, nil)
+				repo.EXPECT().UpdateStatus(gomock.Any(), "order-1", model.OrderStatusCreated, model.OrderStatusPaid, "").
+					Return(model.ErrStatusConflict)
+				cache.EXPECT().Set(gomock.Any(), gomock.Any()).Times(0)
+			},
+			wantErr: model.ErrStatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockOrderRepository(ctrl)
+			mockCache := mocks.NewMockOrderCache(ctrl)
+			tt.setupMocks(mockRepo, mockCache)
+
+			uc := NewChangeOrderStatusUseCase(mockRepo, mockCache, mocks.NewMockCacheRepairQueue(ctrl), zap.NewNop())
+			err := uc.Execute(context.Background(), tt.change)
+
+			require.Error(t, err)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestChangeOrderStatusUseCase_CacheSetFailedQueuesRepair(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+	mockCache := mocks.NewMockOrderCache(ctrl)
+	mockRepairs := mocks.NewMockCacheRepairQueue(ctrl)
+	uc := NewChangeOrderStatusUseCase(mockRepo, mockCache, mockRepairs, zap.NewNop())
+
+	mockRepo.EXPECT().GetByUID(gomock.Any(), "order-1").
+		Return(&model.Order{OrderUID: "order-1", Status: model.OrderStatusCreated}, nil)
+	mockRepo.EXPECT().UpdateStatus(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	mockCache.EXPECT().Set(gomock.Any(), gomock.Any()).Return(errors.New("redis down"))
+	mockRepairs.EXPECT().Enqueue("order-1")
+
+	err := uc.Execute(context.Background(), &model.StatusChange{OrderUID: "order-1", Status: model.OrderStatusPaid})
+
+	assert.NoError(t, err)
+}
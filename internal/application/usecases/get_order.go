@@ -9,11 +9,17 @@ import (
 	"l0/internal/domain/repository"
 
 	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
 )
 
+// GetOrderUseCase reads through the cache. Concurrent misses for the same
+// order share one DB lookup, and UIDs the DB doesn't know are recorded in the
+// cache as missing, so neither a popular order expiring nor a client probing
+// unknown UIDs multiplies the load on the DB.
 type GetOrderUseCase struct {
 	orderRepo  repository.OrderRepository
 	orderCache repository.OrderCache
+	loads      singleflight.Group
 	logger     *zap.Logger
 }
 
@@ -23,15 +29,44 @@ func NewGetOrderUseCase(repo repository.OrderRepository, cache repository.OrderC
 
 func (uc *GetOrderUseCase) Execute(ctx context.Context, orderUID string) (*model.Order, error) {
 	order, err := uc.orderCache.Get(ctx, orderUID)
+	if errors.Is(err, model.ErrOrderNotFound) {
+		uc.logger.Debug("Order known to be missing", zap.String("order_uid", orderUID))
+		return nil, model.ErrOrderNotFound
+	}
 	if err == nil && order != nil {
 		uc.logger.Debug("Order retrieved from cache", zap.String("order_uid", orderUID))
 		return order, nil
 	}
 
-	order, err = uc.orderRepo.GetByUID(ctx, orderUID)
+	// The shared lookup must not fail for everyone when the caller that
+	// started it goes away, so it runs detached from that caller's
+	// cancellation, and each caller stops waiting on its own.
+	loadCtx := context.WithoutCancel(ctx)
+	result := uc.loads.DoChan(orderUID, func() (any, error) {
+		return uc.load(loadCtx, orderUID)
+	})
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-result:
+		if res.Shared {
+			uc.logger.Debug("Order lookup shared with concurrent requests", zap.String("order_uid", orderUID))
+		}
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return res.Val.(*model.Order), nil
+	}
+}
+
+func (uc *GetOrderUseCase) load(ctx context.Context, orderUID string) (*model.Order, error) {
+	order, err := uc.orderRepo.GetByUID(ctx, orderUID)
 	if err != nil {
 		if errors.Is(err, model.ErrOrderNotFound) {
 			uc.logger.Info("Order not found", zap.String("order_uid", orderUID))
+			if err := uc.orderCache.MarkMissing(ctx, orderUID); err != nil {
+				uc.logger.Warn("Failed to record missing order in cache", zap.Error(err), zap.String("order_uid", orderUID))
+			}
 			return nil, model.ErrOrderNotFound
 		}
 		uc.logger.Error("Failed to get order from DB", zap.Error(err), zap.String("order_uid", orderUID))
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"testing/synctest"
 
 	"l0/internal/domain/model"
 	"l0/internal/domain/repository/mocks"
@@ -71,11 +72,11 @@ func TestGetOrderUseCase_Execute_CacheMiss_FoundInDB(t *testing.T) {
 		Return(nil, errors.New("cache miss"))
 
 	mockRepo.EXPECT().
-		GetByUID(ctx, uid).
+		GetByUID(gomock.Any(), uid).
 		Return(expectedOrder, nil)
 
 	mockCache.EXPECT().
-		Set(ctx, expectedOrder).
+		Set(gomock.Any(), expectedOrder).
 		Return(nil)
 
 	order, err := uc.Execute(ctx, uid)
@@ -104,9 +105,13 @@ func TestGetOrderUseCase_Execute_NotFound(t *testing.T) {
 		Return(nil, errors.New("not in cache"))
 
 	mockRepo.EXPECT().
-		GetByUID(ctx, uid).
+		GetByUID(gomock.Any(), uid).
 		Return(nil, model.ErrOrderNotFound)
 
+	mockCache.EXPECT().
+		MarkMissing(gomock.Any(), uid).
+		Return(nil)
+
 	order, err := uc.Execute(ctx, uid)
 
 	require.ErrorIs(t, err, model.ErrOrderNotFound)
@@ -130,8 +135,8 @@ func TestGetOrderUseCase_Execute_CacheSetFailure_ReturnsData(t *testing.T) {
 	expectedOrder := &model.Order{OrderUID: uid}
 
 	mockCache.EXPECT().Get(ctx, uid).Return(nil, errors.New("miss"))
-	mockRepo.EXPECT().GetByUID(ctx, uid).Return(expectedOrder, nil)
-	mockCache.EXPECT().Set(ctx, expectedOrder).Return(errors.New("redis down"))
+	mockRepo.EXPECT().GetByUID(gomock.Any(), uid).Return(expectedOrder, nil)
+	mockCache.EXPECT().Set(gomock.Any(), expectedOrder).Return(errors.New("redis down"))
 
 	order, err := uc.Execute(ctx, uid)
 
@@ -154,10 +159,88 @@ func TestGetOrderUseCase_Execute_DBError(t *testing.T) {
 	uid := "test-order"
 
 	mockCache.EXPECT().Get(ctx, uid).Return(nil, errors.New("miss"))
-	mockRepo.EXPECT().GetByUID(ctx, uid).Return(nil, errors.New("db connection lost"))
+	mockRepo.EXPECT().GetByUID(gomock.Any(), uid).Return(nil, errors.New("db connection lost"))
 
 	order, err := uc.Execute(ctx, uid)
 
 	require.Error(t, err)
 	require.Nil(t, order)
 }
+
+func TestGetOrderUseCase_Execute_KnownMissing(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCache := mocks.NewMockOrderCache(ctrl)
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+
+	uc := NewGetOrderUseCase(mockRepo, mockCache, zap.NewNop())
+
+	ctx := context.Background()
+	mockCache.EXPECT().Get(ctx, "probed-uid").Return(nil, model.ErrOrderNotFound)
+	mockRepo.EXPECT().GetByUID(gomock.Any(), gomock.Any()).Times(0)
+
+	order, err := uc.Execute(ctx, "probed-uid")
+
+	require.ErrorIs(t, err, model.ErrOrderNotFound)
+	assert.Nil(t, order)
+}
+
+func TestGetOrderUseCase_Execute_CoalescesConcurrentMisses(t *testing.T) {
+	t.Parallel()
+
+	// The bubble lets the test wait until every caller is parked on the
+	// shared lookup instead of guessing how long that takes.
+	synctest.Test(t, func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockCache := mocks.NewMockOrderCache(ctrl)
+		mockRepo := mocks.NewMockOrderRepository(ctrl)
+
+		uc := NewGetOrderUseCase(mockRepo, mockCache, zap.NewNop())
+
+		const callers = 10
+		uid := "hot-order"
+		expectedOrder := &model.Order{OrderUID: uid}
+		release := make(chan struct{})
+
+		mockCache.EXPECT().Get(gomock.Any(), uid).Return(nil, nil).Times(callers)
+		mockRepo.EXPECT().GetByUID(gomock.Any(), uid).DoAndReturn(func(context.Context, string) (*model.Order, error) {
+			<-release
+			return expectedOrder, nil
+		}).Times(1)
+		mockCache.EXPECT().Set(gomock.Any(), expectedOrder).Return(nil).Times(1)
+
+		ctx, cancelFirst := context.WithCancel(context.Background())
+		results := make(chan error, callers)
+		for i := range callers {
+			callerCtx := context.Background()
+			if i == 0 {
+				callerCtx = ctx
+			}
+			go func() {
+				order, err := uc.Execute(callerCtx, uid)
+				if err == nil && order.OrderUID != uid {
+					err = errors.New("unexpected order")
+				}
+				results <- err
+			}()
+		}
+		// Every caller has missed the cache and is waiting on the one
+		// lookup, which is blocked on release.
+		synctest.Wait()
+
+		// The caller whose request started the lookup leaving must not fail
+		// the others.
+		cancelFirst()
+		require.ErrorIs(t, <-results, context.Canceled)
+		close(release)
+
+		for range callers - 1 {
+			assert.NoError(t, <-results)
+		}
+	})
+}
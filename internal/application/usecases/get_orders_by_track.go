@@ -0,0 +1,57 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"l0/internal/domain/model"
+	"l0/internal/domain/repository"
+
+	"go.uber.org/zap"
+)
+
+type GetOrdersByTrackUseCase struct {
+	orderRepo  repository.OrderRepository
+	orderCache repository.OrderCache
+	logger     *zap.Logger
+}
+
+func NewGetOrdersByTrackUseCase(repo repository.OrderRepository, cache repository.OrderCache, logger *zap.Logger) *GetOrdersByTrackUseCase {
+	return &GetOrdersByTrackUseCase{orderRepo: repo, orderCache: cache, logger: logger}
+}
+
+// Execute finds the orders a track number belongs to, either as the order's
+// own track number or as one of its items'. Unknown track numbers are cached
+// too, so repeated lookups for them don't reach the DB until an order with
+// that track number is saved.
+func (uc *GetOrdersByTrackUseCase) Execute(ctx context.Context, trackNumber string) ([]*model.Order, error) {
+	orders, err := uc.orderCache.GetByTrack(ctx, trackNumber)
+	if err != nil {
+		uc.logger.Warn("Failed to get track index from cache", zap.Error(err), zap.String("track_number", trackNumber))
+	}
+	if err == nil && orders != nil {
+		uc.logger.Debug("Orders by track retrieved from cache", zap.String("track_number", trackNumber))
+		return foundOrNotFound(orders)
+	}
+
+	orders, err = uc.orderRepo.GetByTrackNumber(ctx, trackNumber)
+	if err != nil {
+		uc.logger.Error("Failed to get orders by track from DB", zap.Error(err), zap.String("track_number", trackNumber))
+		return nil, fmt.Errorf("failed to get orders by track from DB: %w", err)
+	}
+
+	if err := uc.orderCache.SetByTrack(ctx, trackNumber, orders); err != nil {
+		uc.logger.Warn("Failed to update track index, continuing", zap.Error(err), zap.String("track_number", trackNumber))
+	}
+
+	uc.logger.Debug("Orders by track retrieved from DB",
+		zap.String("track_number", trackNumber), zap.Int("orders_count", len(orders)))
+	return foundOrNotFound(orders)
+}
+
+func foundOrNotFound(orders []*model.Order) ([]*model.Order, error) {
+	if len(orders) == 0 {
+		return nil, model.ErrOrderNotFound
+	}
+	return orders, nil
+}
@@ -0,0 +1,100 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"l0/internal/domain/model"
+	"l0/internal/domain/repository/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestGetOrdersByTrackUseCase_Execute(t *testing.T) {
+	t.Parallel()
+
+	const track = "WBILMTESTTRACK"
+	orders := []*model.Order{{OrderUID: "order-1", TrackNumber: track}}
+
+	tests := []struct {
+		name        string
+		setupMocks  func(repo *mocks.MockOrderRepository, cache *mocks.MockOrderCache)
+		wantOrders  []*model.Order
+		expectedErr error
+	}{
+		{
+			name: "cache hit",
+			setupMocks: func(repo *mocks.MockOrderRepository, cache *mocks.MockOrderCache) {
+				cache.EXPECT().GetByTrack(gomock.Any(), track).Return(orders, nil)
+				repo.EXPECT().GetByTrackNumber(gomock.Any(), gomock.Any()).Times(0)
+			},
+			wantOrders: orders,
+		},
+		{
+			name: "cached unknown track",
+			setupMocks: func(repo *mocks.MockOrderRepository, cache *mocks.MockOrderCache) {
+				cache.EXPECT().GetByTrack(gomock.Any(), track).Return([]*model.Order{}, nil)
+				repo.EXPECT().GetByTrackNumber(gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedErr: model.ErrOrderNotFound,
+		},
+		{
+			name: "cache miss loads from DB and fills index",
+			setupMocks: func(repo *mocks.MockOrderRepository, cache *mocks.MockOrderCache) {
+				cache.EXPECT().GetByTrack(gomock.Any(), track).Return(nil, nil)
+				repo.EXPECT().GetByTrackNumber(gomock.Any(), track).Return(orders, nil)
+				cache.EXPECT().SetByTrack(gomock.Any(), track, orders).Return(nil)
+			},
+			wantOrders: orders,
+		},
+		{
+			name: "cache error falls back to DB, unknown track is indexed",
+			setupMocks: func(repo *mocks.MockOrderRepository, cache *mocks.MockOrderCache) {
+				cache.EXPECT().GetByTrack(gomock.Any(), track).Return(nil, errors.New("redis timeout"))
+				repo.EXPECT().GetByTrackNumber(gomock.Any(), track).Return([]*model.Order{}, nil)
+				cache.EXPECT().SetByTrack(gomock.Any(), track, []*model.Order{}).Return(errors.New("redis timeout"))
+			},
+			expectedErr: model.ErrOrderNotFound,
+		},
+		{
+			name: "DB error",
+			setupMocks: func(repo *mocks.MockOrderRepository, cache *mocks.MockOrderCache) {
+				cache.EXPECT().GetByTrack(gomock.Any(), track).Return(nil, nil)
+				repo.EXPECT().GetByTrackNumber(gomock.Any(), track).Return(nil, errors.New("connection refused"))
+				cache.EXPECT().SetByTrack(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedErr: errors.New("failed to get orders by track from DB: connection refused"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockRepo := mocks.NewMockOrderRepository(ctrl)
+			mockCache := mocks.NewMockOrderCache(ctrl)
+			tt.setupMocks(mockRepo, mockCache)
+
+			uc := NewGetOrdersByTrackUseCase(mockRepo, mockCache, zap.NewNop())
+			got, err := uc.Execute(context.Background(), track)
+
+			if tt.expectedErr != nil {
+				require.Error(t, err)
+				if errors.Is(tt.expectedErr, model.ErrOrderNotFound) {
+					assert.ErrorIs(t, err, model.ErrOrderNotFound)
+				} else {
+					assert.EqualError(t, err, tt.expectedErr.Error())
+				}
+				assert.Nil(t, got)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantOrders, got)
+		})
+	}
+}
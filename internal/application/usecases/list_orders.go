@@ -0,0 +1,56 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+
+	"l0/internal/domain/model"
+	"l0/internal/domain/repository"
+
+	"go.uber.org/zap"
+)
+
+const (
+	DefaultListLimit = 20
+	MaxListLimit     = 100
+)
+
+type ListOrdersUseCase struct {
+	orderRepo repository.OrderRepository
+	logger    *zap.Logger
+}
+
+func NewListOrdersUseCase(repo repository.OrderRepository, logger *zap.Logger) *ListOrdersUseCase {
+	return &ListOrdersUseCase{orderRepo: repo, logger: logger}
+}
+
+// Execute returns one page of orders. It asks the repository for one extra
+// row to learn whether another page exists without a separate count query.
+func (uc *ListOrdersUseCase) Execute(ctx context.Context, filter model.OrderFilter) (*model.OrderPage, error) {
+	switch {
+	case filter.Limit <= 0:
+		filter.Limit = DefaultListLimit
+	case filter.Limit > MaxListLimit:
+		filter.Limit = MaxListLimit
+	}
+	limit := filter.Limit
+	filter.Limit++
+
+	orders, err := uc.orderRepo.List(ctx, filter)
+	if err != nil {
+		uc.logger.Error("Failed to list orders from DB", zap.Error(err))
+		return nil, fmt.Errorf("failed to list orders from DB: %w", err)
+	}
+
+	page := &model.OrderPage{Orders: orders}
+	if len(orders) > limit {
+		page.Orders = orders[:limit]
+		cursor, err := model.CursorOf(page.Orders[limit-1])
+		if err != nil {
+			return nil, err
+		}
+		page.NextCursor = &cursor
+	}
+
+	return page, nil
+}
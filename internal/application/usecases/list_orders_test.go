@@ -0,0 +1,100 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"l0/internal/domain/model"
+	"l0/internal/domain/repository/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestListOrdersUseCase_Execute(t *testing.T) {
+	t.Parallel()
+
+	orders := func(n int) []*model.Order {
+		out := make([]*model.Order, n)
+		for i := range out {
+			out[i] = &model.Order{
+				OrderUID:    "order-" + string(rune('a'+i)),
+				DateCreated: time.Date(2021, 11, 26, 6, 22, 19-i, 0, time.UTC).Format(time.RFC3339Nano),
+			}
+		}
+		return out
+	}
+
+	tests := []struct {
+		name       string
+		limit      int
+		repoLimit  int
+		returned   int
+		wantLen    int
+		wantCursor *model.OrderCursor
+	}{
+		{
+			name:      "default limit, last page",
+			limit:     0,
+			repoLimit: DefaultListLimit + 1,
+			returned:  3,
+			wantLen:   3,
+		},
+		{
+			name:      "more rows than limit yields cursor",
+			limit:     2,
+			repoLimit: 3,
+			returned:  3,
+			wantLen:   2,
+			wantCursor: &model.OrderCursor{
+				DateCreated: time.Date(2021, 11, 26, 6, 22, 18, 0, time.UTC),
+				OrderUID:    "order-b",
+			},
+		},
+		{
+			name:      "limit is capped",
+			limit:     MaxListLimit * 10,
+			repoLimit: MaxListLimit + 1,
+			returned:  0,
+			wantLen:   0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockRepo := mocks.NewMockOrderRepository(ctrl)
+			uc := NewListOrdersUseCase(mockRepo, zap.NewNop())
+
+			mockRepo.EXPECT().
+				List(gomock.Any(), model.OrderFilter{CustomerID: "cust-1", Limit: tt.repoLimit}).
+				Return(orders(tt.returned), nil)
+
+			page, err := uc.Execute(context.Background(), model.OrderFilter{CustomerID: "cust-1", Limit: tt.limit})
+			require.NoError(t, err)
+			assert.Len(t, page.Orders, tt.wantLen)
+			assert.Equal(t, tt.wantCursor, page.NextCursor)
+		})
+	}
+}
+
+func TestListOrdersUseCase_Execute_RepoError(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+	uc := NewListOrdersUseCase(mockRepo, zap.NewNop())
+
+	dbErr := errors.New("connection refused")
+	mockRepo.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil, dbErr)
+
+	page, err := uc.Execute(context.Background(), model.OrderFilter{})
+	require.ErrorIs(t, err, dbErr)
+	assert.Nil(t, page)
+}
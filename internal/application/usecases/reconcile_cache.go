@@ -0,0 +1,125 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"l0/internal/domain/model"
+	"l0/internal/domain/repository"
+
+	"go.uber.org/zap"
+)
+
+const DefaultReconcileWindow = time.Hour
+
+type ReconcileOptions struct {
+	// Window is how far back a pass looks for orders stored or changed. It
+	// should span a few intervals, so an order a pass failed on is checked
+	// again by the next one.
+	Window    time.Duration
+	ChunkSize int
+	// OnPass, if set, is called after every completed pass.
+	OnPass func(model.ReconcileResult)
+}
+
+// ReconcileCacheUseCase catches the cache writes that the repair queue never
+// got to: those lost to a restart or a full queue, or given up on. It walks
+// the orders stored or changed within the window and compares them with the
+// cache. Missing orders are filled in, and orders cached with an outdated
+// status go to the repair queue, which rewrites them from a fresh DB read.
+// Orders don't change after they are stored except for their status, so that
+// is all a cached copy is checked for. Older orders are left alone, as they
+// are meant to expire from the cache. The cache is only peeked at, so a pass
+// keeps no order cached longer than requests would.
+type ReconcileCacheUseCase struct {
+	orderRepo  repository.OrderRepository
+	orderCache repository.OrderCacheInspector
+	repairs    repository.CacheRepairQueue
+	logger     *zap.Logger
+}
+
+func NewReconcileCacheUseCase(orderRepo repository.OrderRepository, orderCache repository.OrderCacheInspector, repairs repository.CacheRepairQueue, logger *zap.Logger) *ReconcileCacheUseCase {
+	return &ReconcileCacheUseCase{orderRepo: orderRepo, orderCache: orderCache, repairs: repairs, logger: logger}
+}
+
+// Run reconciles every interval until ctx is cancelled.
+func (uc *ReconcileCacheUseCase) Run(ctx context.Context, interval time.Duration, opts ReconcileOptions) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if _, err := uc.Execute(ctx, opts); err != nil && ctx.Err() == nil {
+			uc.logger.Warn("Cache reconciliation failed", zap.Error(err))
+		}
+	}
+}
+
+func (uc *ReconcileCacheUseCase) Execute(ctx context.Context, opts ReconcileOptions) (model.ReconcileResult, error) {
+	window := opts.Window
+	if window <= 0 {
+		window = DefaultReconcileWindow
+	}
+	filter := model.OrderFilter{ChangedFrom: time.Now().Add(-window), Limit: opts.ChunkSize}
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultRestoreChunkSize
+	}
+
+	var result model.ReconcileResult
+	err := uc.orderRepo.Iterate(ctx, filter, func(chunk []*model.Order, _ model.OrderCursor) error {
+		var missing []*model.Order
+		for _, order := range chunk {
+			cached, err := uc.orderCache.Peek(ctx, order.OrderUID)
+			switch {
+			case errors.Is(err, model.ErrOrderNotFound):
+				uc.stale(order.OrderUID, &result)
+			case err != nil:
+				return fmt.Errorf("failed to read order %s from cache: %w", order.OrderUID, err)
+			case cached == nil:
+				missing = append(missing, order)
+			case cached.Status != order.Status:
+				uc.stale(order.OrderUID, &result)
+			}
+		}
+
+		// Fill leaves cached orders alone, so an order cached in the
+		// meantime is not overwritten with the copy read here.
+		if len(missing) > 0 {
+			if err := uc.orderCache.Fill(ctx, missing); err != nil {
+				return fmt.Errorf("failed to write orders to cache: %w", err)
+			}
+			for _, order := range missing {
+				uc.logger.Info("Order missing from cache, filled in", zap.String("order_uid", order.OrderUID))
+			}
+		}
+		result.Missing += len(missing)
+		result.Checked += len(chunk)
+		return nil
+	})
+	if err != nil {
+		return result, fmt.Errorf("cache reconciliation interrupted after %d orders: %w", result.Checked, err)
+	}
+
+	if result.Missing > 0 || result.Stale > 0 {
+		uc.logger.Info("Cache reconciled",
+			zap.Int("checked", result.Checked), zap.Int("missing", result.Missing), zap.Int("stale", result.Stale))
+	} else {
+		uc.logger.Debug("Cache reconciled", zap.Int("checked", result.Checked))
+	}
+	if opts.OnPass != nil {
+		opts.OnPass(result)
+	}
+	return result, nil
+}
+
+func (uc *ReconcileCacheUseCase) stale(orderUID string, result *model.ReconcileResult) {
+	uc.logger.Info("Cached order out of date, queued for repair", zap.String("order_uid", orderUID))
+	uc.repairs.Enqueue(orderUID)
+	result.Stale++
+}
@@ -0,0 +1,83 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"l0/internal/domain/model"
+	"l0/internal/domain/repository/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestReconcileCacheUseCase_Execute(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+	mockCache := mocks.NewMockOrderCacheInspector(ctrl)
+	mockRepairs := mocks.NewMockCacheRepairQueue(ctrl)
+	uc := NewReconcileCacheUseCase(mockRepo, mockCache, mockRepairs, zap.NewNop())
+
+	inSync := &model.Order{OrderUID: "in-sync", Status: model.OrderStatusPaid}
+	missing := &model.Order{OrderUID: "missing", Status: model.OrderStatusCreated}
+	outdated := &model.Order{OrderUID: "outdated", Status: model.OrderStatusShipped}
+	markedMissing := &model.Order{OrderUID: "marked-missing", Status: model.OrderStatusCreated}
+
+	start := time.Now()
+	mockRepo.EXPECT().Iterate(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, filter model.OrderFilter, fn iterateFn) error {
+			assert.Equal(t, 2, filter.Limit)
+			assert.WithinDuration(t, start.Add(-10*time.Minute), filter.ChangedFrom, time.Second)
+			assert.True(t, filter.CreatedFrom.IsZero(), "orders are picked by when they changed, not when they were created")
+			return iterateChunks([]*model.Order{inSync, missing}, []*model.Order{outdated, markedMissing})(ctx, filter, fn)
+		})
+	mockCache.EXPECT().Peek(gomock.Any(), "in-sync").Return(&model.Order{OrderUID: "in-sync", Status: model.OrderStatusPaid}, nil)
+	mockCache.EXPECT().Peek(gomock.Any(), "missing").Return(nil, nil)
+	mockCache.EXPECT().Peek(gomock.Any(), "outdated").Return(&model.Order{OrderUID: "outdated", Status: model.OrderStatusPaid}, nil)
+	mockCache.EXPECT().Peek(gomock.Any(), "marked-missing").Return(nil, model.ErrOrderNotFound)
+	mockCache.EXPECT().Fill(gomock.Any(), []*model.Order{missing}).Return(nil)
+	mockRepairs.EXPECT().Enqueue("outdated")
+	mockRepairs.EXPECT().Enqueue("marked-missing")
+
+	var passes []model.ReconcileResult
+	result, err := uc.Execute(context.Background(), ReconcileOptions{
+		Window:    10 * time.Minute,
+		ChunkSize: 2,
+		OnPass:    func(r model.ReconcileResult) { passes = append(passes, r) },
+	})
+
+	require.NoError(t, err)
+	want := model.ReconcileResult{Checked: 4, Missing: 1, Stale: 2}
+	assert.Equal(t, want, result)
+	assert.Equal(t, []model.ReconcileResult{want}, passes)
+}
+
+func TestReconcileCacheUseCase_Execute_CacheUnavailable(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+	mockCache := mocks.NewMockOrderCacheInspector(ctrl)
+	uc := NewReconcileCacheUseCase(mockRepo, mockCache, mocks.NewMockCacheRepairQueue(ctrl), zap.NewNop())
+
+	order := &model.Order{OrderUID: "order-1"}
+	mockRepo.EXPECT().Iterate(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(iterateChunks([]*model.Order{order}, []*model.Order{{OrderUID: "order-2"}}))
+	mockCache.EXPECT().Peek(gomock.Any(), "order-1").Return(nil, errors.New("redis down"))
+
+	_, err := uc.Execute(context.Background(), ReconcileOptions{
+		OnPass: func(model.ReconcileResult) { t.Error("an interrupted pass must not be reported") },
+	})
+
+	assert.Error(t, err)
+}
@@ -3,37 +3,193 @@ package usecases
 import (
 	"context"
 	"fmt"
+	"sync"
+	"time"
+
+	"l0/internal/domain/model"
 	"l0/internal/domain/repository"
 
 	"go.uber.org/zap"
 )
 
+const (
+	DefaultRestoreChunkSize = 500
+	DefaultRestoreWorkers   = 4
+	// restoreLogEvery keeps the restore log readable on large tables.
+	restoreLogEvery = 20
+)
+
+// RestoreOptions bounds a cache restore.
+type RestoreOptions struct {
+	// Since skips orders created before it; zero restores every order. A
+	// restore only resumes from a checkpoint taken with the same Since, so
+	// callers deriving it from the clock should round it.
+	Since     time.Time
+	ChunkSize int
+	// Workers bounds how many chunks are written to the cache at once.
+	Workers int
+	// OnProgress, if set, is called after every chunk written to the cache.
+	OnProgress func(model.RestoreProgress)
+}
+
+// RestoreCacheUseCase streams orders from the DB into the cache newest first.
+// Chunks are read one after another and written by up to Workers goroutines,
+// and the checkpoint only ever moves past chunks that are written along with
+// every chunk before them. A restore interrupted by a shutdown or an error
+// resumes from that checkpoint on the next run.
 type RestoreCacheUseCase struct {
-	orderRepo  repository.OrderRepository
-	orderCache repository.OrderCache
-	logger     *zap.Logger
+	orderRepo   repository.OrderRepository
+	orderCache  repository.OrderCache
+	checkpoints repository.RestoreCheckpointStore
+	logger      *zap.Logger
 }
 
-func NewRestoreCacheUseCase(orderRepo repository.OrderRepository, orderCache repository.OrderCache, logger *zap.Logger) *RestoreCacheUseCase {
-	return &RestoreCacheUseCase{orderRepo: orderRepo, orderCache: orderCache, logger: logger}
+func NewRestoreCacheUseCase(orderRepo repository.OrderRepository, orderCache repository.OrderCache, checkpoints repository.RestoreCheckpointStore, logger *zap.Logger) *RestoreCacheUseCase {
+	return &RestoreCacheUseCase{orderRepo: orderRepo, orderCache: orderCache, checkpoints: checkpoints, logger: logger}
 }
 
-func (uc *RestoreCacheUseCase) Execute(ctx context.Context) error {
-	orders, err := uc.orderRepo.GetAll(ctx)
+func (uc *RestoreCacheUseCase) Execute(ctx context.Context, opts RestoreOptions) error {
+	filter := model.OrderFilter{CreatedFrom: opts.Since, Limit: opts.ChunkSize}
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultRestoreChunkSize
+	}
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = DefaultRestoreWorkers
+	}
+
+	tracker := &restoreTracker{uc: uc, since: opts.Since, onProgress: opts.OnProgress}
+	checkpoint, err := uc.checkpoints.Load(ctx, opts.Since)
 	if err != nil {
-		uc.logger.Error("Failed to get orders from DB for cache restore", zap.Error(err))
-		return fmt.Errorf("failed to get orders from DB: %w", err)
+		// Starting over is only slower: the fill never overwrites cached orders.
+		uc.logger.Warn("Failed to load cache restore checkpoint, restoring from the start", zap.Error(err))
 	}
+	if checkpoint != nil {
+		filter.Cursor = &checkpoint.Cursor
+		tracker.progress = model.RestoreProgress{Restored: checkpoint.Restored, Cursor: checkpoint.Cursor, Resumed: true}
+		uc.logger.Info("Resuming cache restore",
+			zap.Int("restored", checkpoint.Restored), zap.String("after_order_uid", checkpoint.Cursor.OrderUID))
+	}
+
+	fillCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	// Checkpoints are saved even while shutting down, so the chunks written
+	// before the shutdown are not restored again.
+	saveCtx := context.WithoutCancel(ctx)
 
-	successCount := 0
-	for _, order := range orders {
-		if err := uc.orderCache.Set(ctx, order); err != nil {
-			uc.logger.Error("Failed to save order to cache", zap.Error(err), zap.String("order_uid", order.OrderUID))
-			continue
+	sem := make(chan struct{}, workers)
+	wg := &sync.WaitGroup{}
+	err = uc.orderRepo.Iterate(fillCtx, filter, func(chunk []*model.Order, last model.OrderCursor) error {
+		select {
+		case sem <- struct{}{}:
+		case <-fillCtx.Done():
+			return context.Cause(fillCtx)
 		}
-		successCount++
+
+		pending := tracker.dispatch(len(chunk), last)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := uc.orderCache.Fill(fillCtx, chunk); err != nil {
+				cancel(fmt.Errorf("failed to write orders to cache: %w", err))
+				return
+			}
+			tracker.complete(saveCtx, pending)
+		}()
+		return nil
+	})
+	wg.Wait()
+	// A failed fill cancels fillCtx, which the walk may report as a plain
+	// cancellation; the cause says what actually went wrong.
+	if cause := context.Cause(fillCtx); cause != nil {
+		err = cause
+	}
+
+	progress := tracker.snapshot()
+	if err != nil {
+		uc.logger.Error("Cache restore interrupted", zap.Error(err), zap.Int("restored", progress.Restored))
+		return fmt.Errorf("cache restore interrupted after %d orders: %w", progress.Restored, err)
 	}
 
-	uc.logger.Info("Cache restored", zap.Int("success_count", successCount), zap.Int("total_count", len(orders)))
+	if err := uc.checkpoints.Clear(ctx, opts.Since); err != nil {
+		uc.logger.Warn("Failed to clear cache restore checkpoint", zap.Error(err))
+	}
+
+	uc.logger.Info("Cache restored",
+		zap.Int("restored", progress.Restored), zap.Int("chunks", progress.Chunks), zap.Bool("resumed", progress.Resumed))
 	return nil
 }
+
+type restoreChunk struct {
+	size int
+	last model.OrderCursor
+	done bool
+}
+
+// restoreTracker turns chunks finishing in any order into progress that only
+// moves forward over a contiguous prefix of the walk.
+type restoreTracker struct {
+	uc         *RestoreCacheUseCase
+	since      time.Time
+	onProgress func(model.RestoreProgress)
+
+	mu       sync.Mutex
+	inFlight []*restoreChunk
+	progress model.RestoreProgress
+}
+
+func (t *restoreTracker) dispatch(size int, last model.OrderCursor) *restoreChunk {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	chunk := &restoreChunk{size: size, last: last}
+	t.inFlight = append(t.inFlight, chunk)
+	return chunk
+}
+
+// complete marks chunk as written and, if that extends the written prefix,
+// saves the checkpoint and reports progress. It holds the lock while saving
+// so checkpoints are written in order.
+func (t *restoreTracker) complete(ctx context.Context, chunk *restoreChunk) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	chunk.done = true
+	advanced := false
+	for len(t.inFlight) > 0 && t.inFlight[0].done {
+		written := t.inFlight[0]
+		t.inFlight = t.inFlight[1:]
+		t.progress.Restored += written.size
+		t.progress.Chunks++
+		t.progress.Cursor = written.last
+		advanced = true
+
+		if t.progress.Chunks%restoreLogEvery == 0 {
+			t.uc.logger.Info("Cache restore in progress",
+				zap.Int("restored", t.progress.Restored), zap.Int("chunks", t.progress.Chunks))
+		}
+	}
+	if !advanced {
+		return
+	}
+
+	if err := t.uc.checkpoints.Save(ctx, &model.RestoreCheckpoint{
+		Since:     t.since,
+		Cursor:    t.progress.Cursor,
+		Restored:  t.progress.Restored,
+		UpdatedAt: time.Now(),
+	}); err != nil {
+		t.uc.logger.Warn("Failed to save cache restore checkpoint", zap.Error(err))
+	}
+	if t.onProgress != nil {
+		t.onProgress(t.progress)
+	}
+}
+
+func (t *restoreTracker) snapshot() model.RestoreProgress {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.progress
+}
@@ -4,62 +4,107 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"l0/internal/domain/model"
 	"l0/internal/domain/repository/mocks"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 	"go.uber.org/zap"
 )
 
+type iterateFn = func([]*model.Order, model.OrderCursor) error
+
+// iterateChunks makes an Iterate mock hand chunks to fn, stopping at fn's
+// first error like the real repository does.
+func iterateChunks(chunks ...[]*model.Order) func(context.Context, model.OrderFilter, iterateFn) error {
+	return func(_ context.Context, _ model.OrderFilter, fn iterateFn) error {
+		for _, chunk := range chunks {
+			last := model.OrderCursor{OrderUID: chunk[len(chunk)-1].OrderUID}
+			if err := fn(chunk, last); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
 func TestRestoreCacheUseCase_Success(t *testing.T) {
 	t.Parallel()
 
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	chunk1 := []*model.Order{{OrderUID: "order-1"}, {OrderUID: "order-2"}}
+	chunk2 := []*model.Order{{OrderUID: "order-3"}}
+
 	tests := []struct {
-		name       string
-		setupMocks func(*mocks.MockOrderRepository, *mocks.MockOrderCache, context.Context)
+		name         string
+		opts         RestoreOptions
+		setupMocks   func(*mocks.MockOrderRepository, *mocks.MockOrderCache, *mocks.MockRestoreCheckpointStore)
+		wantProgress []model.RestoreProgress
 	}{
 		{
-			name: "restores_all_orders",
-			setupMocks: func(repo *mocks.MockOrderRepository, cache *mocks.MockOrderCache, ctx context.Context) {
-				orders := []*model.Order{
-					{OrderUID: "order-1", TrackNumber: "TRACK-1"},
-					{OrderUID: "order-2", TrackNumber: "TRACK-2"},
-					{OrderUID: "order-3", TrackNumber: "TRACK-3"},
-				}
-				repo.EXPECT().GetAll(ctx).Return(orders, nil)
-				cache.EXPECT().Set(ctx, gomock.Any()).Return(nil).Times(3)
+			name: "restores_in_chunks",
+			opts: RestoreOptions{Since: since, ChunkSize: 2, Workers: 1},
+			setupMocks: func(repo *mocks.MockOrderRepository, cache *mocks.MockOrderCache, store *mocks.MockRestoreCheckpointStore) {
+				store.EXPECT().Load(gomock.Any(), since).Return(nil, nil)
+				repo.EXPECT().Iterate(gomock.Any(), model.OrderFilter{CreatedFrom: since, Limit: 2}, gomock.Any()).
+					DoAndReturn(iterateChunks(chunk1, chunk2))
+				cache.EXPECT().Fill(gomock.Any(), chunk1).Return(nil)
+				cache.EXPECT().Fill(gomock.Any(), chunk2).Return(nil)
+				store.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, checkpoint *model.RestoreCheckpoint) error {
+						assert.Equal(t, since, checkpoint.Since)
+						return nil
+					}).Times(2)
+				store.EXPECT().Clear(gomock.Any(), since).Return(nil)
+			},
+			wantProgress: []model.RestoreProgress{
+				{Restored: 2, Chunks: 1, Cursor: model.OrderCursor{OrderUID: "order-2"}},
+				{Restored: 3, Chunks: 2, Cursor: model.OrderCursor{OrderUID: "order-3"}},
 			},
 		},
 		{
-			name: "empty_database",
-			setupMocks: func(repo *mocks.MockOrderRepository, cache *mocks.MockOrderCache, ctx context.Context) {
-				repo.EXPECT().GetAll(ctx).Return([]*model.Order{}, nil)
+			name: "resumes_from_checkpoint",
+			setupMocks: func(repo *mocks.MockOrderRepository, cache *mocks.MockOrderCache, store *mocks.MockRestoreCheckpointStore) {
+				checkpoint := &model.RestoreCheckpoint{Cursor: model.OrderCursor{OrderUID: "order-2"}, Restored: 2}
+				store.EXPECT().Load(gomock.Any(), gomock.Any()).Return(checkpoint, nil)
+				repo.EXPECT().Iterate(gomock.Any(), model.OrderFilter{Limit: DefaultRestoreChunkSize, Cursor: &checkpoint.Cursor}, gomock.Any()).
+					DoAndReturn(iterateChunks(chunk2))
+				cache.EXPECT().Fill(gomock.Any(), chunk2).Return(nil)
+				store.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, checkpoint *model.RestoreCheckpoint) error {
+						assert.Equal(t, 3, checkpoint.Restored)
+						assert.Equal(t, "order-3", checkpoint.Cursor.OrderUID)
+						return nil
+					})
+				store.EXPECT().Clear(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			wantProgress: []model.RestoreProgress{
+				{Restored: 3, Chunks: 1, Cursor: model.OrderCursor{OrderUID: "order-3"}, Resumed: true},
 			},
 		},
 		{
-			name: "partial_cache_failure",
-			setupMocks: func(repo *mocks.MockOrderRepository, cache *mocks.MockOrderCache, ctx context.Context) {
-				orders := []*model.Order{
-					{OrderUID: "order-1"},
-					{OrderUID: "order-2"},
-					{OrderUID: "order-3"},
-				}
-				repo.EXPECT().GetAll(ctx).Return(orders, nil)
-				cache.EXPECT().Set(ctx, gomock.Any()).Return(nil).Times(2)
-				cache.EXPECT().Set(ctx, gomock.Any()).Return(errors.New("redis timeout")).Times(1)
+			name: "unreadable_checkpoint_starts_over",
+			setupMocks: func(repo *mocks.MockOrderRepository, cache *mocks.MockOrderCache, store *mocks.MockRestoreCheckpointStore) {
+				store.EXPECT().Load(gomock.Any(), gomock.Any()).Return(nil, errors.New("redis timeout"))
+				repo.EXPECT().Iterate(gomock.Any(), model.OrderFilter{Limit: DefaultRestoreChunkSize}, gomock.Any()).
+					DoAndReturn(iterateChunks(chunk1))
+				cache.EXPECT().Fill(gomock.Any(), chunk1).Return(nil)
+				store.EXPECT().Save(gomock.Any(), gomock.Any()).Return(errors.New("redis timeout"))
+				store.EXPECT().Clear(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			wantProgress: []model.RestoreProgress{
+				{Restored: 2, Chunks: 1, Cursor: model.OrderCursor{OrderUID: "order-2"}},
 			},
 		},
 		{
-			name: "all_cache_writes_fail",
-			setupMocks: func(repo *mocks.MockOrderRepository, cache *mocks.MockOrderCache, ctx context.Context) {
-				orders := []*model.Order{
-					{OrderUID: "order-1"},
-					{OrderUID: "order-2"},
-				}
-				repo.EXPECT().GetAll(ctx).Return(orders, nil)
-				cache.EXPECT().Set(ctx, gomock.Any()).Return(errors.New("redis down")).Times(2)
+			name: "empty_database",
+			setupMocks: func(repo *mocks.MockOrderRepository, cache *mocks.MockOrderCache, store *mocks.MockRestoreCheckpointStore) {
+				store.EXPECT().Load(gomock.Any(), gomock.Any()).Return(nil, nil)
+				repo.EXPECT().Iterate(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(iterateChunks())
+				store.EXPECT().Clear(gomock.Any(), gomock.Any()).Return(nil)
 			},
 		},
 	}
@@ -73,44 +118,102 @@ func TestRestoreCacheUseCase_Success(t *testing.T) {
 
 			mockRepo := mocks.NewMockOrderRepository(ctrl)
 			mockCache := mocks.NewMockOrderCache(ctrl)
-			logger := zap.NewNop()
+			mockStore := mocks.NewMockRestoreCheckpointStore(ctrl)
+			tt.setupMocks(mockRepo, mockCache, mockStore)
 
-			ctx := context.Background()
-			tt.setupMocks(mockRepo, mockCache, ctx)
+			var progress []model.RestoreProgress
+			opts := tt.opts
+			opts.OnProgress = func(p model.RestoreProgress) { progress = append(progress, p) }
 
-			uc := NewRestoreCacheUseCase(mockRepo, mockCache, logger)
-			err := uc.Execute(ctx)
+			uc := NewRestoreCacheUseCase(mockRepo, mockCache, mockStore, zap.NewNop())
+			err := uc.Execute(context.Background(), opts)
 
-			assert.NoError(t, err)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantProgress, progress)
 		})
 	}
 }
 
+func TestRestoreCacheUseCase_CheckpointWaitsForEarlierChunks(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+	mockCache := mocks.NewMockOrderCache(ctrl)
+	mockStore := mocks.NewMockRestoreCheckpointStore(ctrl)
+
+	chunk1 := []*model.Order{{OrderUID: "order-1"}, {OrderUID: "order-2"}}
+	chunk2 := []*model.Order{{OrderUID: "order-3"}}
+	chunk2Written := make(chan struct{})
+
+	mockStore.EXPECT().Load(gomock.Any(), gomock.Any()).Return(nil, nil)
+	mockRepo.EXPECT().Iterate(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(iterateChunks(chunk1, chunk2))
+	mockCache.EXPECT().Fill(gomock.Any(), chunk1).DoAndReturn(func(context.Context, []*model.Order) error {
+		<-chunk2Written
+		return nil
+	})
+	mockCache.EXPECT().Fill(gomock.Any(), chunk2).DoAndReturn(func(context.Context, []*model.Order) error {
+		close(chunk2Written)
+		return nil
+	})
+	mockStore.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, checkpoint *model.RestoreCheckpoint) error {
+			assert.Equal(t, 3, checkpoint.Restored)
+			assert.Equal(t, "order-3", checkpoint.Cursor.OrderUID)
+			return nil
+		})
+	mockStore.EXPECT().Clear(gomock.Any(), gomock.Any()).Return(nil)
+
+	var progress []model.RestoreProgress
+	uc := NewRestoreCacheUseCase(mockRepo, mockCache, mockStore, zap.NewNop())
+	err := uc.Execute(context.Background(), RestoreOptions{
+		Workers:    2,
+		OnProgress: func(p model.RestoreProgress) { progress = append(progress, p) },
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []model.RestoreProgress{
+		{Restored: 3, Chunks: 2, Cursor: model.OrderCursor{OrderUID: "order-3"}},
+	}, progress)
+}
+
 func TestRestoreCacheUseCase_Error(t *testing.T) {
 	t.Parallel()
 
+	chunk1 := []*model.Order{{OrderUID: "order-1"}}
+	chunk2 := []*model.Order{{OrderUID: "order-2"}}
+
 	tests := []struct {
 		name       string
-		setupMocks func(*mocks.MockOrderRepository, *mocks.MockOrderCache, context.Context)
-		ctx        context.Context
+		setupMocks func(*mocks.MockOrderRepository, *mocks.MockOrderCache, *mocks.MockRestoreCheckpointStore)
+		wantErr    error
 	}{
 		{
 			name: "database_unavailable",
-			setupMocks: func(repo *mocks.MockOrderRepository, cache *mocks.MockOrderCache, ctx context.Context) {
-				repo.EXPECT().GetAll(ctx).Return(nil, errors.New("database connection lost"))
+			setupMocks: func(repo *mocks.MockOrderRepository, cache *mocks.MockOrderCache, store *mocks.MockRestoreCheckpointStore) {
+				store.EXPECT().Load(gomock.Any(), gomock.Any()).Return(nil, nil)
+				repo.EXPECT().Iterate(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("database connection lost"))
+			},
+		},
+		{
+			name: "cache_write_fails_keeps_checkpoint",
+			setupMocks: func(repo *mocks.MockOrderRepository, cache *mocks.MockOrderCache, store *mocks.MockRestoreCheckpointStore) {
+				store.EXPECT().Load(gomock.Any(), gomock.Any()).Return(nil, nil)
+				repo.EXPECT().Iterate(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(iterateChunks(chunk1, chunk2))
+				cache.EXPECT().Fill(gomock.Any(), chunk1).Return(nil)
+				cache.EXPECT().Fill(gomock.Any(), chunk2).Return(errors.New("redis down"))
+				store.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil).Times(1)
 			},
-			ctx: context.Background(),
 		},
 		{
 			name: "context_canceled",
-			setupMocks: func(repo *mocks.MockOrderRepository, cache *mocks.MockOrderCache, ctx context.Context) {
-				repo.EXPECT().GetAll(ctx).Return(nil, context.Canceled)
+			setupMocks: func(repo *mocks.MockOrderRepository, cache *mocks.MockOrderCache, store *mocks.MockRestoreCheckpointStore) {
+				store.EXPECT().Load(gomock.Any(), gomock.Any()).Return(nil, nil)
+				repo.EXPECT().Iterate(gomock.Any(), gomock.Any(), gomock.Any()).Return(context.Canceled)
 			},
-			ctx: func() context.Context {
-				ctx, cancel := context.WithCancel(context.Background())
-				cancel()
-				return ctx
-			}(),
+			wantErr: context.Canceled,
 		},
 	}
 
@@ -123,14 +226,16 @@ func TestRestoreCacheUseCase_Error(t *testing.T) {
 
 			mockRepo := mocks.NewMockOrderRepository(ctrl)
 			mockCache := mocks.NewMockOrderCache(ctrl)
-			logger := zap.NewNop()
-
-			tt.setupMocks(mockRepo, mockCache, tt.ctx)
+			mockStore := mocks.NewMockRestoreCheckpointStore(ctrl)
+			tt.setupMocks(mockRepo, mockCache, mockStore)
 
-			uc := NewRestoreCacheUseCase(mockRepo, mockCache, logger)
-			err := uc.Execute(tt.ctx)
+			uc := NewRestoreCacheUseCase(mockRepo, mockCache, mockStore, zap.NewNop())
+			err := uc.Execute(context.Background(), RestoreOptions{})
 
-			assert.Error(t, err)
+			require.Error(t, err)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
 		})
 	}
 }
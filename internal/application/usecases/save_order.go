@@ -2,50 +2,129 @@ package usecases
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"l0/internal/application/validation"
 	"l0/internal/domain/model"
 	"l0/internal/domain/repository"
 
 	"go.uber.org/zap"
 )
 
+// SaveOrderUseCase stores new orders in the DB and then caches them. The DB
+// is the source of truth, so once an order is committed a failed cache write
+// does not fail the save: the order goes to the repair queue instead.
 type SaveOrderUseCase struct {
 	orderRepo  repository.OrderRepository
 	orderCache repository.OrderCache
-	validator  *validation.Validator
+	repairs    repository.CacheRepairQueue
+	validator  repository.OrderValidator
 	logger     *zap.Logger
 }
 
-func NewSaveOrderUseCase(orderRepo repository.OrderRepository, orderCache repository.OrderCache, validator *validation.Validator, logger *zap.Logger) *SaveOrderUseCase {
-	return &SaveOrderUseCase{orderRepo: orderRepo, orderCache: orderCache, validator: validator, logger: logger}
+func NewSaveOrderUseCase(orderRepo repository.OrderRepository, orderCache repository.OrderCache, repairs repository.CacheRepairQueue, validator repository.OrderValidator, logger *zap.Logger) *SaveOrderUseCase {
+	return &SaveOrderUseCase{orderRepo: orderRepo, orderCache: orderCache, repairs: repairs, validator: validator, logger: logger}
 }
 
 func (uc *SaveOrderUseCase) Execute(ctx context.Context, order *model.Order) error {
-	if err := uc.validator.ValidateOrder(*order); err != nil {
-		uc.logger.Warn("Order validation failed", zap.String("order_uid", order.OrderUID), zap.Error(err))
-		return fmt.Errorf("validation failed: %w", model.ErrInvalidOrderData)
+	if err := uc.validator.Validate(ctx, order); err != nil {
+		return uc.invalidOrder(order, err)
 	}
 
+	// The status is owned by the service, so whatever the producer sent is
+	// replaced.
+	order.Status = model.OrderStatusCreated
+
 	exists, err := uc.orderRepo.Exists(ctx, order.OrderUID)
 	if err != nil {
 		return fmt.Errorf("failed to check order existence: %w", err)
 	}
 	if exists {
 		uc.logger.Info("Order already exists, skipping", zap.String("order_uid", order.OrderUID))
+		// A redelivery may follow a save that stopped before caching.
+		uc.repairs.Enqueue(order.OrderUID)
 		return model.ErrOrderAlreadyExists
 	}
 
-	if err := uc.orderRepo.Save(ctx, order); err != nil {
+	err = uc.orderRepo.Save(ctx, order)
+	if errors.Is(err, model.ErrOrderAlreadyExists) {
+		// Another writer stored the same order after the Exists check.
+		uc.logger.Info("Order already exists, skipping", zap.String("order_uid", order.OrderUID))
+		uc.repairs.Enqueue(order.OrderUID)
+		return model.ErrOrderAlreadyExists
+	}
+	if err != nil {
 		uc.logger.Error("Failed to save order to DB", zap.Error(err), zap.String("order_uid", order.OrderUID))
 		return fmt.Errorf("failed to save order to DB: %w", err)
 	}
 
+	uc.cache(ctx, order)
+	uc.logger.Info("Order saved", zap.String("order_uid", order.OrderUID))
+	return nil
+}
+
+// ExecuteBatch validates and saves orders in a single repository call. The
+// returned slice holds the outcome of each order, mirroring Execute's errors.
+func (uc *SaveOrderUseCase) ExecuteBatch(ctx context.Context, orders []*model.Order) []error {
+	errs := make([]error, len(orders))
+
+	valid := make([]*model.Order, 0, len(orders))
+	validIdx := make([]int, 0, len(orders))
+	for i, order := range orders {
+		if err := uc.validator.Validate(ctx, order); err != nil {
+			errs[i] = uc.invalidOrder(order, err)
+			continue
+		}
+		order.Status = model.OrderStatusCreated
+		valid = append(valid, order)
+		validIdx = append(validIdx, i)
+	}
+	if len(valid) == 0 {
+		return errs
+	}
+
+	saveErrs := uc.orderRepo.SaveBatch(ctx, valid)
+	saved := 0
+	for j, order := range valid {
+		i := validIdx[j]
+		switch err := saveErrs[j]; {
+		case errors.Is(err, model.ErrOrderAlreadyExists):
+			uc.logger.Info("Order already exists, skipping", zap.String("order_uid", order.OrderUID))
+			uc.repairs.Enqueue(order.OrderUID)
+			errs[i] = model.ErrOrderAlreadyExists
+			continue
+		case err != nil:
+			uc.logger.Error("Failed to save order to DB", zap.Error(err), zap.String("order_uid", order.OrderUID))
+			errs[i] = fmt.Errorf("failed to save order to DB: %w", err)
+			continue
+		}
+
+		uc.cache(ctx, order)
+		saved++
+	}
+
+	uc.logger.Info("Order batch saved", zap.Int("saved", saved), zap.Int("batch_size", len(orders)))
+	return errs
+}
+
+// cache writes a committed order to the cache, handing it to the repair
+// queue if the write fails.
+func (uc *SaveOrderUseCase) cache(ctx context.Context, order *model.Order) {
 	if err := uc.orderCache.Set(ctx, order); err != nil {
-		uc.logger.Error("Failed to save order to cache", zap.Error(err), zap.String("order_uid", order.OrderUID))
-		return fmt.Errorf("failed to save order to cache: %w", err)
+		uc.logger.Warn("Failed to save order to cache, queued for repair", zap.Error(err), zap.String("order_uid", order.OrderUID))
+		uc.repairs.Enqueue(order.OrderUID)
 	}
+}
 
-	uc.logger.Info("Order saved", zap.String("order_uid", order.OrderUID))
-	return nil
+// invalidOrder logs a failed validation and returns the error to hand back.
+// A *model.ValidationError is returned as is, so its violations reach the
+// consumer and HTTP layers; anything else is wrapped as ErrInvalidOrderData.
+func (uc *SaveOrderUseCase) invalidOrder(order *model.Order, err error) error {
+	var validationErr *model.ValidationError
+	if errors.As(err, &validationErr) {
+		uc.logger.Warn("Order validation failed",
+			zap.String("order_uid", order.OrderUID), zap.Any("violations", validationErr.Violations))
+		return validationErr
+	}
+	uc.logger.Warn("Order validation failed", zap.String("order_uid", order.OrderUID), zap.Error(err))
+	return fmt.Errorf("%w: %w", model.ErrInvalidOrderData, err)
 }
@@ -82,7 +82,7 @@ func TestSaveOrderUseCase_Success(t *testing.T) {
 	validator := validation.NewValidator()
 	logger := zap.NewNop()
 
-	uc := NewSaveOrderUseCase(mockRepo, mockCache, validator, logger)
+	uc := NewSaveOrderUseCase(mockRepo, mockCache, mocks.NewMockCacheRepairQueue(ctrl), validator, logger)
 
 	ctx := context.Background()
 	order := createValidOrder(t)
@@ -96,18 +96,43 @@ func TestSaveOrderUseCase_Success(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestSaveOrderUseCase_ReplacesIncomingStatus(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+	mockCache := mocks.NewMockOrderCache(ctrl)
+
+	uc := NewSaveOrderUseCase(mockRepo, mockCache, mocks.NewMockCacheRepairQueue(ctrl), validation.NewValidator(), zap.NewNop())
+
+	ctx := context.Background()
+	order := createValidOrder(t)
+	order.Status = model.OrderStatusPaid
+
+	mockRepo.EXPECT().Exists(ctx, order.OrderUID).Return(false, nil)
+	mockRepo.EXPECT().Save(ctx, &order).DoAndReturn(func(_ context.Context, saved *model.Order) error {
+		assert.Equal(t, model.OrderStatusCreated, saved.Status)
+		return nil
+	})
+	mockCache.EXPECT().Set(ctx, &order).Return(nil)
+
+	require.NoError(t, uc.Execute(ctx, &order))
+}
+
 func TestSaveOrderUseCase_BusinessErrors(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
 		name        string
-		setupMocks  func(*mocks.MockOrderRepository, *mocks.MockOrderCache, context.Context, string)
+		setupMocks  func(*mocks.MockOrderRepository, *mocks.MockOrderCache, *mocks.MockCacheRepairQueue, context.Context, string)
 		mutateOrder func(*model.Order)
 		wantErr     error
 	}{
 		{
 			name: "validation_failed",
-			setupMocks: func(repo *mocks.MockOrderRepository, cache *mocks.MockOrderCache, ctx context.Context, uid string) {
+			setupMocks: func(repo *mocks.MockOrderRepository, cache *mocks.MockOrderCache, repairs *mocks.MockCacheRepairQueue, ctx context.Context, uid string) {
 				repo.EXPECT().Exists(gomock.Any(), gomock.Any()).Times(0)
 				repo.EXPECT().Save(gomock.Any(), gomock.Any()).Times(0)
 				cache.EXPECT().Set(gomock.Any(), gomock.Any()).Times(0)
@@ -119,10 +144,22 @@ func TestSaveOrderUseCase_BusinessErrors(t *testing.T) {
 		},
 		{
 			name: "order_already_exists",
-			setupMocks: func(repo *mocks.MockOrderRepository, cache *mocks.MockOrderCache, ctx context.Context, uid string) {
+			setupMocks: func(repo *mocks.MockOrderRepository, cache *mocks.MockOrderCache, repairs *mocks.MockCacheRepairQueue, ctx context.Context, uid string) {
 				repo.EXPECT().Exists(ctx, uid).Return(true, nil)
 				repo.EXPECT().Save(gomock.Any(), gomock.Any()).Times(0)
 				cache.EXPECT().Set(gomock.Any(), gomock.Any()).Times(0)
+				repairs.EXPECT().Enqueue(uid)
+			},
+			mutateOrder: func(o *model.Order) {},
+			wantErr:     model.ErrOrderAlreadyExists,
+		},
+		{
+			name: "order_saved_concurrently",
+			setupMocks: func(repo *mocks.MockOrderRepository, cache *mocks.MockOrderCache, repairs *mocks.MockCacheRepairQueue, ctx context.Context, uid string) {
+				repo.EXPECT().Exists(ctx, uid).Return(false, nil)
+				repo.EXPECT().Save(ctx, gomock.Any()).Return(model.ErrOrderAlreadyExists)
+				cache.EXPECT().Set(gomock.Any(), gomock.Any()).Times(0)
+				repairs.EXPECT().Enqueue(uid)
 			},
 			mutateOrder: func(o *model.Order) {},
 			wantErr:     model.ErrOrderAlreadyExists,
@@ -138,16 +175,17 @@ func TestSaveOrderUseCase_BusinessErrors(t *testing.T) {
 
 			mockRepo := mocks.NewMockOrderRepository(ctrl)
 			mockCache := mocks.NewMockOrderCache(ctrl)
+			mockRepairs := mocks.NewMockCacheRepairQueue(ctrl)
 			validator := validation.NewValidator()
 			logger := zap.NewNop()
 
-			uc := NewSaveOrderUseCase(mockRepo, mockCache, validator, logger)
+			uc := NewSaveOrderUseCase(mockRepo, mockCache, mockRepairs, validator, logger)
 
 			ctx := context.Background()
 			order := createValidOrder(t)
 			tt.mutateOrder(&order)
 
-			tt.setupMocks(mockRepo, mockCache, ctx, order.OrderUID)
+			tt.setupMocks(mockRepo, mockCache, mockRepairs, ctx, order.OrderUID)
 
 			err := uc.Execute(ctx, &order)
 
@@ -178,14 +216,6 @@ func TestSaveOrderUseCase_InfrastructureErrors(t *testing.T) {
 				cache.EXPECT().Set(gomock.Any(), gomock.Any()).Times(0)
 			},
 		},
-		{
-			name: "cache_set_failed",
-			setupMocks: func(repo *mocks.MockOrderRepository, cache *mocks.MockOrderCache, ctx context.Context, uid string) {
-				repo.EXPECT().Exists(ctx, uid).Return(false, nil)
-				repo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
-				cache.EXPECT().Set(gomock.Any(), gomock.Any()).Return(errors.New("redis down"))
-			},
-		},
 	}
 
 	for _, tt := range tests {
@@ -200,7 +230,7 @@ func TestSaveOrderUseCase_InfrastructureErrors(t *testing.T) {
 			validator := validation.NewValidator()
 			logger := zap.NewNop()
 
-			uc := NewSaveOrderUseCase(mockRepo, mockCache, validator, logger)
+			uc := NewSaveOrderUseCase(mockRepo, mockCache, mocks.NewMockCacheRepairQueue(ctrl), validator, logger)
 
 			ctx := context.Background()
 			order := createValidOrder(t)
@@ -213,3 +243,95 @@ func TestSaveOrderUseCase_InfrastructureErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestSaveOrderUseCase_CacheSetFailedQueuesRepair(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+	mockCache := mocks.NewMockOrderCache(ctrl)
+	mockRepairs := mocks.NewMockCacheRepairQueue(ctrl)
+	validator := validation.NewValidator()
+	logger := zap.NewNop()
+
+	uc := NewSaveOrderUseCase(mockRepo, mockCache, mockRepairs, validator, logger)
+
+	ctx := context.Background()
+	order := createValidOrder(t)
+
+	mockRepo.EXPECT().Exists(ctx, order.OrderUID).Return(false, nil)
+	mockRepo.EXPECT().Save(ctx, &order).Return(nil)
+	mockCache.EXPECT().Set(ctx, &order).Return(errors.New("redis down"))
+	mockRepairs.EXPECT().Enqueue(order.OrderUID)
+
+	err := uc.Execute(ctx, &order)
+
+	assert.NoError(t, err)
+}
+
+func TestSaveOrderUseCase_ExecuteBatch(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+	mockCache := mocks.NewMockOrderCache(ctrl)
+	mockRepairs := mocks.NewMockCacheRepairQueue(ctrl)
+	validator := validation.NewValidator()
+	logger := zap.NewNop()
+
+	uc := NewSaveOrderUseCase(mockRepo, mockCache, mockRepairs, validator, logger)
+
+	ctx := context.Background()
+	saved := createValidOrder(t)
+	invalid := createValidOrder(t)
+	invalid.OrderUID = ""
+	existing := createValidOrder(t)
+	failed := createValidOrder(t)
+	uncached := createValidOrder(t)
+
+	mockRepo.EXPECT().
+		SaveBatch(ctx, []*model.Order{&saved, &existing, &failed, &uncached}).
+		Return([]error{nil, model.ErrOrderAlreadyExists, errors.New("db connection lost"), nil})
+	mockCache.EXPECT().Set(ctx, &saved).Return(nil)
+	mockCache.EXPECT().Set(ctx, &uncached).Return(errors.New("redis down"))
+	mockRepairs.EXPECT().Enqueue(existing.OrderUID)
+	mockRepairs.EXPECT().Enqueue(uncached.OrderUID)
+
+	errs := uc.ExecuteBatch(ctx, []*model.Order{&saved, &invalid, &existing, &failed, &uncached})
+
+	require.Len(t, errs, 5)
+	require.NoError(t, errs[0])
+	require.ErrorIs(t, errs[1], model.ErrInvalidOrderData)
+	require.ErrorIs(t, errs[2], model.ErrOrderAlreadyExists)
+	require.Error(t, errs[3])
+	assert.NotErrorIs(t, errs[3], model.ErrOrderAlreadyExists)
+	assert.NoError(t, errs[4], "a committed order is saved even if caching it failed")
+}
+
+func TestSaveOrderUseCase_ExecuteBatch_AllInvalid(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockOrderRepository(ctrl)
+	mockCache := mocks.NewMockOrderCache(ctrl)
+	validator := validation.NewValidator()
+	logger := zap.NewNop()
+
+	uc := NewSaveOrderUseCase(mockRepo, mockCache, mocks.NewMockCacheRepairQueue(ctrl), validator, logger)
+
+	order := createValidOrder(t)
+	order.Items = nil
+
+	mockRepo.EXPECT().SaveBatch(gomock.Any(), gomock.Any()).Times(0)
+
+	errs := uc.ExecuteBatch(context.Background(), []*model.Order{&order})
+
+	require.Len(t, errs, 1)
+	assert.ErrorIs(t, errs[0], model.ErrInvalidOrderData)
+}
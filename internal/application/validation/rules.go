@@ -0,0 +1,137 @@
+package validation
+
+import (
+	"fmt"
+	"strconv"
+
+	"l0/internal/domain/model"
+)
+
+// Mode decides what a business rule violation does to an order.
+type Mode string
+
+const (
+	// ModeReject fails validation, like a struct tag violation.
+	ModeReject Mode = "reject"
+	// ModeWarn lets the order through and reports the violation to the
+	// warning handler.
+	ModeWarn Mode = "warn"
+	// ModeOff disables the rule.
+	ModeOff Mode = "off"
+)
+
+func ParseMode(s string) (Mode, error) {
+	switch m := Mode(s); m {
+	case ModeReject, ModeWarn, ModeOff:
+		return m, nil
+	}
+	return "", fmt.Errorf("unknown validation mode %q, want reject, warn or off", s)
+}
+
+// Rule is a cross-field check that struct tags can't express. Check returns
+// one violation per inconsistency; Rule is reported as the violation's rule.
+type Rule struct {
+	Name  string
+	Check func(order *model.Order) []model.FieldViolation
+}
+
+const (
+	ruleGoodsTotal      = "goods_total"
+	rulePaymentAmount   = "payment_amount"
+	ruleItemTotal       = "item_total"
+	ruleTransaction     = "transaction"
+	ruleItemTrackNumber = "item_track_number"
+)
+
+var (
+	// GoodsTotalRule checks that payment.goods_total is the sum of the items'
+	// total prices.
+	GoodsTotalRule = Rule{Name: ruleGoodsTotal, Check: checkGoodsTotal}
+	// PaymentAmountRule checks that payment.amount is goods_total plus
+	// delivery_cost plus custom_fee.
+	PaymentAmountRule = Rule{Name: rulePaymentAmount, Check: checkPaymentAmount}
+	// ItemTotalRule checks that an item's total_price is its price with the
+	// sale percentage taken off, rounded either way.
+	ItemTotalRule = Rule{Name: ruleItemTotal, Check: checkItemTotals}
+	// TransactionRule checks that payment.transaction equals order_uid.
+	TransactionRule = Rule{Name: ruleTransaction, Check: checkTransaction}
+	// ItemTrackNumberRule checks that every item carries the order's track
+	// number.
+	ItemTrackNumberRule = Rule{Name: ruleItemTrackNumber, Check: checkItemTrackNumbers}
+)
+
+func checkGoodsTotal(order *model.Order) []model.FieldViolation {
+	sum := 0
+	for _, item := range order.Items {
+		sum += item.TotalPrice
+	}
+	if order.Payment.GoodsTotal == sum {
+		return nil
+	}
+	return []model.FieldViolation{{
+		Field: "payment.goods_total",
+		Rule:  ruleGoodsTotal,
+		Param: strconv.Itoa(sum),
+		Value: order.Payment.GoodsTotal,
+	}}
+}
+
+func checkPaymentAmount(order *model.Order) []model.FieldViolation {
+	p := order.Payment
+	want := p.GoodsTotal + p.DeliveryCost + p.CustomFee
+	if p.Amount == want {
+		return nil
+	}
+	return []model.FieldViolation{{
+		Field: "payment.amount",
+		Rule:  rulePaymentAmount,
+		Param: strconv.Itoa(want),
+		Value: p.Amount,
+	}}
+}
+
+func checkItemTotals(order *model.Order) []model.FieldViolation {
+	var violations []model.FieldViolation
+	for i, item := range order.Items {
+		discounted := item.Price * (100 - item.Sale)
+		floor, ceil := discounted/100, (discounted+99)/100
+		if item.TotalPrice == floor || item.TotalPrice == ceil {
+			continue
+		}
+		violations = append(violations, model.FieldViolation{
+			Field: fmt.Sprintf("items[%d].total_price", i),
+			Rule:  ruleItemTotal,
+			Param: strconv.Itoa(floor),
+			Value: item.TotalPrice,
+		})
+	}
+	return violations
+}
+
+func checkTransaction(order *model.Order) []model.FieldViolation {
+	if order.Payment.Transaction == order.OrderUID {
+		return nil
+	}
+	return []model.FieldViolation{{
+		Field: "payment.transaction",
+		Rule:  ruleTransaction,
+		Param: order.OrderUID,
+		Value: order.Payment.Transaction,
+	}}
+}
+
+func checkItemTrackNumbers(order *model.Order) []model.FieldViolation {
+	var violations []model.FieldViolation
+	for i, item := range order.Items {
+		if item.TrackNumber == order.TrackNumber {
+			continue
+		}
+		violations = append(violations, model.FieldViolation{
+			Field: fmt.Sprintf("items[%d].track_number", i),
+			Rule:  ruleItemTrackNumber,
+			Param: order.TrackNumber,
+			Value: item.TrackNumber,
+		})
+	}
+	return violations
+}
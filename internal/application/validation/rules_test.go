@@ -0,0 +1,180 @@
+package validation
+
+import (
+	"testing"
+
+	"l0/internal/domain/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// consistentOrder returns a valid order that satisfies every business rule.
+func consistentOrder(t *testing.T) model.Order {
+	t.Helper()
+
+	order := validOrder(t)
+	order.Items = append(order.Items, order.Items[0])
+	order.Items[0].Price, order.Items[0].Sale, order.Items[0].TotalPrice = 453, 30, 317
+	order.Items[1].Price, order.Items[1].Sale, order.Items[1].TotalPrice = 1000, 0, 1000
+	for i := range order.Items {
+		order.Items[i].TrackNumber = order.TrackNumber
+	}
+	order.Payment.Transaction = order.OrderUID
+	order.Payment.GoodsTotal = 1317
+	order.Payment.DeliveryCost = 1500
+	order.Payment.CustomFee = 10
+	order.Payment.Amount = 2827
+	return order
+}
+
+func TestRules(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		rule   Rule
+		mutate func(*model.Order)
+		want   []model.FieldViolation
+	}{
+		{
+			name:   "goods total mismatch",
+			rule:   GoodsTotalRule,
+			mutate: func(o *model.Order) { o.Payment.GoodsTotal = 1300 },
+			want:   []model.FieldViolation{{Field: "payment.goods_total", Rule: "goods_total", Param: "1317", Value: 1300}},
+		},
+		{
+			name:   "amount mismatch",
+			rule:   PaymentAmountRule,
+			mutate: func(o *model.Order) { o.Payment.CustomFee = 0 },
+			want:   []model.FieldViolation{{Field: "payment.amount", Rule: "payment_amount", Param: "2817", Value: 2827}},
+		},
+		{
+			name:   "item total ignores sale",
+			rule:   ItemTotalRule,
+			mutate: func(o *model.Order) { o.Items[0].TotalPrice = 453 },
+			want:   []model.FieldViolation{{Field: "items[0].total_price", Rule: "item_total", Param: "317", Value: 453}},
+		},
+		{
+			name:   "item total rounded up is accepted",
+			rule:   ItemTotalRule,
+			mutate: func(o *model.Order) { o.Items[0].TotalPrice = 318 },
+		},
+		{
+			name:   "transaction differs from order uid",
+			rule:   TransactionRule,
+			mutate: func(o *model.Order) { o.Payment.Transaction = "other" },
+			want:   []model.FieldViolation{{Field: "payment.transaction", Rule: "transaction", Param: "uid", Value: "other"}},
+		},
+		{
+			name:   "item track number differs",
+			rule:   ItemTrackNumberRule,
+			mutate: func(o *model.Order) { o.Items[1].TrackNumber = "OTHER" },
+			want:   []model.FieldViolation{{Field: "items[1].track_number", Rule: "item_track_number", Param: "TRACK", Value: "OTHER"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			order := consistentOrder(t)
+			order.OrderUID, order.Payment.Transaction = "uid", "uid"
+			order.TrackNumber = "TRACK"
+			for i := range order.Items {
+				order.Items[i].TrackNumber = "TRACK"
+			}
+			require.Empty(t, tt.rule.Check(&order), "consistent order must pass")
+
+			tt.mutate(&order)
+			assert.Equal(t, tt.want, tt.rule.Check(&order))
+		})
+	}
+}
+
+func TestValidateOrder_RuleModes(t *testing.T) {
+	t.Parallel()
+
+	mismatch := func(o *model.Order) { o.Payment.GoodsTotal++ }
+
+	t.Run("tag-only by default", func(t *testing.T) {
+		t.Parallel()
+
+		order := consistentOrder(t)
+		mismatch(&order)
+		assert.NoError(t, NewValidator().ValidateOrder(order))
+	})
+
+	t.Run("reject", func(t *testing.T) {
+		t.Parallel()
+
+		order := consistentOrder(t)
+		mismatch(&order)
+		err := NewValidator(WithRule(GoodsTotalRule, ModeReject), WithRule(PaymentAmountRule, ModeReject)).ValidateOrder(order)
+
+		var validationErr *model.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.ErrorIs(t, err, model.ErrInvalidOrderData)
+		require.Len(t, validationErr.Violations, 2)
+		assert.Equal(t, "payment.goods_total", validationErr.Violations[0].Field)
+		assert.Equal(t, "payment.amount", validationErr.Violations[1].Field)
+	})
+
+	t.Run("warn", func(t *testing.T) {
+		t.Parallel()
+
+		order := consistentOrder(t)
+		mismatch(&order)
+
+		var warned []model.FieldViolation
+		v := NewValidator(
+			WithRule(GoodsTotalRule, ModeWarn),
+			WithWarningHandler(func(o *model.Order, warnings []model.FieldViolation) {
+				assert.Equal(t, order.OrderUID, o.OrderUID)
+				warned = warnings
+			}),
+		)
+		require.NoError(t, v.ValidateOrder(order))
+		require.Len(t, warned, 1)
+		assert.Equal(t, "goods_total", warned[0].Rule)
+	})
+
+	t.Run("off", func(t *testing.T) {
+		t.Parallel()
+
+		order := consistentOrder(t)
+		mismatch(&order)
+		v := NewValidator(
+			WithRule(GoodsTotalRule, ModeOff),
+			WithWarningHandler(func(*model.Order, []model.FieldViolation) { t.Error("unexpected warning") }),
+		)
+		assert.NoError(t, v.ValidateOrder(order))
+	})
+
+	t.Run("rules skipped when tags fail", func(t *testing.T) {
+		t.Parallel()
+
+		order := consistentOrder(t)
+		mismatch(&order)
+		order.OrderUID = ""
+		err := NewValidator(WithRule(GoodsTotalRule, ModeReject)).ValidateOrder(order)
+
+		var validationErr *model.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		require.Len(t, validationErr.Violations, 1)
+		assert.Equal(t, "order_uid", validationErr.Violations[0].Field)
+	})
+}
+
+func TestParseMode(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range []string{"reject", "warn", "off"} {
+		mode, err := ParseMode(s)
+		require.NoError(t, err)
+		assert.Equal(t, Mode(s), mode)
+	}
+
+	_, err := ParseMode("strict")
+	assert.Error(t, err)
+}
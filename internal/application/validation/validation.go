@@ -1,31 +1,136 @@
 package validation
 
 import (
+	"context"
 	"errors"
-	"fmt"
+	"reflect"
+	"strings"
+
 	"l0/internal/domain/model"
 
 	"github.com/go-playground/validator/v10"
 )
 
 type Validator struct {
-	validate *validator.Validate
+	validate  *validator.Validate
+	rules     []configuredRule
+	onWarning WarningHandler
+}
+
+type configuredRule struct {
+	Rule
+	mode Mode
+}
+
+// WarningHandler receives the violations of rules in ModeWarn for an order
+// that otherwise passed validation.
+type WarningHandler func(order *model.Order, warnings []model.FieldViolation)
+
+type Option func(*Validator)
+
+// WithRule enables a business rule in the given mode. Rules run in the order
+// they were added, and only once the struct tags pass, so they never see an
+// order missing the fields they compare.
+func WithRule(rule Rule, mode Mode) Option {
+	return func(v *Validator) {
+		if mode != ModeOff {
+			v.rules = append(v.rules, configuredRule{Rule: rule, mode: mode})
+		}
+	}
 }
 
-func NewValidator() *Validator {
-	return &Validator{
-		validate: validator.New(),
+func WithWarningHandler(handler WarningHandler) Option {
+	return func(v *Validator) {
+		v.onWarning = handler
 	}
 }
 
+// NewValidator checks struct tags only unless business rules are added
+// with WithRule.
+func NewValidator(opts ...Option) *Validator {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(jsonFieldName)
+	v := &Validator{
+		validate: validate,
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// Validate implements repository.OrderValidator.
+func (v *Validator) Validate(_ context.Context, order *model.Order) error {
+	return v.ValidateOrder(*order)
+}
+
+// ValidateOrder returns a *model.ValidationError listing every failed field
+// and every violated business rule in ModeReject.
 func (v *Validator) ValidateOrder(order model.Order) error {
-	err := v.validate.Struct(order)
-	if err != nil {
-		var invalidErr *validator.InvalidValidationError
-		if errors.As(err, &invalidErr) {
-			return err
+	if err := v.validateTags(order); err != nil {
+		return err
+	}
+
+	var rejected, warnings []model.FieldViolation
+	for _, rule := range v.rules {
+		violations := rule.Check(&order)
+		if rule.mode == ModeReject {
+			rejected = append(rejected, violations...)
+		} else {
+			warnings = append(warnings, violations...)
 		}
-		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	if len(warnings) > 0 && v.onWarning != nil {
+		v.onWarning(&order, warnings)
+	}
+	if len(rejected) > 0 {
+		return &model.ValidationError{Violations: rejected}
 	}
 	return nil
 }
+
+func (v *Validator) validateTags(order model.Order) error {
+	err := v.validate.Struct(order)
+	if err == nil {
+		return nil
+	}
+
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return err
+	}
+
+	violations := make([]model.FieldViolation, 0, len(validationErrs))
+	for _, fe := range validationErrs {
+		violations = append(violations, model.FieldViolation{
+			Field: fieldPath(fe.Namespace()),
+			Rule:  fe.Tag(),
+			Param: fe.Param(),
+			Value: fe.Value(),
+		})
+	}
+	return &model.ValidationError{Violations: violations}
+}
+
+// jsonFieldName makes validator report fields by their JSON names, so paths
+// match what producers put in the payload.
+func jsonFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	}
+	return name
+}
+
+// fieldPath drops the root struct name from a validator namespace:
+// "Order.items[0].price" becomes "items[0].price".
+func fieldPath(namespace string) string {
+	if _, path, ok := strings.Cut(namespace, "."); ok {
+		return path
+	}
+	return namespace
+}
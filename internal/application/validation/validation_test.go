@@ -8,7 +8,6 @@ import (
 	"l0/internal/domain/model"
 
 	"github.com/brianvoe/gofakeit/v7"
-	"github.com/go-playground/validator/v10"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -89,55 +88,63 @@ func TestValidateOrder_ValidationErrors(t *testing.T) {
 		name          string
 		mutateOrder   func(*model.Order)
 		expectedField string
+		expectedRule  string
 	}{
 		{
 			name: "missing_order_uid",
 			mutateOrder: func(o *model.Order) {
 				o.OrderUID = ""
 			},
-			expectedField: "OrderUID",
+			expectedField: "order_uid",
+			expectedRule:  "required",
 		},
 		{
 			name: "invalid_email",
 			mutateOrder: func(o *model.Order) {
 				o.Delivery.Email = "not-an-email"
 			},
-			expectedField: "Email",
+			expectedField: "delivery.email",
+			expectedRule:  "email",
 		},
 		{
 			name: "invalid_phone",
 			mutateOrder: func(o *model.Order) {
 				o.Delivery.Phone = "123"
 			},
-			expectedField: "Phone",
+			expectedField: "delivery.phone",
+			expectedRule:  "e164",
 		},
 		{
 			name: "empty_items",
 			mutateOrder: func(o *model.Order) {
 				o.Items = []model.Item{}
 			},
-			expectedField: "Items",
+			expectedField: "items",
+			expectedRule:  "min",
 		},
 		{
 			name: "negative_amount",
 			mutateOrder: func(o *model.Order) {
 				o.Payment.Amount = -100
 			},
-			expectedField: "Amount",
+			expectedField: "payment.amount",
+			expectedRule:  "gt",
 		},
 		{
 			name: "invalid_date",
 			mutateOrder: func(o *model.Order) {
 				o.DateCreated = "not-a-date"
 			},
-			expectedField: "DateCreated",
+			expectedField: "date_created",
+			expectedRule:  "datetime",
 		},
 		{
 			name: "zero_price",
 			mutateOrder: func(o *model.Order) {
 				o.Items[0].Price = 0
 			},
-			expectedField: "Price",
+			expectedField: "items[0].price",
+			expectedRule:  "required",
 		},
 	}
 
@@ -152,10 +159,34 @@ func TestValidateOrder_ValidationErrors(t *testing.T) {
 
 			require.Error(t, err)
 
-			var validationErrs validator.ValidationErrors
-			require.ErrorAs(t, err, &validationErrs)
+			assert.ErrorIs(t, err, model.ErrInvalidOrderData)
 
-			assert.Equal(t, tt.expectedField, validationErrs[0].Field())
+			var validationErr *model.ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			require.Len(t, validationErr.Violations, 1)
+
+			violation := validationErr.Violations[0]
+			assert.Equal(t, tt.expectedField, violation.Field)
+			assert.Equal(t, tt.expectedRule, violation.Rule)
 		})
 	}
 }
+
+func TestValidateOrder_ReportsValueAndParam(t *testing.T) {
+	t.Parallel()
+
+	order := validOrder(t)
+	order.Payment.Amount = -100
+	order.Items = append(order.Items, order.Items[0])
+	order.Items[1].Sale = -5
+
+	err := NewValidator().ValidateOrder(order)
+
+	var validationErr *model.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, []model.FieldViolation{
+		{Field: "payment.amount", Rule: "gt", Param: "0", Value: -100},
+		{Field: "items[1].sale", Rule: "gte", Param: "0", Value: -5},
+	}, validationErr.Violations)
+	assert.EqualError(t, err, "invalid order data: payment.amount: gt=0; items[1].sale: gte=0")
+}
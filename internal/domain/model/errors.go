@@ -6,4 +6,8 @@ var (
 	ErrOrderNotFound      = errors.New("order not found")
 	ErrOrderAlreadyExists = errors.New("order already exists")
 	ErrInvalidOrderData   = errors.New("invalid order data")
+
+	ErrInvalidStatusUpdate     = errors.New("invalid status update")
+	ErrInvalidStatusTransition = errors.New("invalid status transition")
+	ErrStatusConflict          = errors.New("order status changed concurrently")
 )
@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+const (
+	EventOrderSaved         = "OrderSaved"
+	EventOrderStatusChanged = "OrderStatusChanged"
+)
+
+// OutboxEvent is a domain event stored in the same transaction as the change
+// that produced it and published to Kafka afterwards.
+type OutboxEvent struct {
+	ID          int64
+	AggregateID string
+	EventType   string
+	Payload     []byte
+	CreatedAt   time.Time
+}
+
+type OrderSavedEvent struct {
+	OrderUID    string    `json:"order_uid"`
+	TrackNumber string    `json:"track_number"`
+	CustomerID  string    `json:"customer_id"`
+	DateCreated string    `json:"date_created"`
+	SavedAt     time.Time `json:"saved_at"`
+}
+
+type OrderStatusChangedEvent struct {
+	OrderUID  string      `json:"order_uid"`
+	From      OrderStatus `json:"from"`
+	To        OrderStatus `json:"to"`
+	Reason    string      `json:"reason,omitempty"`
+	ChangedAt time.Time   `json:"changed_at"`
+}
@@ -0,0 +1,11 @@
+package model
+
+// IdempotencyRecord is what an Idempotency-Key resolves to: a fingerprint of
+// the request it was first used with and, once that request has finished, the
+// response to replay to retries.
+type IdempotencyRecord struct {
+	RequestHash string `json:"request_hash"`
+	Completed   bool   `json:"completed"`
+	StatusCode  int    `json:"status_code,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// OrderFilter narrows an order listing. Zero values disable the
+// corresponding condition. Orders are listed newest first, ties broken by
+// order_uid, and Cursor continues a listing after the given order.
+type OrderFilter struct {
+	CustomerID      string
+	TrackNumber     string
+	DeliveryService string
+	Entry           string
+	Locale          string
+	CreatedFrom     time.Time
+	CreatedTo       time.Time
+	// ChangedFrom keeps orders stored or moved to another status since then,
+	// whatever their date_created.
+	ChangedFrom time.Time
+	Cursor      *OrderCursor
+	Limit       int
+}
+
+// OrderCursor is the keyset position of the last order on a page.
+type OrderCursor struct {
+	DateCreated time.Time `json:"date_created"`
+	OrderUID    string    `json:"order_uid"`
+}
+
+// CursorOf returns the keyset position of order, for continuing a listing
+// after it.
+func CursorOf(order *Order) (OrderCursor, error) {
+	createdAt, err := time.Parse(time.RFC3339Nano, order.DateCreated)
+	if err != nil {
+		return OrderCursor{}, fmt.Errorf("failed to build cursor for order %s: %w", order.OrderUID, err)
+	}
+	return OrderCursor{DateCreated: createdAt, OrderUID: order.OrderUID}, nil
+}
+
+type OrderPage struct {
+	Orders     []*Order
+	NextCursor *OrderCursor
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// Message is a record read from or written to a message broker. Offsets are
+// ordered within a partition, and HighWaterMark is the offset the partition's
+// next message will get, as far as the source knew when the message was read.
+type Message struct {
+	Topic         string
+	Partition     int
+	Offset        int64
+	HighWaterMark int64
+	Key           []byte
+	Value         []byte
+	Headers       []MessageHeader
+	Time          time.Time
+}
+
+type MessageHeader struct {
+	Key   string
+	Value []byte
+}
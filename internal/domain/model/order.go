@@ -1,20 +1,21 @@
 package model
 
 type Order struct {
-	OrderUID          string   `json:"order_uid" validate:"required"`
-	TrackNumber       string   `json:"track_number" validate:"required"`
-	Entry             string   `json:"entry" validate:"required"`
-	Delivery          Delivery `json:"delivery" validate:"required"`
-	Payment           Payment  `json:"payment" validate:"required"`
-	Items             []Item   `json:"items" validate:"required,min=1,dive"`
-	Locale            string   `json:"locale" validate:"required"`
-	InternalSignature string   `json:"internal_signature"`
-	CustomerID        string   `json:"customer_id" validate:"required"`
-	DeliveryService   string   `json:"delivery_service" validate:"required"`
-	Shardkey          string   `json:"shardkey" validate:"required"`
-	SmID              int      `json:"sm_id" validate:"required"`
-	DateCreated       string   `json:"date_created" validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
-	OofShard          string   `json:"oof_shard" validate:"required"`
+	OrderUID          string      `json:"order_uid" validate:"required"`
+	TrackNumber       string      `json:"track_number" validate:"required"`
+	Entry             string      `json:"entry" validate:"required"`
+	Delivery          Delivery    `json:"delivery" validate:"required"`
+	Payment           Payment     `json:"payment" validate:"required"`
+	Items             []Item      `json:"items" validate:"required,min=1,dive"`
+	Locale            string      `json:"locale" validate:"required"`
+	InternalSignature string      `json:"internal_signature"`
+	CustomerID        string      `json:"customer_id" validate:"required"`
+	DeliveryService   string      `json:"delivery_service" validate:"required"`
+	Shardkey          string      `json:"shardkey" validate:"required"`
+	SmID              int         `json:"sm_id" validate:"required"`
+	DateCreated       string      `json:"date_created" validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
+	OofShard          string      `json:"oof_shard" validate:"required"`
+	Status            OrderStatus `json:"status"`
 }
 
 type Delivery struct {
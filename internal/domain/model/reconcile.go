@@ -0,0 +1,11 @@
+package model
+
+// ReconcileResult sums up one pass of the cache reconciler.
+type ReconcileResult struct {
+	Checked int
+	// Missing counts orders that were not cached and have been filled in.
+	Missing int
+	// Stale counts orders cached with an outdated status or recorded as
+	// missing, which have been queued for repair.
+	Stale int
+}
@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// RestoreCheckpoint records how far a cache restore got, so a restarted
+// restore continues after the last chunk written instead of starting over.
+// Every order newer than Cursor and created after Since is in the cache,
+// whichever instance wrote it.
+type RestoreCheckpoint struct {
+	Since     time.Time   `json:"since"`
+	Cursor    OrderCursor `json:"cursor"`
+	Restored  int         `json:"restored"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// RestoreProgress is reported after every chunk of a cache restore.
+type RestoreProgress struct {
+	// Restored counts orders written so far, including those written
+	// before the restore was resumed.
+	Restored int
+	Chunks   int
+	Cursor   OrderCursor
+	Resumed  bool
+}
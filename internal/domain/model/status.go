@@ -0,0 +1,49 @@
+package model
+
+type OrderStatus string
+
+const (
+	OrderStatusCreated    OrderStatus = "created"
+	OrderStatusPaid       OrderStatus = "paid"
+	OrderStatusAssembling OrderStatus = "assembling"
+	OrderStatusShipped    OrderStatus = "shipped"
+	OrderStatusDelivered  OrderStatus = "delivered"
+	OrderStatusCancelled  OrderStatus = "cancelled"
+	OrderStatusReturned   OrderStatus = "returned"
+)
+
+// orderTransitions lists, for every status, the statuses an order may move to.
+// Cancelled and returned are terminal.
+var orderTransitions = map[OrderStatus][]OrderStatus{
+	OrderStatusCreated:    {OrderStatusPaid, OrderStatusCancelled},
+	OrderStatusPaid:       {OrderStatusAssembling, OrderStatusCancelled},
+	OrderStatusAssembling: {OrderStatusShipped, OrderStatusCancelled},
+	OrderStatusShipped:    {OrderStatusDelivered, OrderStatusReturned},
+	OrderStatusDelivered:  {OrderStatusReturned},
+	OrderStatusCancelled:  {},
+	OrderStatusReturned:   {},
+}
+
+func (s OrderStatus) IsValid() bool {
+	_, ok := orderTransitions[s]
+	return ok
+}
+
+func (s OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	for _, allowed := range orderTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// MaxStatusReasonLength is the most characters status_history.reason holds.
+const MaxStatusReasonLength = 200
+
+// StatusChange is a request to move an order to another lifecycle status.
+type StatusChange struct {
+	OrderUID string      `json:"order_uid"`
+	Status   OrderStatus `json:"status"`
+	Reason   string      `json:"reason"`
+}
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderStatus_CanTransitionTo(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		from OrderStatus
+		to   OrderStatus
+		want bool
+	}{
+		{OrderStatusCreated, OrderStatusPaid, true},
+		{OrderStatusCreated, OrderStatusCancelled, true},
+		{OrderStatusCreated, OrderStatusShipped, false},
+		{OrderStatusPaid, OrderStatusAssembling, true},
+		{OrderStatusAssembling, OrderStatusShipped, true},
+		{OrderStatusShipped, OrderStatusDelivered, true},
+		{OrderStatusShipped, OrderStatusCancelled, false},
+		{OrderStatusDelivered, OrderStatusReturned, true},
+		{OrderStatusDelivered, OrderStatusPaid, false},
+		{OrderStatusCancelled, OrderStatusPaid, false},
+		{OrderStatusReturned, OrderStatusDelivered, false},
+		{OrderStatusPaid, OrderStatusPaid, false},
+		{OrderStatus("unknown"), OrderStatusPaid, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+"_to_"+string(tt.to), func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, tt.from.CanTransitionTo(tt.to))
+		})
+	}
+}
+
+func TestOrderStatus_IsValid(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, OrderStatusAssembling.IsValid())
+	assert.False(t, OrderStatus("lost").IsValid())
+	assert.False(t, OrderStatus("").IsValid())
+}
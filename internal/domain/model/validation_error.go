@@ -0,0 +1,38 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldViolation describes one failed check. Field is the JSON path of the
+// offending value, e.g. "items[0].price".
+type FieldViolation struct {
+	Field string `json:"field"`
+	Rule  string `json:"rule"`
+	Param string `json:"param,omitempty"`
+	Value any    `json:"value,omitempty"`
+}
+
+// ValidationError lists every violation found in an order. It matches
+// ErrInvalidOrderData with errors.Is, so callers that only care about the
+// category keep working.
+type ValidationError struct {
+	Violations []FieldViolation
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		rule := v.Rule
+		if v.Param != "" {
+			rule += "=" + v.Param
+		}
+		parts = append(parts, fmt.Sprintf("%s: %s", v.Field, rule))
+	}
+	return fmt.Sprintf("%s: %s", ErrInvalidOrderData, strings.Join(parts, "; "))
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrInvalidOrderData
+}
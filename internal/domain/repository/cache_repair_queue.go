@@ -0,0 +1,10 @@
+package repository
+
+// CacheRepairQueue brings a cached order back in line with the DB after a
+// cache write failed. Enqueue neither blocks nor fails: the order is re-read
+// from the DB and written to the cache in the background, with retries.
+//
+//go:generate mockgen -source=cache_repair_queue.go -destination=mocks/cache_repair_queue.go -package=mocks
+type CacheRepairQueue interface {
+	Enqueue(orderUID string)
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"l0/internal/domain/model"
+)
+
+//go:generate mockgen -source=idempotency_store.go -destination=mocks/idempotency_store.go -package=mocks
+type IdempotencyStore interface {
+	// Reserve claims key for a request in flight. When the key is already
+	// taken it returns false together with the existing record.
+	Reserve(ctx context.Context, key, requestHash string) (*model.IdempotencyRecord, bool, error)
+	Complete(ctx context.Context, key string, record *model.IdempotencyRecord) error
+	Release(ctx context.Context, key string) error
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+
+	"l0/internal/domain/model"
+)
+
+//go:generate mockgen -source=message_source.go -destination=mocks/message_source.go -package=mocks
+type MessageSource interface {
+	// Fetch blocks until the next message is available or ctx is done.
+	// Messages of a partition are handed out in offset order.
+	Fetch(ctx context.Context) (model.Message, error)
+	Close() error
+}
+
+// Committer records how far messages have been processed, so a restarted
+// consumer resumes after them. Committing a message commits every earlier
+// message of its partition.
+type Committer interface {
+	Commit(ctx context.Context, msgs ...model.Message) error
+}
+
+// MessagePublisher writes messages to the topics they name.
+type MessagePublisher interface {
+	Publish(ctx context.Context, msgs ...model.Message) error
+	Close() error
+}
@@ -0,0 +1,52 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cache_repair_queue.go
+//
+// Generated by this command:
+//
+//	mockgen -source=cache_repair_queue.go -destination=mocks/cache_repair_queue.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCacheRepairQueue is a mock of CacheRepairQueue interface.
+type MockCacheRepairQueue struct {
+	ctrl     *gomock.Controller
+	recorder *MockCacheRepairQueueMockRecorder
+	isgomock struct{}
+}
+
+// MockCacheRepairQueueMockRecorder is the mock recorder for MockCacheRepairQueue.
+type MockCacheRepairQueueMockRecorder struct {
+	mock *MockCacheRepairQueue
+}
+
+// NewMockCacheRepairQueue creates a new mock instance.
+func NewMockCacheRepairQueue(ctrl *gomock.Controller) *MockCacheRepairQueue {
+	mock := &MockCacheRepairQueue{ctrl: ctrl}
+	mock.recorder = &MockCacheRepairQueueMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCacheRepairQueue) EXPECT() *MockCacheRepairQueueMockRecorder {
+	return m.recorder
+}
+
+// Enqueue mocks base method.
+func (m *MockCacheRepairQueue) Enqueue(orderUID string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Enqueue", orderUID)
+}
+
+// Enqueue indicates an expected call of Enqueue.
+func (mr *MockCacheRepairQueueMockRecorder) Enqueue(orderUID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enqueue", reflect.TypeOf((*MockCacheRepairQueue)(nil).Enqueue), orderUID)
+}
@@ -0,0 +1,171 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: message_source.go
+//
+// Generated by this command:
+//
+//	mockgen -source=message_source.go -destination=mocks/message_source.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	model "l0/internal/domain/model"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockMessageSource is a mock of MessageSource interface.
+type MockMessageSource struct {
+	ctrl     *gomock.Controller
+	recorder *MockMessageSourceMockRecorder
+	isgomock struct{}
+}
+
+// MockMessageSourceMockRecorder is the mock recorder for MockMessageSource.
+type MockMessageSourceMockRecorder struct {
+	mock *MockMessageSource
+}
+
+// NewMockMessageSource creates a new mock instance.
+func NewMockMessageSource(ctrl *gomock.Controller) *MockMessageSource {
+	mock := &MockMessageSource{ctrl: ctrl}
+	mock.recorder = &MockMessageSourceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMessageSource) EXPECT() *MockMessageSourceMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockMessageSource) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockMessageSourceMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockMessageSource)(nil).Close))
+}
+
+// Fetch mocks base method.
+func (m *MockMessageSource) Fetch(ctx context.Context) (model.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fetch", ctx)
+	ret0, _ := ret[0].(model.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Fetch indicates an expected call of Fetch.
+func (mr *MockMessageSourceMockRecorder) Fetch(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fetch", reflect.TypeOf((*MockMessageSource)(nil).Fetch), ctx)
+}
+
+// MockCommitter is a mock of Committer interface.
+type MockCommitter struct {
+	ctrl     *gomock.Controller
+	recorder *MockCommitterMockRecorder
+	isgomock struct{}
+}
+
+// MockCommitterMockRecorder is the mock recorder for MockCommitter.
+type MockCommitterMockRecorder struct {
+	mock *MockCommitter
+}
+
+// NewMockCommitter creates a new mock instance.
+func NewMockCommitter(ctrl *gomock.Controller) *MockCommitter {
+	mock := &MockCommitter{ctrl: ctrl}
+	mock.recorder = &MockCommitterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCommitter) EXPECT() *MockCommitterMockRecorder {
+	return m.recorder
+}
+
+// Commit mocks base method.
+func (m *MockCommitter) Commit(ctx context.Context, msgs ...model.Message) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range msgs {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Commit", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Commit indicates an expected call of Commit.
+func (mr *MockCommitterMockRecorder) Commit(ctx any, msgs ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, msgs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Commit", reflect.TypeOf((*MockCommitter)(nil).Commit), varargs...)
+}
+
+// MockMessagePublisher is a mock of MessagePublisher interface.
+type MockMessagePublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockMessagePublisherMockRecorder
+	isgomock struct{}
+}
+
+// MockMessagePublisherMockRecorder is the mock recorder for MockMessagePublisher.
+type MockMessagePublisherMockRecorder struct {
+	mock *MockMessagePublisher
+}
+
+// NewMockMessagePublisher creates a new mock instance.
+func NewMockMessagePublisher(ctrl *gomock.Controller) *MockMessagePublisher {
+	mock := &MockMessagePublisher{ctrl: ctrl}
+	mock.recorder = &MockMessagePublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMessagePublisher) EXPECT() *MockMessagePublisherMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockMessagePublisher) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockMessagePublisherMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockMessagePublisher)(nil).Close))
+}
+
+// Publish mocks base method.
+func (m *MockMessagePublisher) Publish(ctx context.Context, msgs ...model.Message) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range msgs {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Publish", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockMessagePublisherMockRecorder) Publish(ctx any, msgs ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, msgs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockMessagePublisher)(nil).Publish), varargs...)
+}
@@ -71,6 +71,21 @@ func (mr *MockOrderRepositoryMockRecorder) GetAll(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockOrderRepository)(nil).GetAll), ctx)
 }
 
+// GetByTrackNumber mocks base method.
+func (m *MockOrderRepository) GetByTrackNumber(ctx context.Context, trackNumber string) ([]*model.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByTrackNumber", ctx, trackNumber)
+	ret0, _ := ret[0].([]*model.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByTrackNumber indicates an expected call of GetByTrackNumber.
+func (mr *MockOrderRepositoryMockRecorder) GetByTrackNumber(ctx, trackNumber any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByTrackNumber", reflect.TypeOf((*MockOrderRepository)(nil).GetByTrackNumber), ctx, trackNumber)
+}
+
 // GetByUID mocks base method.
 func (m *MockOrderRepository) GetByUID(ctx context.Context, orderUID string) (*model.Order, error) {
 	m.ctrl.T.Helper()
@@ -86,6 +101,35 @@ func (mr *MockOrderRepositoryMockRecorder) GetByUID(ctx, orderUID any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUID", reflect.TypeOf((*MockOrderRepository)(nil).GetByUID), ctx, orderUID)
 }
 
+// Iterate mocks base method.
+func (m *MockOrderRepository) Iterate(ctx context.Context, filter model.OrderFilter, fn func([]*model.Order, model.OrderCursor) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Iterate", ctx, filter, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Iterate indicates an expected call of Iterate.
+func (mr *MockOrderRepositoryMockRecorder) Iterate(ctx, filter, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Iterate", reflect.TypeOf((*MockOrderRepository)(nil).Iterate), ctx, filter, fn)
+}
+
+// List mocks base method.
+func (m *MockOrderRepository) List(ctx context.Context, filter model.OrderFilter) ([]*model.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, filter)
+	ret0, _ := ret[0].([]*model.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockOrderRepositoryMockRecorder) List(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockOrderRepository)(nil).List), ctx, filter)
+}
+
 // Save mocks base method.
 func (m *MockOrderRepository) Save(ctx context.Context, order *model.Order) error {
 	m.ctrl.T.Helper()
@@ -100,6 +144,34 @@ func (mr *MockOrderRepositoryMockRecorder) Save(ctx, order any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockOrderRepository)(nil).Save), ctx, order)
 }
 
+// SaveBatch mocks base method.
+func (m *MockOrderRepository) SaveBatch(ctx context.Context, orders []*model.Order) []error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveBatch", ctx, orders)
+	ret0, _ := ret[0].([]error)
+	return ret0
+}
+
+// SaveBatch indicates an expected call of SaveBatch.
+func (mr *MockOrderRepositoryMockRecorder) SaveBatch(ctx, orders any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveBatch", reflect.TypeOf((*MockOrderRepository)(nil).SaveBatch), ctx, orders)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockOrderRepository) UpdateStatus(ctx context.Context, orderUID string, from, to model.OrderStatus, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, orderUID, from, to, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockOrderRepositoryMockRecorder) UpdateStatus(ctx, orderUID, from, to, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockOrderRepository)(nil).UpdateStatus), ctx, orderUID, from, to, reason)
+}
+
 // MockOrderCache is a mock of OrderCache interface.
 type MockOrderCache struct {
 	ctrl     *gomock.Controller
@@ -152,6 +224,20 @@ func (mr *MockOrderCacheMockRecorder) Delete(ctx, orderUID any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockOrderCache)(nil).Delete), ctx, orderUID)
 }
 
+// Fill mocks base method.
+func (m *MockOrderCache) Fill(ctx context.Context, orders []*model.Order) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fill", ctx, orders)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Fill indicates an expected call of Fill.
+func (mr *MockOrderCacheMockRecorder) Fill(ctx, orders any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fill", reflect.TypeOf((*MockOrderCache)(nil).Fill), ctx, orders)
+}
+
 // Get mocks base method.
 func (m *MockOrderCache) Get(ctx context.Context, orderUID string) (*model.Order, error) {
 	m.ctrl.T.Helper()
@@ -167,6 +253,35 @@ func (mr *MockOrderCacheMockRecorder) Get(ctx, orderUID any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockOrderCache)(nil).Get), ctx, orderUID)
 }
 
+// GetByTrack mocks base method.
+func (m *MockOrderCache) GetByTrack(ctx context.Context, trackNumber string) ([]*model.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByTrack", ctx, trackNumber)
+	ret0, _ := ret[0].([]*model.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByTrack indicates an expected call of GetByTrack.
+func (mr *MockOrderCacheMockRecorder) GetByTrack(ctx, trackNumber any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByTrack", reflect.TypeOf((*MockOrderCache)(nil).GetByTrack), ctx, trackNumber)
+}
+
+// MarkMissing mocks base method.
+func (m *MockOrderCache) MarkMissing(ctx context.Context, orderUID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkMissing", ctx, orderUID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkMissing indicates an expected call of MarkMissing.
+func (mr *MockOrderCacheMockRecorder) MarkMissing(ctx, orderUID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkMissing", reflect.TypeOf((*MockOrderCache)(nil).MarkMissing), ctx, orderUID)
+}
+
 // Set mocks base method.
 func (m *MockOrderCache) Set(ctx context.Context, order *model.Order) error {
 	m.ctrl.T.Helper()
@@ -181,6 +296,201 @@ func (mr *MockOrderCacheMockRecorder) Set(ctx, order any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockOrderCache)(nil).Set), ctx, order)
 }
 
+// SetByTrack mocks base method.
+func (m *MockOrderCache) SetByTrack(ctx context.Context, trackNumber string, orders []*model.Order) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetByTrack", ctx, trackNumber, orders)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetByTrack indicates an expected call of SetByTrack.
+func (mr *MockOrderCacheMockRecorder) SetByTrack(ctx, trackNumber, orders any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetByTrack", reflect.TypeOf((*MockOrderCache)(nil).SetByTrack), ctx, trackNumber, orders)
+}
+
+// MockOrderCacheInspector is a mock of OrderCacheInspector interface.
+type MockOrderCacheInspector struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrderCacheInspectorMockRecorder
+	isgomock struct{}
+}
+
+// MockOrderCacheInspectorMockRecorder is the mock recorder for MockOrderCacheInspector.
+type MockOrderCacheInspectorMockRecorder struct {
+	mock *MockOrderCacheInspector
+}
+
+// NewMockOrderCacheInspector creates a new mock instance.
+func NewMockOrderCacheInspector(ctrl *gomock.Controller) *MockOrderCacheInspector {
+	mock := &MockOrderCacheInspector{ctrl: ctrl}
+	mock.recorder = &MockOrderCacheInspectorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrderCacheInspector) EXPECT() *MockOrderCacheInspectorMockRecorder {
+	return m.recorder
+}
+
+// Fill mocks base method.
+func (m *MockOrderCacheInspector) Fill(ctx context.Context, orders []*model.Order) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fill", ctx, orders)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Fill indicates an expected call of Fill.
+func (mr *MockOrderCacheInspectorMockRecorder) Fill(ctx, orders any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fill", reflect.TypeOf((*MockOrderCacheInspector)(nil).Fill), ctx, orders)
+}
+
+// Peek mocks base method.
+func (m *MockOrderCacheInspector) Peek(ctx context.Context, orderUID string) (*model.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Peek", ctx, orderUID)
+	ret0, _ := ret[0].(*model.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Peek indicates an expected call of Peek.
+func (mr *MockOrderCacheInspectorMockRecorder) Peek(ctx, orderUID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Peek", reflect.TypeOf((*MockOrderCacheInspector)(nil).Peek), ctx, orderUID)
+}
+
+// MockOrderSaver is a mock of OrderSaver interface.
+type MockOrderSaver struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrderSaverMockRecorder
+	isgomock struct{}
+}
+
+// MockOrderSaverMockRecorder is the mock recorder for MockOrderSaver.
+type MockOrderSaverMockRecorder struct {
+	mock *MockOrderSaver
+}
+
+// NewMockOrderSaver creates a new mock instance.
+func NewMockOrderSaver(ctrl *gomock.Controller) *MockOrderSaver {
+	mock := &MockOrderSaver{ctrl: ctrl}
+	mock.recorder = &MockOrderSaverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrderSaver) EXPECT() *MockOrderSaverMockRecorder {
+	return m.recorder
+}
+
+// Execute mocks base method.
+func (m *MockOrderSaver) Execute(ctx context.Context, order *model.Order) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Execute", ctx, order)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Execute indicates an expected call of Execute.
+func (mr *MockOrderSaverMockRecorder) Execute(ctx, order any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockOrderSaver)(nil).Execute), ctx, order)
+}
+
+// ExecuteBatch mocks base method.
+func (m *MockOrderSaver) ExecuteBatch(ctx context.Context, orders []*model.Order) []error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteBatch", ctx, orders)
+	ret0, _ := ret[0].([]error)
+	return ret0
+}
+
+// ExecuteBatch indicates an expected call of ExecuteBatch.
+func (mr *MockOrderSaverMockRecorder) ExecuteBatch(ctx, orders any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteBatch", reflect.TypeOf((*MockOrderSaver)(nil).ExecuteBatch), ctx, orders)
+}
+
+// MockOrderValidator is a mock of OrderValidator interface.
+type MockOrderValidator struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrderValidatorMockRecorder
+	isgomock struct{}
+}
+
+// MockOrderValidatorMockRecorder is the mock recorder for MockOrderValidator.
+type MockOrderValidatorMockRecorder struct {
+	mock *MockOrderValidator
+}
+
+// NewMockOrderValidator creates a new mock instance.
+func NewMockOrderValidator(ctrl *gomock.Controller) *MockOrderValidator {
+	mock := &MockOrderValidator{ctrl: ctrl}
+	mock.recorder = &MockOrderValidatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrderValidator) EXPECT() *MockOrderValidatorMockRecorder {
+	return m.recorder
+}
+
+// Validate mocks base method.
+func (m *MockOrderValidator) Validate(ctx context.Context, order *model.Order) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Validate", ctx, order)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Validate indicates an expected call of Validate.
+func (mr *MockOrderValidatorMockRecorder) Validate(ctx, order any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Validate", reflect.TypeOf((*MockOrderValidator)(nil).Validate), ctx, order)
+}
+
+// MockStatusChanger is a mock of StatusChanger interface.
+type MockStatusChanger struct {
+	ctrl     *gomock.Controller
+	recorder *MockStatusChangerMockRecorder
+	isgomock struct{}
+}
+
+// MockStatusChangerMockRecorder is the mock recorder for MockStatusChanger.
+type MockStatusChangerMockRecorder struct {
+	mock *MockStatusChanger
+}
+
+// NewMockStatusChanger creates a new mock instance.
+func NewMockStatusChanger(ctrl *gomock.Controller) *MockStatusChanger {
+	mock := &MockStatusChanger{ctrl: ctrl}
+	mock.recorder = &MockStatusChangerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStatusChanger) EXPECT() *MockStatusChangerMockRecorder {
+	return m.recorder
+}
+
+// Execute mocks base method.
+func (m *MockStatusChanger) Execute(ctx context.Context, change *model.StatusChange) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Execute", ctx, change)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Execute indicates an expected call of Execute.
+func (mr *MockStatusChangerMockRecorder) Execute(ctx, change any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockStatusChanger)(nil).Execute), ctx, change)
+}
+
 // MockOrderUseCaseProvider is a mock of OrderUseCaseProvider interface.
 type MockOrderUseCaseProvider struct {
 	ctrl     *gomock.Controller
@@ -219,3 +529,81 @@ func (mr *MockOrderUseCaseProviderMockRecorder) Execute(ctx, orderUID any) *gomo
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockOrderUseCaseProvider)(nil).Execute), ctx, orderUID)
 }
+
+// MockOrderListProvider is a mock of OrderListProvider interface.
+type MockOrderListProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrderListProviderMockRecorder
+	isgomock struct{}
+}
+
+// MockOrderListProviderMockRecorder is the mock recorder for MockOrderListProvider.
+type MockOrderListProviderMockRecorder struct {
+	mock *MockOrderListProvider
+}
+
+// NewMockOrderListProvider creates a new mock instance.
+func NewMockOrderListProvider(ctrl *gomock.Controller) *MockOrderListProvider {
+	mock := &MockOrderListProvider{ctrl: ctrl}
+	mock.recorder = &MockOrderListProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrderListProvider) EXPECT() *MockOrderListProviderMockRecorder {
+	return m.recorder
+}
+
+// Execute mocks base method.
+func (m *MockOrderListProvider) Execute(ctx context.Context, filter model.OrderFilter) (*model.OrderPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Execute", ctx, filter)
+	ret0, _ := ret[0].(*model.OrderPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Execute indicates an expected call of Execute.
+func (mr *MockOrderListProviderMockRecorder) Execute(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockOrderListProvider)(nil).Execute), ctx, filter)
+}
+
+// MockOrderTrackProvider is a mock of OrderTrackProvider interface.
+type MockOrderTrackProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrderTrackProviderMockRecorder
+	isgomock struct{}
+}
+
+// MockOrderTrackProviderMockRecorder is the mock recorder for MockOrderTrackProvider.
+type MockOrderTrackProviderMockRecorder struct {
+	mock *MockOrderTrackProvider
+}
+
+// NewMockOrderTrackProvider creates a new mock instance.
+func NewMockOrderTrackProvider(ctrl *gomock.Controller) *MockOrderTrackProvider {
+	mock := &MockOrderTrackProvider{ctrl: ctrl}
+	mock.recorder = &MockOrderTrackProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrderTrackProvider) EXPECT() *MockOrderTrackProviderMockRecorder {
+	return m.recorder
+}
+
+// Execute mocks base method.
+func (m *MockOrderTrackProvider) Execute(ctx context.Context, trackNumber string) ([]*model.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Execute", ctx, trackNumber)
+	ret0, _ := ret[0].([]*model.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Execute indicates an expected call of Execute.
+func (mr *MockOrderTrackProviderMockRecorder) Execute(ctx, trackNumber any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockOrderTrackProvider)(nil).Execute), ctx, trackNumber)
+}
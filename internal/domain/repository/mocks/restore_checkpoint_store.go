@@ -0,0 +1,86 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: restore_checkpoint_store.go
+//
+// Generated by this command:
+//
+//	mockgen -source=restore_checkpoint_store.go -destination=mocks/restore_checkpoint_store.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	model "l0/internal/domain/model"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRestoreCheckpointStore is a mock of RestoreCheckpointStore interface.
+type MockRestoreCheckpointStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockRestoreCheckpointStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockRestoreCheckpointStoreMockRecorder is the mock recorder for MockRestoreCheckpointStore.
+type MockRestoreCheckpointStoreMockRecorder struct {
+	mock *MockRestoreCheckpointStore
+}
+
+// NewMockRestoreCheckpointStore creates a new mock instance.
+func NewMockRestoreCheckpointStore(ctrl *gomock.Controller) *MockRestoreCheckpointStore {
+	mock := &MockRestoreCheckpointStore{ctrl: ctrl}
+	mock.recorder = &MockRestoreCheckpointStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRestoreCheckpointStore) EXPECT() *MockRestoreCheckpointStoreMockRecorder {
+	return m.recorder
+}
+
+// Clear mocks base method.
+func (m *MockRestoreCheckpointStore) Clear(ctx context.Context, since time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Clear", ctx, since)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Clear indicates an expected call of Clear.
+func (mr *MockRestoreCheckpointStoreMockRecorder) Clear(ctx, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Clear", reflect.TypeOf((*MockRestoreCheckpointStore)(nil).Clear), ctx, since)
+}
+
+// Load mocks base method.
+func (m *MockRestoreCheckpointStore) Load(ctx context.Context, since time.Time) (*model.RestoreCheckpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Load", ctx, since)
+	ret0, _ := ret[0].(*model.RestoreCheckpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Load indicates an expected call of Load.
+func (mr *MockRestoreCheckpointStoreMockRecorder) Load(ctx, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Load", reflect.TypeOf((*MockRestoreCheckpointStore)(nil).Load), ctx, since)
+}
+
+// Save mocks base method.
+func (m *MockRestoreCheckpointStore) Save(ctx context.Context, checkpoint *model.RestoreCheckpoint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, checkpoint)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockRestoreCheckpointStoreMockRecorder) Save(ctx, checkpoint any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockRestoreCheckpointStore)(nil).Save), ctx, checkpoint)
+}
@@ -9,18 +9,72 @@ import (
 //go:generate mockgen -source=order_repository.go -destination=mocks/order_repository.go -package=mocks
 type OrderRepository interface {
 	Save(ctx context.Context, order *model.Order) error
+	SaveBatch(ctx context.Context, orders []*model.Order) []error
 	GetByUID(ctx context.Context, orderUID string) (*model.Order, error)
 	GetAll(ctx context.Context) ([]*model.Order, error)
+	List(ctx context.Context, filter model.OrderFilter) ([]*model.Order, error)
+	// Iterate walks every order matching filter in keyset order, handing
+	// them to fn in chunks of filter.Limit together with the cursor of the
+	// chunk's last order. filter.Cursor resumes a previous walk; an error
+	// returned by fn stops the walk and is returned as is.
+	Iterate(ctx context.Context, filter model.OrderFilter, fn func(chunk []*model.Order, last model.OrderCursor) error) error
+	GetByTrackNumber(ctx context.Context, trackNumber string) ([]*model.Order, error)
 	Exists(ctx context.Context, orderUID string) (bool, error)
+	UpdateStatus(ctx context.Context, orderUID string, from, to model.OrderStatus, reason string) error
 }
 
 type OrderCache interface {
+	// Get returns nil on a miss and model.ErrOrderNotFound when the order is
+	// known not to exist, see MarkMissing.
 	Get(ctx context.Context, orderUID string) (*model.Order, error)
+	// Set caches the order and clears a MarkMissing record for it.
 	Set(ctx context.Context, order *model.Order) error
+	// MarkMissing remembers for a short while that the order does not exist,
+	// so repeated lookups of an unknown UID don't reach the DB.
+	MarkMissing(ctx context.Context, orderUID string) error
+	// GetByTrack returns nil on a miss and a non-nil, possibly empty, slice
+	// when the track number is indexed.
+	GetByTrack(ctx context.Context, trackNumber string) ([]*model.Order, error)
+	SetByTrack(ctx context.Context, trackNumber string, orders []*model.Order) error
+	// Fill caches the orders that are not cached yet in one round trip,
+	// leaving existing entries alone.
+	Fill(ctx context.Context, orders []*model.Order) error
 	Delete(ctx context.Context, orderUID string) error
 	Close() error
 }
 
+// OrderCacheInspector reads a cache tier for housekeeping such as
+// reconciliation, which should leave no trace on what it inspects.
+type OrderCacheInspector interface {
+	// Peek is OrderCache.Get without side effects: it does not extend a
+	// sliding TTL.
+	Peek(ctx context.Context, orderUID string) (*model.Order, error)
+	Fill(ctx context.Context, orders []*model.Order) error
+}
+
+type OrderSaver interface {
+	Execute(ctx context.Context, order *model.Order) error
+	ExecuteBatch(ctx context.Context, orders []*model.Order) []error
+}
+
+// OrderValidator checks an order before it is saved. Failures are a
+// *model.ValidationError or wrap model.ErrInvalidOrderData.
+type OrderValidator interface {
+	Validate(ctx context.Context, order *model.Order) error
+}
+
+type StatusChanger interface {
+	Execute(ctx context.Context, change *model.StatusChange) error
+}
+
 type OrderUseCaseProvider interface {
 	Execute(ctx context.Context, orderUID string) (*model.Order, error)
 }
+
+type OrderListProvider interface {
+	Execute(ctx context.Context, filter model.OrderFilter) (*model.OrderPage, error)
+}
+
+type OrderTrackProvider interface {
+	Execute(ctx context.Context, trackNumber string) ([]*model.Order, error)
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"l0/internal/domain/model"
+)
+
+//go:generate mockgen -source=outbox_repository.go -destination=mocks/outbox_repository.go -package=mocks
+type OutboxRepository interface {
+	// PublishPending claims up to limit unpublished events, oldest first, and
+	// hands them to publish. The events are marked as published if publish
+	// succeeds and released otherwise. Events claimed by another caller are
+	// skipped, so concurrent relays never publish the same event twice.
+	PublishPending(ctx context.Context, limit int, publish func(ctx context.Context, events []model.OutboxEvent) error) (int, error)
+	DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error)
+}
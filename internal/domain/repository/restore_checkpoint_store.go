@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"l0/internal/domain/model"
+)
+
+//go:generate mockgen -source=restore_checkpoint_store.go -destination=mocks/restore_checkpoint_store.go -package=mocks
+type RestoreCheckpointStore interface {
+	// Load returns nil when there is no restore of orders created after
+	// since to resume.
+	Load(ctx context.Context, since time.Time) (*model.RestoreCheckpoint, error)
+	Save(ctx context.Context, checkpoint *model.RestoreCheckpoint) error
+	Clear(ctx context.Context, since time.Time) error
+}
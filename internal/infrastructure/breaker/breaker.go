@@ -0,0 +1,192 @@
+// Package breaker stops calls to a failing dependency for a while, so an
+// outage costs a quick error instead of a timeout per call, and probes the
+// dependency on a schedule to find out when it is back.
+package breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"l0/internal/infrastructure/metrics"
+
+	"go.uber.org/zap"
+)
+
+var ErrOpen = errors.New("circuit breaker is open")
+
+type State int
+
+const (
+	StateClosed State = iota
+	StateOpen
+)
+
+func (s State) String() string {
+	if s == StateOpen {
+		return "open"
+	}
+	return "closed"
+}
+
+type Options struct {
+	// FailureThreshold is how many failures in a row open the breaker.
+	FailureThreshold int
+	// ProbeInterval is how often an open breaker probes the dependency.
+	ProbeInterval time.Duration
+	ProbeTimeout  time.Duration
+}
+
+// Breaker opens after FailureThreshold consecutive failures. While it is
+// open, a goroutine runs the probe every ProbeInterval, and the first probe
+// that succeeds runs the recovery hook and closes the breaker again.
+type Breaker struct {
+	name      string
+	probe     func(ctx context.Context) error
+	opts      Options
+	onRecover func(ctx context.Context) error
+	logger    *zap.Logger
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	stop     chan struct{}
+	probing  sync.WaitGroup
+}
+
+func New(name string, probe func(ctx context.Context) error, opts Options, logger *zap.Logger) *Breaker {
+	metrics.BreakerState.WithLabelValues(name).Set(float64(StateClosed))
+	return &Breaker{name: name, probe: probe, opts: opts, logger: logger, stop: make(chan struct{})}
+}
+
+// OnRecover sets a hook that runs after a successful probe, before the
+// breaker closes. If it fails the breaker stays open and the next probe
+// tries again. It runs once more right after closing, for work that arrived
+// while the first run was in progress.
+func (b *Breaker) OnRecover(fn func(ctx context.Context) error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.onRecover = fn
+}
+
+// Allow reports whether a call may go through.
+func (b *Breaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state == StateClosed
+}
+
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// Record counts the outcome of a call that Allow let through.
+func (b *Breaker) Record(failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateOpen {
+		return
+	}
+	if !failed {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures < b.opts.FailureThreshold {
+		return
+	}
+
+	b.setState(StateOpen)
+	b.openedAt = time.Now()
+	b.logger.Warn("Circuit breaker opened", zap.String("breaker", b.name), zap.Int("failures", b.failures))
+	b.probing.Add(1)
+	go b.probeUntilRecovered()
+}
+
+// Check is a health check that fails while the breaker is open.
+func (b *Breaker) Check(context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == StateOpen {
+		return fmt.Errorf("%w since %s", ErrOpen, b.openedAt.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// Close stops probing.
+func (b *Breaker) Close() {
+	b.mu.Lock()
+	select {
+	case <-b.stop:
+	default:
+		close(b.stop)
+	}
+	b.mu.Unlock()
+	b.probing.Wait()
+}
+
+func (b *Breaker) probeUntilRecovered() {
+	defer b.probing.Done()
+
+	ticker := time.NewTicker(b.opts.ProbeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-b.stop:
+			return
+		case <-ticker.C:
+		}
+		if b.tryRecover() {
+			return
+		}
+	}
+}
+
+func (b *Breaker) tryRecover() bool {
+	probeCtx, cancel := context.WithTimeout(context.Background(), b.opts.ProbeTimeout)
+	defer cancel()
+	if err := b.probe(probeCtx); err != nil {
+		b.logger.Debug("Circuit breaker probe failed", zap.String("breaker", b.name), zap.Error(err))
+		return false
+	}
+
+	// The hook is bounded by the dependency's own call timeouts rather than
+	// the probe's, since it may have a backlog to work through.
+	ctx := context.Background()
+	b.mu.Lock()
+	onRecover := b.onRecover
+	b.mu.Unlock()
+	if onRecover != nil {
+		if err := onRecover(ctx); err != nil {
+			b.logger.Warn("Circuit breaker recovery failed, staying open", zap.String("breaker", b.name), zap.Error(err))
+			return false
+		}
+	}
+
+	b.mu.Lock()
+	b.setState(StateClosed)
+	b.failures = 0
+	downtime := time.Since(b.openedAt)
+	b.mu.Unlock()
+	b.logger.Info("Circuit breaker closed", zap.String("breaker", b.name), zap.Duration("open_for", downtime))
+
+	if onRecover != nil {
+		if err := onRecover(ctx); err != nil {
+			b.logger.Warn("Circuit breaker recovery after closing failed", zap.String("breaker", b.name), zap.Error(err))
+		}
+	}
+	return true
+}
+
+// setState must be called with mu held.
+func (b *Breaker) setState(state State) {
+	b.state = state
+	metrics.BreakerState.WithLabelValues(b.name).Set(float64(state))
+	metrics.BreakerTransitions.WithLabelValues(b.name, state.String()).Inc()
+}
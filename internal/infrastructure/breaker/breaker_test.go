@@ -0,0 +1,212 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"l0/internal/domain/model"
+	"l0/internal/domain/repository/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+var errRedisDown = errors.New("dial tcp: connection refused")
+
+func newTestBreaker(t *testing.T, probe func(context.Context) error) *Breaker {
+	t.Helper()
+	b := New(t.Name(), probe, Options{
+		FailureThreshold: 3,
+		ProbeInterval:    10 * time.Millisecond,
+		ProbeTimeout:     time.Second,
+	}, zap.NewNop())
+	t.Cleanup(b.Close)
+	return b
+}
+
+func TestBreaker_OpensAfterConsecutiveFailures(t *testing.T) {
+	t.Parallel()
+
+	b := newTestBreaker(t, func(context.Context) error { return errRedisDown })
+
+	b.Record(true)
+	b.Record(true)
+	b.Record(false)
+	b.Record(true)
+	b.Record(true)
+	assert.True(t, b.Allow(), "a success resets the failure count")
+	assert.NoError(t, b.Check(context.Background()))
+
+	b.Record(true)
+	assert.False(t, b.Allow())
+	assert.Equal(t, StateOpen, b.State())
+	assert.ErrorIs(t, b.Check(context.Background()), ErrOpen)
+}
+
+func TestBreaker_ProbesUntilRecovered(t *testing.T) {
+	t.Parallel()
+
+	var (
+		healthy   atomic.Bool
+		recovered atomic.Int32
+	)
+	b := newTestBreaker(t, func(context.Context) error {
+		if healthy.Load() {
+			return nil
+		}
+		return errRedisDown
+	})
+	b.OnRecover(func(context.Context) error {
+		recovered.Add(1)
+		return nil
+	})
+
+	for range 3 {
+		b.Record(true)
+	}
+	time.Sleep(50 * time.Millisecond)
+	require.False(t, b.Allow(), "must stay open while probes fail")
+
+	healthy.Store(true)
+	require.Eventually(t, b.Allow, time.Second, 5*time.Millisecond)
+	assert.Equal(t, int32(2), recovered.Load(), "the hook runs before and right after closing")
+}
+
+func TestBreaker_StaysOpenWhileRecoveryFails(t *testing.T) {
+	t.Parallel()
+
+	var attempts atomic.Int32
+	b := newTestBreaker(t, func(context.Context) error { return nil })
+	b.OnRecover(func(context.Context) error {
+		if attempts.Add(1) < 3 {
+			return errRedisDown
+		}
+		return nil
+	})
+
+	for range 3 {
+		b.Record(true)
+	}
+	require.Eventually(t, b.Allow, time.Second, 5*time.Millisecond)
+	assert.GreaterOrEqual(t, attempts.Load(), int32(3))
+}
+
+func TestOrderCache_DegradesWhileOpen(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	next := mocks.NewMockOrderCache(ctrl)
+
+	var healthy atomic.Bool
+	b := newTestBreaker(t, func(context.Context) error {
+		if healthy.Load() {
+			return nil
+		}
+		return errRedisDown
+	})
+	c := NewOrderCache(next, b, zap.NewNop())
+	ctx := context.Background()
+	order := &model.Order{OrderUID: "order-1"}
+
+	next.EXPECT().Get(ctx, "order-1").Return(nil, errRedisDown).Times(3)
+	for range 3 {
+		_, err := c.Get(ctx, "order-1")
+		require.Error(t, err)
+	}
+	require.Equal(t, StateOpen, b.State())
+
+	// While open nothing reaches the wrapped cache.
+	got, err := c.Get(ctx, "order-1")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+	assert.NoError(t, c.Set(ctx, order))
+	assert.NoError(t, c.MarkMissing(ctx, "order-2"))
+	tracked, err := c.GetByTrack(ctx, "TRACK")
+	require.NoError(t, err)
+	assert.Nil(t, tracked)
+	assert.ErrorIs(t, c.Fill(ctx, []*model.Order{order}), ErrOpen)
+
+	// On recovery the order whose write was skipped is dropped, so the
+	// version cached before the outage is not served.
+	next.EXPECT().Delete(gomock.Any(), "order-1").Return(nil)
+	healthy.Store(true)
+	require.Eventually(t, b.Allow, time.Second, 5*time.Millisecond)
+
+	next.EXPECT().Get(ctx, "order-1").Return(nil, nil)
+	got, err = c.Get(ctx, "order-1")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func TestOrderCache_KnownMissingIsNotAFailure(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	next := mocks.NewMockOrderCache(ctrl)
+	b := newTestBreaker(t, func(context.Context) error { return nil })
+	c := NewOrderCache(next, b, zap.NewNop())
+	ctx := context.Background()
+
+	next.EXPECT().Get(ctx, "probed").Return(nil, model.ErrOrderNotFound).Times(5)
+	for range 5 {
+		_, err := c.Get(ctx, "probed")
+		assert.ErrorIs(t, err, model.ErrOrderNotFound)
+	}
+	assert.True(t, b.Allow())
+}
+
+func TestIdempotencyStore_FailsFastWhileOpen(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	next := mocks.NewMockIdempotencyStore(ctrl)
+	b := newTestBreaker(t, func(context.Context) error { return errRedisDown })
+	s := NewIdempotencyStore(next, b)
+	ctx := context.Background()
+
+	next.EXPECT().Reserve(ctx, "key-1", "hash").Return(nil, false, errRedisDown).Times(3)
+	for range 3 {
+		_, _, err := s.Reserve(ctx, "key-1", "hash")
+		assert.ErrorIs(t, err, errRedisDown)
+	}
+	require.Equal(t, StateOpen, b.State())
+
+	// The wrapped store is not called again while the breaker is open.
+	_, reserved, err := s.Reserve(ctx, "key-1", "hash")
+	assert.ErrorIs(t, err, ErrOpen)
+	assert.False(t, reserved)
+	assert.ErrorIs(t, s.Complete(ctx, "key-1", &model.IdempotencyRecord{}), ErrOpen)
+	assert.ErrorIs(t, s.Release(ctx, "key-1"), ErrOpen)
+}
+
+func TestOrderCacheInspector_FailsWhileOpen(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	next := mocks.NewMockOrderCacheInspector(ctrl)
+	b := newTestBreaker(t, func(context.Context) error { return errRedisDown })
+	c := NewOrderCacheInspector(next, b)
+	ctx := context.Background()
+
+	next.EXPECT().Peek(ctx, "order-1").Return(nil, model.ErrOrderNotFound)
+	_, err := c.Peek(ctx, "order-1")
+	assert.ErrorIs(t, err, model.ErrOrderNotFound)
+
+	next.EXPECT().Peek(ctx, "order-1").Return(nil, errRedisDown).Times(3)
+	for range 3 {
+		_, err = c.Peek(ctx, "order-1")
+		assert.ErrorIs(t, err, errRedisDown)
+	}
+	require.Equal(t, StateOpen, b.State())
+
+	// An open breaker is an error rather than a miss, so a reconciliation
+	// pass stops instead of refilling every order.
+	_, err = c.Peek(ctx, "order-1")
+	assert.ErrorIs(t, err, ErrOpen)
+	assert.ErrorIs(t, c.Fill(ctx, []*model.Order{{OrderUID: "order-1"}}), ErrOpen)
+}
@@ -0,0 +1,170 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"l0/internal/domain/model"
+	"l0/internal/domain/repository"
+	"l0/internal/infrastructure/metrics"
+
+	"go.uber.org/zap"
+)
+
+// maxSkippedWrites bounds the memory kept for writes skipped during an
+// outage. Past it the cache may serve stale orders until they expire.
+const maxSkippedWrites = 100_000
+
+// OrderCache degrades the wrapped cache to a no-op while the breaker is open:
+// lookups miss, so callers fall back to the DB, and writes are skipped. The
+// orders whose writes were skipped are deleted from the cache when it comes
+// back, before the breaker closes, so it never serves the versions they
+// replaced.
+type OrderCache struct {
+	next    repository.OrderCache
+	breaker *Breaker
+	logger  *zap.Logger
+
+	mu         sync.Mutex
+	skipped    map[string]struct{}
+	overflowed bool
+}
+
+func NewOrderCache(next repository.OrderCache, breaker *Breaker, logger *zap.Logger) repository.OrderCache {
+	c := &OrderCache{next: next, breaker: breaker, logger: logger, skipped: make(map[string]struct{})}
+	breaker.OnRecover(c.dropSkipped)
+	return c
+}
+
+// isFailure tells outages from answers: a known missing order is a result,
+// and a caller giving up says nothing about the cache.
+func isFailure(err error) bool {
+	return err != nil && !errors.Is(err, model.ErrOrderNotFound) && !errors.Is(err, context.Canceled)
+}
+
+func (c *OrderCache) allow(operation string) bool {
+	if c.breaker.Allow() {
+		return true
+	}
+	metrics.BreakerShortCircuits.WithLabelValues(c.breaker.name, operation).Inc()
+	return false
+}
+
+func (c *OrderCache) Get(ctx context.Context, orderUID string) (*model.Order, error) {
+	if !c.allow("get") {
+		return nil, nil
+	}
+	order, err := c.next.Get(ctx, orderUID)
+	c.breaker.Record(isFailure(err))
+	return order, err
+}
+
+func (c *OrderCache) Set(ctx context.Context, order *model.Order) error {
+	return c.write("set", order.OrderUID, func() error { return c.next.Set(ctx, order) })
+}
+
+func (c *OrderCache) Delete(ctx context.Context, orderUID string) error {
+	return c.write("delete", orderUID, func() error { return c.next.Delete(ctx, orderUID) })
+}
+
+// write skips fn while the breaker is open. A write that fails may or may not
+// have reached the cache, so it is remembered just like a skipped one.
+func (c *OrderCache) write(operation, orderUID string, fn func() error) error {
+	if !c.allow(operation) {
+		c.skip(orderUID)
+		return nil
+	}
+	err := fn()
+	failed := isFailure(err)
+	if failed {
+		c.skip(orderUID)
+	}
+	c.breaker.Record(failed)
+	return err
+}
+
+func (c *OrderCache) MarkMissing(ctx context.Context, orderUID string) error {
+	if !c.allow("mark_missing") {
+		return nil
+	}
+	err := c.next.MarkMissing(ctx, orderUID)
+	c.breaker.Record(isFailure(err))
+	return err
+}
+
+func (c *OrderCache) GetByTrack(ctx context.Context, trackNumber string) ([]*model.Order, error) {
+	if !c.allow("get_by_track") {
+		return nil, nil
+	}
+	orders, err := c.next.GetByTrack(ctx, trackNumber)
+	c.breaker.Record(isFailure(err))
+	return orders, err
+}
+
+func (c *OrderCache) SetByTrack(ctx context.Context, trackNumber string, orders []*model.Order) error {
+	if !c.allow("set_by_track") {
+		return nil
+	}
+	err := c.next.SetByTrack(ctx, trackNumber, orders)
+	c.breaker.Record(isFailure(err))
+	return err
+}
+
+// Fill fails rather than skipping, so a cache restore stops at its
+// checkpoint and resumes later instead of walking the DB for nothing.
+func (c *OrderCache) Fill(ctx context.Context, orders []*model.Order) error {
+	if !c.allow("fill") {
+		return ErrOpen
+	}
+	err := c.next.Fill(ctx, orders)
+	c.breaker.Record(isFailure(err))
+	return err
+}
+
+func (c *OrderCache) Close() error {
+	c.breaker.Close()
+	return c.next.Close()
+}
+
+func (c *OrderCache) skip(orderUID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.skipped) >= maxSkippedWrites {
+		c.overflowed = true
+		return
+	}
+	c.skipped[orderUID] = struct{}{}
+}
+
+// dropSkipped deletes the orders whose writes were skipped. Orders that
+// could not be deleted stay on the list for the next attempt.
+func (c *OrderCache) dropSkipped(ctx context.Context) error {
+	c.mu.Lock()
+	uids := make([]string, 0, len(c.skipped))
+	for uid := range c.skipped {
+		uids = append(uids, uid)
+	}
+	overflowed := c.overflowed
+	c.overflowed = false
+	c.mu.Unlock()
+
+	if overflowed {
+		c.logger.Error("Too many cache writes skipped during the outage to track, cached orders may be stale until they expire",
+			zap.Int("limit", maxSkippedWrites))
+	}
+
+	for _, uid := range uids {
+		if err := c.next.Delete(ctx, uid); err != nil {
+			return fmt.Errorf("failed to drop order %s skipped during the outage: %w", uid, err)
+		}
+		c.mu.Lock()
+		delete(c.skipped, uid)
+		c.mu.Unlock()
+	}
+	if len(uids) > 0 {
+		c.logger.Info("Dropped orders whose cache writes were skipped during the outage", zap.Int("orders", len(uids)))
+	}
+	return nil
+}
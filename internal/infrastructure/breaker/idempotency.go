@@ -0,0 +1,57 @@
+package breaker
+
+import (
+	"context"
+
+	"l0/internal/domain/model"
+	"l0/internal/domain/repository"
+	"l0/internal/infrastructure/metrics"
+)
+
+// IdempotencyStore fails calls to the wrapped store with ErrOpen while the
+// breaker is open, so an ingest request does not wait out a Redis timeout
+// before it is refused for want of its Idempotency-Key. Its failures count towards
+// the same breaker as the order cache's, as both live in the same Redis.
+type IdempotencyStore struct {
+	next    repository.IdempotencyStore
+	breaker *Breaker
+}
+
+func NewIdempotencyStore(next repository.IdempotencyStore, breaker *Breaker) repository.IdempotencyStore {
+	return &IdempotencyStore{next: next, breaker: breaker}
+}
+
+func (s *IdempotencyStore) allow(operation string) bool {
+	if s.breaker.Allow() {
+		return true
+	}
+	metrics.BreakerShortCircuits.WithLabelValues(s.breaker.name, operation).Inc()
+	return false
+}
+
+func (s *IdempotencyStore) Reserve(ctx context.Context, key, requestHash string) (*model.IdempotencyRecord, bool, error) {
+	if !s.allow("idempotency_reserve") {
+		return nil, false, ErrOpen
+	}
+	record, reserved, err := s.next.Reserve(ctx, key, requestHash)
+	s.breaker.Record(isFailure(err))
+	return record, reserved, err
+}
+
+func (s *IdempotencyStore) Complete(ctx context.Context, key string, record *model.IdempotencyRecord) error {
+	if !s.allow("idempotency_complete") {
+		return ErrOpen
+	}
+	err := s.next.Complete(ctx, key, record)
+	s.breaker.Record(isFailure(err))
+	return err
+}
+
+func (s *IdempotencyStore) Release(ctx context.Context, key string) error {
+	if !s.allow("idempotency_release") {
+		return ErrOpen
+	}
+	err := s.next.Release(ctx, key)
+	s.breaker.Record(isFailure(err))
+	return err
+}
@@ -0,0 +1,47 @@
+package breaker
+
+import (
+	"context"
+
+	"l0/internal/domain/model"
+	"l0/internal/domain/repository"
+	"l0/internal/infrastructure/metrics"
+)
+
+// OrderCacheInspector fails calls with ErrOpen while the breaker is open,
+// so housekeeping stops at the first order instead of taking every order it
+// reads for a miss.
+type OrderCacheInspector struct {
+	next    repository.OrderCacheInspector
+	breaker *Breaker
+}
+
+func NewOrderCacheInspector(next repository.OrderCacheInspector, breaker *Breaker) repository.OrderCacheInspector {
+	return &OrderCacheInspector{next: next, breaker: breaker}
+}
+
+func (c *OrderCacheInspector) allow(operation string) bool {
+	if c.breaker.Allow() {
+		return true
+	}
+	metrics.BreakerShortCircuits.WithLabelValues(c.breaker.name, operation).Inc()
+	return false
+}
+
+func (c *OrderCacheInspector) Peek(ctx context.Context, orderUID string) (*model.Order, error) {
+	if !c.allow("peek") {
+		return nil, ErrOpen
+	}
+	order, err := c.next.Peek(ctx, orderUID)
+	c.breaker.Record(isFailure(err))
+	return order, err
+}
+
+func (c *OrderCacheInspector) Fill(ctx context.Context, orders []*model.Order) error {
+	if !c.allow("fill") {
+		return ErrOpen
+	}
+	err := c.next.Fill(ctx, orders)
+	c.breaker.Record(isFailure(err))
+	return err
+}
@@ -2,10 +2,11 @@ package cache
 
 import (
 	"context"
-	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"l0/internal/domain/model"
@@ -15,32 +16,90 @@ import (
 )
 
 type Cache struct {
-	client *redis.Client
-	logger *zap.Logger
+	client     *redis.Client
+	orderTTL   time.Duration
+	slidingTTL bool
+	missingTTL time.Duration
+
+	dialTimeout time.Duration
+	ioTimeout   time.Duration
+	maxRetries  int
+	logger      *zap.Logger
 }
 
-func NewCache(addr string, logger *zap.Logger) *Cache {
-	client := redis.NewClient(&redis.Options{
+// Option configures a Cache.
+type Option func(*Cache)
+
+// WithOrderTTL expires a cached order ttl after it was written. Zero keeps
+// orders until Redis evicts them.
+func WithOrderTTL(ttl time.Duration) Option {
+	return func(c *Cache) {
+		c.orderTTL = ttl
+	}
+}
+
+// WithSlidingExpiration restarts an order's TTL on every read, so orders that
+// keep being looked up stay cached while cold ones age out. It has no effect
+// without WithOrderTTL.
+func WithSlidingExpiration() Option {
+	return func(c *Cache) {
+		c.slidingTTL = true
+	}
+}
+
+// WithMissingTTL sets how long MarkOrderMissing remembers an unknown order.
+func WithMissingTTL(ttl time.Duration) Option {
+	return func(c *Cache) {
+		c.missingTTL = ttl
+	}
+}
+
+// WithTimeouts bounds how long a Redis call may take: dial for connecting,
+// io for each read and write, with up to maxRetries retries.
+func WithTimeouts(dial, io time.Duration, maxRetries int) Option {
+	return func(c *Cache) {
+		c.dialTimeout = dial
+		c.ioTimeout = io
+		c.maxRetries = maxRetries
+	}
+}
+
+// NewCache does not wait for Redis: if it is down at startup, calls fail
+// until it comes up, which the circuit breaker in front of the cache turns
+// into quick misses.
+func NewCache(addr string, logger *zap.Logger, opts ...Option) *Cache {
+	c := &Cache{
+		missingTTL:  defaultMissingTTL,
+		dialTimeout: time.Second,
+		ioTimeout:   500 * time.Millisecond,
+		maxRetries:  1,
+		logger:      logger,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	c.client = redis.NewClient(&redis.Options{
 		Addr:         addr,
 		Password:     "",
 		DB:           0,
-		DialTimeout:  10 * time.Second,
-		ReadTimeout:  10 * time.Second,
-		WriteTimeout: 10 * time.Second,
-		MaxRetries:   3,
+		DialTimeout:  c.dialTimeout,
+		ReadTimeout:  c.ioTimeout,
+		WriteTimeout: c.ioTimeout,
+		MaxRetries:   c.maxRetries,
 	})
 
-	ctx := context.Background()
-	for i := range 3 {
-		_, err := client.Ping(ctx).Result()
-		if err == nil {
-			logger.Info("Connected to Redis", zap.String("addr", addr))
-			break
-		}
-		logger.Warn("Failed to connect to Redis, retrying...", zap.Error(err), zap.Int("attempt", i+1))
-		time.Sleep(2 * time.Second)
+	if err := c.client.Ping(context.Background()).Err(); err != nil {
+		logger.Warn("Redis is not reachable yet", zap.Error(err), zap.String("addr", addr))
+	} else {
+		logger.Info("Connected to Redis", zap.String("addr", addr))
 	}
-	return &Cache{client: client, logger: logger}
+	return c
+}
+
+// sliding reports whether reads should push an order's expiry back.
+func (c *Cache) sliding() bool {
+	return c.slidingTTL && c.orderTTL > 0
 }
 
 func (c *Cache) SaveOrder(ctx context.Context, order model.Order) error {
@@ -49,7 +108,10 @@ func (c *Cache) SaveOrder(ctx context.Context, order model.Order) error {
 		c.logger.Error("Failed to marshal order for cache", zap.Error(err), zap.String("order_uid", order.OrderUID))
 		return err
 	}
-	if err := c.client.Set(ctx, order.OrderUID, data, 0).Err(); err != nil {
+	pipe := c.client.TxPipeline()
+	pipe.Set(ctx, order.OrderUID, data, c.orderTTL)
+	pipe.Del(ctx, append(trackKeys(&order), missingKey(order.OrderUID))...)
+	if _, err := pipe.Exec(ctx); err != nil {
 		c.logger.Error("Failed to save order to Redis", zap.Error(err), zap.String("order_uid", order.OrderUID))
 		return err
 	}
@@ -58,11 +120,34 @@ func (c *Cache) SaveOrder(ctx context.Context, order model.Order) error {
 	return nil
 }
 
+// GetOrder reads the order and its missing record in one round trip, so a
+// miss costs no more than a hit.
 func (c *Cache) GetOrder(ctx context.Context, orderUID string) (*model.Order, error) {
-	data, err := c.client.Get(ctx, orderUID).Bytes()
+	return c.getOrder(ctx, orderUID, c.sliding())
+}
+
+// PeekOrder is GetOrder without pushing back a sliding expiry.
+func (c *Cache) PeekOrder(ctx context.Context, orderUID string) (*model.Order, error) {
+	return c.getOrder(ctx, orderUID, false)
+}
+
+func (c *Cache) getOrder(ctx context.Context, orderUID string, slide bool) (*model.Order, error) {
+	pipe := c.client.Pipeline()
+	var cmd *redis.StringCmd
+	if slide {
+		cmd = pipe.GetEx(ctx, orderUID, c.orderTTL)
+	} else {
+		cmd = pipe.Get(ctx, orderUID)
+	}
+	missing := pipe.Exists(ctx, missingKey(orderUID))
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		c.logger.Error("Failed to get order from Redis", zap.Error(err), zap.String("order_uid", orderUID))
+		return nil, fmt.Errorf("redis get failed: %w", err)
+	}
+
+	data, err := cmd.Bytes()
 	if errors.Is(err, redis.Nil) {
-		c.logger.Info("Order not found in cache", zap.String("order_uid", orderUID))
-		return nil, nil
+		return nil, c.checkMissing(orderUID, missing)
 	}
 	if err != nil {
 		c.logger.Error("Failed to get order from Redis", zap.Error(err), zap.String("order_uid", orderUID))
@@ -78,109 +163,78 @@ func (c *Cache) GetOrder(ctx context.Context, orderUID string) (*model.Order, er
 	return &order, nil
 }
 
-func (c *Cache) RestoreFromDB(ctx context.Context, dbConn *sql.DB) error {
-	query := `
-        SELECT 
-            o.order_uid, o.track_number, o.entry, o.locale, o.internal_signature,
-            o.customer_id, o.delivery_service, o.shardkey, o.sm_id, o.date_created, o.oof_shard,
-            d.name, d.phone, d.zip, d.city, d.address, d.region, d.email,
-            p.transaction, p.request_id, p.currency, p.provider, p.amount,
-            p.payment_dt, p.bank, p.delivery_cost, p.goods_total, p.custom_fee
-        FROM orders o
-        JOIN delivery d ON o.order_uid = d.order_uid
-        JOIN payment p ON o.order_uid = p.order_uid
-        ORDER BY o.order_uid`
-
-	rows, err := dbConn.QueryContext(ctx, query)
-	if err != nil {
-		c.logger.Error("Failed to query orders for cache restore", zap.Error(err))
-		return err
+// FillOrders writes the orders that are not cached yet in one pipeline. It
+// uses SETNX so a bulk fill never overwrites an order cached by a newer save.
+func (c *Cache) FillOrders(ctx context.Context, orders []*model.Order) error {
+	if len(orders) == 0 {
+		return nil
 	}
-	defer func() {
-		if err := rows.Close(); err != nil {
-			c.logger.Error("Failed to close rows", zap.Error(err))
-		}
-	}()
-
-	ordersMap := make(map[string]*model.Order)
-	var orderUIDs []string
-
-	for rows.Next() {
-		var order model.Order
-		err := rows.Scan(
-			&order.OrderUID, &order.TrackNumber, &order.Entry, &order.Locale,
-			&order.InternalSignature, &order.CustomerID, &order.DeliveryService,
-			&order.Shardkey, &order.SmID, &order.DateCreated, &order.OofShard,
-			&order.Delivery.Name, &order.Delivery.Phone, &order.Delivery.Zip,
-			&order.Delivery.City, &order.Delivery.Address, &order.Delivery.Region,
-			&order.Delivery.Email,
-			&order.Payment.Transaction, &order.Payment.RequestID, &order.Payment.Currency,
-			&order.Payment.Provider, &order.Payment.Amount, &order.Payment.PaymentDt,
-			&order.Payment.Bank, &order.Payment.DeliveryCost, &order.Payment.GoodsTotal,
-			&order.Payment.CustomFee,
-		)
+
+	pipe := c.client.Pipeline()
+	for _, order := range orders {
+		data, err := json.Marshal(order)
 		if err != nil {
-			c.logger.Error("Failed to scan order", zap.Error(err))
-			continue
+			c.logger.Error("Failed to marshal order for cache", zap.Error(err), zap.String("order_uid", order.OrderUID))
+			return err
 		}
-		order.Items = []model.Item{}
-		ordersMap[order.OrderUID] = &order
-		orderUIDs = append(orderUIDs, order.OrderUID)
+		pipe.SetNX(ctx, order.OrderUID, data, c.orderTTL)
 	}
-
-	if len(orderUIDs) == 0 {
-		c.logger.Info("No orders to restore")
-		return nil
+	if _, err := pipe.Exec(ctx); err != nil {
+		c.logger.Error("Failed to fill orders in Redis", zap.Error(err), zap.Int("orders_count", len(orders)))
+		return fmt.Errorf("redis pipeline failed: %w", err)
 	}
+	return nil
+}
 
-	itemsQuery := `
-        SELECT order_uid, chrt_id, track_number, price, rid, name, sale, 
-               size, total_price, nm_id, brand, status
-        FROM items
-        WHERE order_uid = ANY($1)
-        ORDER BY order_uid, chrt_id`
+// Stats is the part of Redis INFO and DBSIZE worth exporting as metrics.
+type Stats struct {
+	UsedMemory  int64
+	MaxMemory   int64
+	Keys        int64
+	ExpiredKeys int64
+	EvictedKeys int64
+}
 
-	itemRows, err := dbConn.QueryContext(ctx, itemsQuery, orderUIDs)
+// Stats reads the Redis memory and key counters.
+func (c *Cache) Stats(ctx context.Context) (Stats, error) {
+	info, err := c.client.Info(ctx).Result()
 	if err != nil {
-		c.logger.Error("Failed to query items", zap.Error(err))
-		return err
+		return Stats{}, fmt.Errorf("redis info failed: %w", err)
 	}
-	defer func() {
-		if err := itemRows.Close(); err != nil {
-			c.logger.Error("Failed to close item rows", zap.Error(err))
-		}
-	}()
-
-	for itemRows.Next() {
-		var item model.Item
-		var orderUID string
-		if err := itemRows.Scan(
-			&orderUID, &item.ChrtID, &item.TrackNumber, &item.Price, &item.Rid,
-			&item.Name, &item.Sale, &item.Size, &item.TotalPrice, &item.NmID,
-			&item.Brand, &item.Status,
-		); err != nil {
-			c.logger.Error("Failed to scan item", zap.Error(err))
-			continue
-		}
-		if order, exists := ordersMap[orderUID]; exists {
-			order.Items = append(order.Items, item)
-		}
+	keys, err := c.client.DBSize(ctx).Result()
+	if err != nil {
+		return Stats{}, fmt.Errorf("redis dbsize failed: %w", err)
 	}
 
-	if err := itemRows.Err(); err != nil {
-		c.logger.Error("Failed to scan items", zap.Error(err))
-		return err
-	}
+	fields := parseInfo(info)
+	return Stats{
+		UsedMemory:  fields["used_memory"],
+		MaxMemory:   fields["maxmemory"],
+		Keys:        keys,
+		ExpiredKeys: fields["expired_keys"],
+		EvictedKeys: fields["evicted_keys"],
+	}, nil
+}
 
-	for _, order := range ordersMap {
-		if err := c.SaveOrder(ctx, *order); err != nil {
-			c.logger.Error("Failed to cache order during restore",
-				zap.Error(err), zap.String("order_uid", order.OrderUID))
+// parseInfo picks the integer fields out of an INFO reply, which is made of
+// "name:value" lines grouped under "# Section" headers.
+func parseInfo(info string) map[string]int64 {
+	fields := make(map[string]int64)
+	for _, line := range strings.Split(info, "\n") {
+		name, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok || strings.HasPrefix(name, "#") {
+			continue
+		}
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			fields[name] = n
 		}
 	}
+	return fields
+}
 
-	c.logger.Info("Cache restored from DB", zap.Int("orders_count", len(ordersMap)))
-	return nil
+// Ping checks that Redis answers.
+func (c *Cache) Ping(ctx context.Context) error {
+	return c.client.Ping(ctx).Err()
 }
 
 func (c *Cache) Close() error {
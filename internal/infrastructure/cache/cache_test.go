@@ -3,6 +3,7 @@ package cache
 import (
 	"context"
 	"testing"
+	"time"
 
 	"l0/internal/domain/model"
 
@@ -12,7 +13,7 @@ import (
 	"go.uber.org/zap"
 )
 
-func setupTestCache(t *testing.T) *Cache {
+func setupTestCache(t *testing.T, opts ...Option) *Cache {
 	t.Helper()
 	ctx := context.Background()
 
@@ -32,7 +33,7 @@ func setupTestCache(t *testing.T) *Cache {
 	require.NoError(t, err, "failed to get redis endpoint")
 
 	logger := zap.NewNop()
-	c := NewCache(endpoint, logger)
+	c := NewCache(endpoint, logger, opts...)
 
 	t.Cleanup(func() {
 		if err := c.Close(); err != nil {
@@ -115,6 +116,165 @@ func TestOrderCache_Overwrite(t *testing.T) {
 	assert.Equal(t, "V2", cachedOrder.TrackNumber)
 }
 
+func TestOrderCache_TTL(t *testing.T) {
+	const ttl = time.Hour
+	ctx := context.Background()
+
+	t.Run("fixed", func(t *testing.T) {
+		c := setupTestCache(t, WithOrderTTL(ttl))
+		order := createTestOrder("ttl-fixed")
+		require.NoError(t, c.SaveOrder(ctx, *order))
+		require.NoError(t, c.client.Expire(ctx, order.OrderUID, time.Minute).Err())
+
+		_, err := c.GetOrder(ctx, order.OrderUID)
+		require.NoError(t, err)
+
+		remaining, err := c.client.TTL(ctx, order.OrderUID).Result()
+		require.NoError(t, err)
+		assert.LessOrEqual(t, remaining, time.Minute, "a read must not extend a fixed TTL")
+	})
+
+	t.Run("sliding", func(t *testing.T) {
+		c := setupTestCache(t, WithOrderTTL(ttl), WithSlidingExpiration())
+		order := createTestOrder("ttl-sliding")
+		require.NoError(t, c.SaveOrder(ctx, *order))
+
+		remaining, err := c.client.TTL(ctx, order.OrderUID).Result()
+		require.NoError(t, err)
+		assert.InDelta(t, ttl.Seconds(), remaining.Seconds(), 5)
+
+		require.NoError(t, c.client.Expire(ctx, order.OrderUID, time.Minute).Err())
+		_, err = c.GetOrder(ctx, order.OrderUID)
+		require.NoError(t, err)
+
+		remaining, err = c.client.TTL(ctx, order.OrderUID).Result()
+		require.NoError(t, err)
+		assert.InDelta(t, ttl.Seconds(), remaining.Seconds(), 5, "a read must restart the TTL")
+	})
+
+	t.Run("peek", func(t *testing.T) {
+		c := setupTestCache(t, WithOrderTTL(ttl), WithSlidingExpiration())
+		order := createTestOrder("ttl-peek")
+		require.NoError(t, c.SaveOrder(ctx, *order))
+		require.NoError(t, c.client.Expire(ctx, order.OrderUID, time.Minute).Err())
+
+		peeked, err := c.PeekOrder(ctx, order.OrderUID)
+		require.NoError(t, err)
+		require.NotNil(t, peeked)
+
+		remaining, err := c.client.TTL(ctx, order.OrderUID).Result()
+		require.NoError(t, err)
+		assert.LessOrEqual(t, remaining, time.Minute, "a peek must not extend a sliding TTL")
+	})
+}
+
+func TestOrderCache_FillKeepsNewerOrders(t *testing.T) {
+	c := setupTestCache(t, WithOrderTTL(time.Hour))
+	ctx := context.Background()
+
+	fresh := createTestOrder("fill-fresh")
+	fresh.TrackNumber = "SAVED"
+	require.NoError(t, c.SaveOrder(ctx, *fresh))
+
+	stale := createTestOrder("fill-fresh")
+	stale.TrackNumber = "RESTORED"
+	missing := createTestOrder("fill-missing")
+	require.NoError(t, c.FillOrders(ctx, []*model.Order{stale, missing}))
+
+	cached, err := c.GetOrder(ctx, fresh.OrderUID)
+	require.NoError(t, err)
+	assert.Equal(t, "SAVED", cached.TrackNumber)
+
+	cached, err = c.GetOrder(ctx, missing.OrderUID)
+	require.NoError(t, err)
+	require.NotNil(t, cached)
+	remaining, err := c.client.TTL(ctx, missing.OrderUID).Result()
+	require.NoError(t, err)
+	assert.Positive(t, remaining)
+}
+
+func TestRestoreCheckpointStore_SaveLoadClear(t *testing.T) {
+	c := setupTestCache(t)
+	ctx := context.Background()
+	store := NewRestoreCheckpointStore(c)
+	since := time.Date(2021, 11, 20, 0, 0, 0, 0, time.UTC)
+
+	checkpoint, err := store.Load(ctx, since)
+	require.NoError(t, err)
+	assert.Nil(t, checkpoint)
+
+	saved := &model.RestoreCheckpoint{
+		Since:    since,
+		Cursor:   model.OrderCursor{DateCreated: time.Date(2021, 11, 26, 6, 0, 0, 0, time.UTC), OrderUID: "order-1"},
+		Restored: 500,
+	}
+	require.NoError(t, store.Save(ctx, saved))
+
+	checkpoint, err = store.Load(ctx, since)
+	require.NoError(t, err)
+	require.NotNil(t, checkpoint)
+	assert.Equal(t, saved.Cursor.OrderUID, checkpoint.Cursor.OrderUID)
+	assert.True(t, saved.Cursor.DateCreated.Equal(checkpoint.Cursor.DateCreated))
+	assert.Equal(t, 500, checkpoint.Restored)
+
+	// A restore of a different window starts over.
+	for _, other := range []time.Time{{}, since.AddDate(0, 0, -1)} {
+		checkpoint, err = store.Load(ctx, other)
+		require.NoError(t, err)
+		assert.Nil(t, checkpoint)
+	}
+
+	require.NoError(t, store.Clear(ctx, since))
+	checkpoint, err = store.Load(ctx, since)
+	require.NoError(t, err)
+	assert.Nil(t, checkpoint)
+}
+
+func TestOrderCache_MissingRecord(t *testing.T) {
+	c := setupTestCache(t, WithMissingTTL(time.Minute))
+	ctx := context.Background()
+	order := createTestOrder("missing-1")
+
+	require.NoError(t, c.MarkOrderMissing(ctx, order.OrderUID))
+	cached, err := c.GetOrder(ctx, order.OrderUID)
+	assert.ErrorIs(t, err, model.ErrOrderNotFound)
+	assert.Nil(t, cached)
+
+	require.NoError(t, c.SaveOrder(ctx, *order))
+	cached, err = c.GetOrder(ctx, order.OrderUID)
+	require.NoError(t, err)
+	require.NotNil(t, cached)
+
+	require.NoError(t, c.DeleteOrder(ctx, order.OrderUID))
+	cached, err = c.GetOrder(ctx, order.OrderUID)
+	require.NoError(t, err, "saving the order must clear the missing record")
+	assert.Nil(t, cached)
+}
+
+func TestCache_Stats(t *testing.T) {
+	c := setupTestCache(t)
+	ctx := context.Background()
+	require.NoError(t, c.SaveOrder(ctx, *createTestOrder("stats")))
+
+	stats, err := c.Stats(ctx)
+	require.NoError(t, err)
+	assert.Positive(t, stats.UsedMemory)
+	assert.Equal(t, int64(1), stats.Keys)
+}
+
+func TestParseInfo(t *testing.T) {
+	t.Parallel()
+
+	info := "# Memory\r\nused_memory:1048576\r\nused_memory_human:1.00M\r\nmaxmemory:0\r\n\r\n# Stats\r\nexpired_keys:12\r\nevicted_keys:3\r\n"
+	fields := parseInfo(info)
+
+	assert.Equal(t, int64(1048576), fields["used_memory"])
+	assert.Equal(t, int64(0), fields["maxmemory"])
+	assert.Equal(t, int64(12), fields["expired_keys"])
+	assert.Equal(t, int64(3), fields["evicted_keys"])
+	assert.NotContains(t, fields, "used_memory_human")
+}
+
 func TestOrderCache_Get_CorruptedData(t *testing.T) {
 	c := setupTestCache(t)
 	ctx := context.Background()
@@ -159,3 +319,76 @@ func TestOrderCache_Serialization_EdgeCases(t *testing.T) {
 
 	assert.Empty(t, retrieved.Items)
 }
+
+func TestOrderCache_TrackIndex_InvalidatedOnSave(t *testing.T) {
+	c := setupTestCache(t)
+	oc := NewOrderCache(c)
+	ctx := context.Background()
+
+	miss, err := oc.GetByTrack(ctx, "TRACK-1")
+	require.NoError(t, err)
+	assert.Nil(t, miss)
+
+	first := createTestOrder("order-1")
+	first.TrackNumber = "TRACK-1"
+	require.NoError(t, oc.SetByTrack(ctx, "TRACK-1", []*model.Order{first}))
+
+	hit, err := oc.GetByTrack(ctx, "TRACK-1")
+	require.NoError(t, err)
+	require.Len(t, hit, 1)
+	assert.Equal(t, "order-1", hit[0].OrderUID)
+
+	second := createTestOrder("order-2")
+	second.Items[0].TrackNumber = "TRACK-1"
+	require.NoError(t, oc.Set(ctx, second))
+
+	stale, err := oc.GetByTrack(ctx, "TRACK-1")
+	require.NoError(t, err)
+	assert.Nil(t, stale, "saving an order with the same track number must drop the index")
+}
+
+func TestOrderCache_TrackIndex_MissingOrderIsMiss(t *testing.T) {
+	c := setupTestCache(t)
+	oc := NewOrderCache(c)
+	ctx := context.Background()
+
+	order := createTestOrder("order-1")
+	require.NoError(t, oc.SetByTrack(ctx, "TRACK-X", []*model.Order{order}))
+	require.NoError(t, c.client.Del(ctx, "order-1").Err())
+
+	got, err := oc.GetByTrack(ctx, "TRACK-X")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func TestIdempotencyStore_ReserveCompleteReplay(t *testing.T) {
+	c := setupTestCache(t)
+	store := NewIdempotencyStore(c, time.Hour)
+	ctx := context.Background()
+
+	record, reserved, err := store.Reserve(ctx, "key-1", "hash-1")
+	require.NoError(t, err)
+	assert.True(t, reserved)
+	assert.Nil(t, record)
+
+	record, reserved, err = store.Reserve(ctx, "key-1", "hash-1")
+	require.NoError(t, err)
+	assert.False(t, reserved)
+	assert.False(t, record.Completed)
+
+	require.NoError(t, store.Complete(ctx, "key-1", &model.IdempotencyRecord{
+		RequestHash: "hash-1", StatusCode: 201, Body: []byte(`{"order_uid":"a"}`),
+	}))
+
+	record, reserved, err = store.Reserve(ctx, "key-1", "hash-1")
+	require.NoError(t, err)
+	assert.False(t, reserved)
+	assert.True(t, record.Completed)
+	assert.Equal(t, 201, record.StatusCode)
+	assert.JSONEq(t, `{"order_uid":"a"}`, string(record.Body))
+
+	require.NoError(t, store.Release(ctx, "key-1"))
+	_, reserved, err = store.Reserve(ctx, "key-1", "hash-2")
+	require.NoError(t, err)
+	assert.True(t, reserved)
+}
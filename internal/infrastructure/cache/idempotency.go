@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"l0/internal/domain/model"
+
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+)
+
+const (
+	idempotencyKeyPrefix = "idempotency:"
+	// idempotencyInFlightTTL frees a key whose request died before completing.
+	idempotencyInFlightTTL = time.Minute
+)
+
+type IdempotencyStore struct {
+	client *redis.Client
+	ttl    time.Duration
+	logger *zap.Logger
+}
+
+func NewIdempotencyStore(cache *Cache, ttl time.Duration) *IdempotencyStore {
+	return &IdempotencyStore{client: cache.client, ttl: ttl, logger: cache.logger}
+}
+
+func (s *IdempotencyStore) Reserve(ctx context.Context, key, requestHash string) (*model.IdempotencyRecord, bool, error) {
+	pending, err := json.Marshal(model.IdempotencyRecord{RequestHash: requestHash})
+	if err != nil {
+		return nil, false, fmt.Errorf("marshal idempotency record failed: %w", err)
+	}
+
+	// A second round covers the key expiring between SETNX and GET.
+	for range 2 {
+		reserved, err := s.client.SetNX(ctx, idempotencyKeyPrefix+key, pending, idempotencyInFlightTTL).Result()
+		if err != nil {
+			return nil, false, fmt.Errorf("redis setnx failed: %w", err)
+		}
+		if reserved {
+			return nil, true, nil
+		}
+
+		data, err := s.client.Get(ctx, idempotencyKeyPrefix+key).Bytes()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("redis get failed: %w", err)
+		}
+
+		var record model.IdempotencyRecord
+		if err := json.Unmarshal(data, &record); err != nil {
+			return nil, false, fmt.Errorf("unmarshal idempotency record failed: %w", err)
+		}
+		return &record, false, nil
+	}
+	return nil, false, fmt.Errorf("idempotency key %q is contended", key)
+}
+
+func (s *IdempotencyStore) Complete(ctx context.Context, key string, record *model.IdempotencyRecord) error {
+	record.Completed = true
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("marshal idempotency record failed: %w", err)
+	}
+	if err := s.client.Set(ctx, idempotencyKeyPrefix+key, data, s.ttl).Err(); err != nil {
+		s.logger.Error("Failed to store idempotency record", zap.Error(err), zap.String("idempotency_key", key))
+		return err
+	}
+	return nil
+}
+
+func (s *IdempotencyStore) Release(ctx context.Context, key string) error {
+	return s.client.Del(ctx, idempotencyKeyPrefix+key).Err()
+}
@@ -0,0 +1,105 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+)
+
+const (
+	invalidationChannel = "orders:invalidate"
+	// invalidationRetryDelay spaces out resubscribe attempts while Redis is
+	// unreachable.
+	invalidationRetryDelay = time.Second
+)
+
+type invalidation struct {
+	Origin   string `json:"origin"`
+	OrderUID string `json:"order_uid"`
+}
+
+// InvalidationBus tells the other instances over Redis pub/sub that an order
+// changed, so they drop it from their local caches.
+type InvalidationBus struct {
+	client *redis.Client
+	origin string
+	skip   func() bool
+	logger *zap.Logger
+}
+
+func NewInvalidationBus(cache *Cache) *InvalidationBus {
+	origin := make([]byte, 8)
+	_, _ = rand.Read(origin)
+	return &InvalidationBus{client: cache.client, origin: hex.EncodeToString(origin), logger: cache.logger}
+}
+
+// SkipWhile makes Publish a no-op while cond reports true, such as while a
+// circuit breaker holds Redis to be down. Nothing is lost: an outage also
+// drops the other instances' subscriptions, and they purge on reconnecting.
+func (b *InvalidationBus) SkipWhile(cond func() bool) {
+	b.skip = cond
+}
+
+func (b *InvalidationBus) Publish(ctx context.Context, orderUID string) error {
+	if b.skip != nil && b.skip() {
+		return nil
+	}
+	data, err := json.Marshal(invalidation{Origin: b.origin, OrderUID: orderUID})
+	if err != nil {
+		return fmt.Errorf("marshal invalidation failed: %w", err)
+	}
+	if err := b.client.Publish(ctx, invalidationChannel, data).Err(); err != nil {
+		return fmt.Errorf("redis publish failed: %w", err)
+	}
+	return nil
+}
+
+// Listen calls onInvalidate for every order changed by another instance until
+// ctx is done. Messages published while the subscription is down are lost,
+// so onReset is called whenever it is (re)established and the caller should
+// drop everything it might have missed.
+func (b *InvalidationBus) Listen(ctx context.Context, onInvalidate func(orderUID string), onReset func()) {
+	pubsub := b.client.Subscribe(ctx, invalidationChannel)
+	defer func() {
+		if err := pubsub.Close(); err != nil {
+			b.logger.Warn("Failed to close invalidation subscription", zap.Error(err))
+		}
+	}()
+
+	for {
+		msg, err := pubsub.Receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			b.logger.Warn("Invalidation subscription failed, resubscribing", zap.Error(err))
+			onReset()
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(invalidationRetryDelay):
+			}
+			continue
+		}
+
+		switch msg := msg.(type) {
+		case *redis.Subscription:
+			onReset()
+		case *redis.Message:
+			var inv invalidation
+			if err := json.Unmarshal([]byte(msg.Payload), &inv); err != nil {
+				b.logger.Warn("Malformed invalidation message", zap.Error(err))
+				continue
+			}
+			if inv.Origin != b.origin {
+				onInvalidate(inv.OrderUID)
+			}
+		}
+	}
+}
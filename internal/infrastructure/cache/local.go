@@ -0,0 +1,137 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"l0/internal/domain/model"
+)
+
+// LocalCache is a size- and TTL-bounded in-process LRU of orders. It hands
+// out copies, so callers may modify what they get without touching the
+// cached order.
+type LocalCache struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	entries map[string]*list.Element
+	order   *list.List // front is the most recently used
+	// generation changes on every removal, so a lookup that raced with an
+	// invalidation can tell its result may already be stale.
+	generation uint64
+	now        func() time.Time
+}
+
+type localEntry struct {
+	orderUID  string
+	order     *model.Order
+	expiresAt time.Time
+}
+
+func NewLocalCache(size int, ttl time.Duration) *LocalCache {
+	return &LocalCache{
+		size:    size,
+		ttl:     ttl,
+		entries: make(map[string]*list.Element, size),
+		order:   list.New(),
+		now:     time.Now,
+	}
+}
+
+func (c *LocalCache) Get(orderUID string) *model.Order {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[orderUID]
+	if !ok {
+		return nil
+	}
+	entry := elem.Value.(*localEntry)
+	if c.now().After(entry.expiresAt) {
+		c.removeElement(elem)
+		return nil
+	}
+	c.order.MoveToFront(elem)
+	return cloneOrder(entry.order)
+}
+
+// Generation returns a token to pass to AddIfUnchanged.
+func (c *LocalCache) Generation() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generation
+}
+
+func (c *LocalCache) Add(order *model.Order) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.add(order)
+}
+
+// AddIfUnchanged adds order unless something was removed since generation
+// was taken, in which case order may be the very version that was
+// invalidated.
+func (c *LocalCache) AddIfUnchanged(order *model.Order, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.generation != generation {
+		return
+	}
+	c.add(order)
+}
+
+func (c *LocalCache) add(order *model.Order) {
+	entry := &localEntry{orderUID: order.OrderUID, order: cloneOrder(order), expiresAt: c.now().Add(c.ttl)}
+	if elem, ok := c.entries[order.OrderUID]; ok {
+		elem.Value = entry
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[order.OrderUID] = c.order.PushFront(entry)
+	for c.order.Len() > c.size {
+		c.removeElement(c.order.Back())
+	}
+}
+
+func (c *LocalCache) Remove(orderUID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	if elem, ok := c.entries[orderUID]; ok {
+		c.removeElement(elem)
+	}
+}
+
+// Purge drops every entry. It is used when invalidations may have been
+// missed, such as after the pub/sub connection dropped.
+func (c *LocalCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	c.entries = make(map[string]*list.Element, c.size)
+	c.order.Init()
+}
+
+func (c *LocalCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+func (c *LocalCache) removeElement(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*localEntry).orderUID)
+}
+
+func cloneOrder(order *model.Order) *model.Order {
+	clone := *order
+	if order.Items != nil {
+		clone.Items = make([]model.Item, len(order.Items))
+		copy(clone.Items, order.Items)
+	}
+	return &clone
+}
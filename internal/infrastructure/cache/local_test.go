@@ -0,0 +1,206 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"l0/internal/domain/model"
+	"l0/internal/domain/repository/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestLocalCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	t.Parallel()
+
+	c := NewLocalCache(2, time.Minute)
+	c.Add(createTestOrder("order-1"))
+	c.Add(createTestOrder("order-2"))
+	require.NotNil(t, c.Get("order-1"))
+
+	c.Add(createTestOrder("order-3"))
+
+	assert.NotNil(t, c.Get("order-1"))
+	assert.Nil(t, c.Get("order-2"))
+	assert.NotNil(t, c.Get("order-3"))
+	assert.Equal(t, 2, c.Len())
+}
+
+func TestLocalCache_Expires(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewLocalCache(10, time.Minute)
+	c.now = func() time.Time { return now }
+	c.Add(createTestOrder("order-1"))
+
+	now = now.Add(59 * time.Second)
+	assert.NotNil(t, c.Get("order-1"))
+
+	now = now.Add(2 * time.Second)
+	assert.Nil(t, c.Get("order-1"))
+	assert.Zero(t, c.Len())
+}
+
+func TestLocalCache_ReturnsCopies(t *testing.T) {
+	t.Parallel()
+
+	c := NewLocalCache(10, time.Minute)
+	order := createTestOrder("order-1")
+	c.Add(order)
+	order.Items[0].Name = "changed by caller"
+
+	cached := c.Get("order-1")
+	require.NotNil(t, cached)
+	cached.Status = model.OrderStatusCancelled
+	cached.Items[0].Name = "changed by reader"
+
+	again := c.Get("order-1")
+	assert.Equal(t, "Item 1", again.Items[0].Name)
+	assert.NotEqual(t, model.OrderStatusCancelled, again.Status)
+}
+
+func TestLocalCache_AddIfUnchanged(t *testing.T) {
+	t.Parallel()
+
+	c := NewLocalCache(10, time.Minute)
+	generation := c.Generation()
+	c.Remove("order-1")
+
+	c.AddIfUnchanged(createTestOrder("order-1"), generation)
+	assert.Nil(t, c.Get("order-1"), "an order read before an invalidation must not be cached")
+
+	c.AddIfUnchanged(createTestOrder("order-1"), c.Generation())
+	assert.NotNil(t, c.Get("order-1"))
+}
+
+func TestTieredOrderCache(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	order := createTestOrder("order-1")
+
+	tests := []struct {
+		name  string
+		setup func(*mocks.MockOrderCache)
+		run   func(*testing.T, *TieredOrderCache)
+	}{
+		{
+			name: "miss_reads_through_once",
+			setup: func(next *mocks.MockOrderCache) {
+				next.EXPECT().Get(ctx, order.OrderUID).Return(order, nil).Times(1)
+			},
+			run: func(t *testing.T, c *TieredOrderCache) {
+				for range 3 {
+					got, err := c.Get(ctx, order.OrderUID)
+					require.NoError(t, err)
+					assert.Equal(t, order.OrderUID, got.OrderUID)
+				}
+			},
+		},
+		{
+			name: "set_replaces_local_entry",
+			setup: func(next *mocks.MockOrderCache) {
+				next.EXPECT().Get(ctx, order.OrderUID).Return(order, nil)
+				next.EXPECT().Set(ctx, gomock.Any()).Return(nil)
+			},
+			run: func(t *testing.T, c *TieredOrderCache) {
+				_, err := c.Get(ctx, order.OrderUID)
+				require.NoError(t, err)
+
+				updated := cloneOrder(order)
+				updated.Status = model.OrderStatusShipped
+				require.NoError(t, c.Set(ctx, updated))
+
+				got, err := c.Get(ctx, order.OrderUID)
+				require.NoError(t, err)
+				assert.Equal(t, model.OrderStatusShipped, got.Status)
+			},
+		},
+		{
+			name: "delete_drops_local_entry",
+			setup: func(next *mocks.MockOrderCache) {
+				next.EXPECT().Get(ctx, order.OrderUID).Return(order, nil)
+				next.EXPECT().Delete(ctx, order.OrderUID).Return(nil)
+				next.EXPECT().Get(ctx, order.OrderUID).Return(nil, nil)
+			},
+			run: func(t *testing.T, c *TieredOrderCache) {
+				_, err := c.Get(ctx, order.OrderUID)
+				require.NoError(t, err)
+				require.NoError(t, c.Delete(ctx, order.OrderUID))
+
+				got, err := c.Get(ctx, order.OrderUID)
+				require.NoError(t, err)
+				assert.Nil(t, got)
+			},
+		},
+		{
+			name: "misses_are_not_cached",
+			setup: func(next *mocks.MockOrderCache) {
+				next.EXPECT().Get(ctx, order.OrderUID).Return(nil, nil).Times(2)
+			},
+			run: func(t *testing.T, c *TieredOrderCache) {
+				for range 2 {
+					got, err := c.Get(ctx, order.OrderUID)
+					require.NoError(t, err)
+					assert.Nil(t, got)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			next := mocks.NewMockOrderCache(ctrl)
+			tt.setup(next)
+
+			c := NewTieredOrderCache(next, NewLocalCache(10, time.Minute), nil, TieredHooks{}, zap.NewNop())
+			tt.run(t, c)
+		})
+	}
+}
+
+func TestTieredOrderCache_InvalidatesOtherInstances(t *testing.T) {
+	redisCache := setupTestCache(t)
+	ctx := context.Background()
+
+	newInstance := func() (*TieredOrderCache, *LocalCache) {
+		local := NewLocalCache(10, time.Minute)
+		return NewTieredOrderCache(NewOrderCache(redisCache), local, NewInvalidationBus(redisCache), TieredHooks{}, zap.NewNop()), local
+	}
+	a, _ := newInstance()
+	b, bLocal := newInstance()
+	t.Cleanup(func() {
+		a.stop()
+		b.stop()
+	})
+
+	require.Eventually(t, func() bool {
+		subs, err := redisCache.client.PubSubNumSub(ctx, invalidationChannel).Result()
+		return err == nil && subs[invalidationChannel] == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	order := createTestOrder("invalidate-1")
+	require.NoError(t, a.Set(ctx, order))
+	// The purge on subscribing may land after the first read.
+	require.Eventually(t, func() bool {
+		_, err := b.Get(ctx, order.OrderUID)
+		return err == nil && bLocal.Len() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	updated := cloneOrder(order)
+	updated.Status = model.OrderStatusShipped
+	require.NoError(t, a.Set(ctx, updated))
+
+	require.Eventually(t, func() bool { return bLocal.Len() == 0 }, 5*time.Second, 10*time.Millisecond)
+	got, err := b.Get(ctx, order.OrderUID)
+	require.NoError(t, err)
+	assert.Equal(t, model.OrderStatusShipped, got.Status)
+}
@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"l0/internal/domain/model"
+
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+)
+
+// A missing record says a lookup found no order in the DB. It lives next to
+// the order key rather than in it, so a concurrent SETNX fill or a sliding
+// GETEX can never mistake it for an order, and SaveOrder deletes it.
+const (
+	missingKeyPrefix  = "missing:"
+	defaultMissingTTL = 30 * time.Second
+)
+
+func missingKey(orderUID string) string {
+	return missingKeyPrefix + orderUID
+}
+
+// MarkOrderMissing records that orderUID is not in the DB for missingTTL.
+func (c *Cache) MarkOrderMissing(ctx context.Context, orderUID string) error {
+	if err := c.client.Set(ctx, missingKey(orderUID), 1, c.missingTTL).Err(); err != nil {
+		return fmt.Errorf("redis set failed: %w", err)
+	}
+	return nil
+}
+
+// checkMissing is called on a cache miss with the EXISTS of the order's
+// missing record, read in the same pipeline as the order, and turns the miss
+// into model.ErrOrderNotFound when the order is recorded as missing.
+func (c *Cache) checkMissing(orderUID string, exists *redis.IntCmd) error {
+	missing, err := exists.Result()
+	if err != nil {
+		c.logger.Error("Failed to check missing order record", zap.Error(err), zap.String("order_uid", orderUID))
+		return fmt.Errorf("redis exists failed: %w", err)
+	}
+	if missing > 0 {
+		return model.ErrOrderNotFound
+	}
+	c.logger.Info("Order not found in cache", zap.String("order_uid", orderUID))
+	return nil
+}
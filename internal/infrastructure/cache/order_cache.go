@@ -17,12 +17,32 @@ func (c *OrderCache) Get(ctx context.Context, orderUID string) (*model.Order, er
 	return c.cache.GetOrder(ctx, orderUID)
 }
 
+func (c *OrderCache) Peek(ctx context.Context, orderUID string) (*model.Order, error) {
+	return c.cache.PeekOrder(ctx, orderUID)
+}
+
 func (c *OrderCache) Set(ctx context.Context, order *model.Order) error {
 	return c.cache.SaveOrder(ctx, *order)
 }
 
-func (r *OrderCache) Delete(ctx context.Context, orderUID string) error {
-	return r.cache.client.Del(ctx, orderUID).Err()
+func (c *OrderCache) MarkMissing(ctx context.Context, orderUID string) error {
+	return c.cache.MarkOrderMissing(ctx, orderUID)
+}
+
+func (c *OrderCache) GetByTrack(ctx context.Context, trackNumber string) ([]*model.Order, error) {
+	return c.cache.GetOrdersByTrack(ctx, trackNumber)
+}
+
+func (c *OrderCache) SetByTrack(ctx context.Context, trackNumber string, orders []*model.Order) error {
+	return c.cache.SaveTrackIndex(ctx, trackNumber, orders)
+}
+
+func (c *OrderCache) Fill(ctx context.Context, orders []*model.Order) error {
+	return c.cache.FillOrders(ctx, orders)
+}
+
+func (c *OrderCache) Delete(ctx context.Context, orderUID string) error {
+	return c.cache.DeleteOrder(ctx, orderUID)
 }
 
 func (c *OrderCache) Close() error {
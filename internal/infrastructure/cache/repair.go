@@ -0,0 +1,239 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"l0/internal/domain/model"
+	"l0/internal/domain/repository"
+
+	"go.uber.org/zap"
+)
+
+const (
+	DefaultRepairWorkers     = 2
+	DefaultRepairMaxAttempts = 10
+	DefaultRepairBackoff     = time.Second
+	DefaultRepairMaxBackoff  = time.Minute
+	DefaultRepairQueueSize   = 10_000
+)
+
+// RepairOutcome is what became of an order on its way through the queue.
+type RepairOutcome string
+
+const (
+	RepairRepaired RepairOutcome = "repaired"
+	RepairRetried  RepairOutcome = "retried"
+	// RepairFailed is an order given up on after the last attempt.
+	RepairFailed RepairOutcome = "failed"
+	// RepairDropped is an order turned away by a full queue.
+	RepairDropped RepairOutcome = "dropped"
+)
+
+type RepairOptions struct {
+	Workers int
+	// MaxAttempts bounds how often an order is tried before it is left to
+	// the reconciler.
+	MaxAttempts int
+	// Backoff is the delay before the first retry. It doubles with every
+	// attempt, up to MaxBackoff.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// Size bounds how many orders can wait for repair at once.
+	Size int
+	// OnOutcome, if set, is called for every repair attempt and every order
+	// turned away.
+	OnOutcome func(RepairOutcome)
+	// OnQueueSize, if set, is called with the number of waiting orders
+	// whenever it changes.
+	OnQueueSize func(int)
+}
+
+type repairState struct {
+	attempts int
+	running  bool
+	// again is set when the order is enqueued while a repair is running,
+	// since that repair may have read the DB before the change.
+	again bool
+}
+
+// RepairQueue rewrites orders whose cache write failed. Each repair re-reads
+// the order from the DB, so a retry never puts back a version older than the
+// one the DB holds, and an order is repaired by one worker at a time, so two
+// repairs cannot race each other. Orders enqueued again while waiting are
+// repaired once.
+type RepairQueue struct {
+	orderRepo  repository.OrderRepository
+	orderCache repository.OrderCache
+	opts       RepairOptions
+	logger     *zap.Logger
+
+	// jobs holds every waiting order at most once and is as large as the
+	// queue, so sending to it never blocks.
+	jobs    chan string
+	mu      sync.Mutex
+	pending map[string]*repairState
+}
+
+func NewRepairQueue(orderRepo repository.OrderRepository, orderCache repository.OrderCache, opts RepairOptions, logger *zap.Logger) *RepairQueue {
+	if opts.Workers <= 0 {
+		opts.Workers = DefaultRepairWorkers
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = DefaultRepairMaxAttempts
+	}
+	if opts.Backoff <= 0 {
+		opts.Backoff = DefaultRepairBackoff
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = DefaultRepairMaxBackoff
+	}
+	opts.MaxBackoff = max(opts.MaxBackoff, opts.Backoff)
+	if opts.Size <= 0 {
+		opts.Size = DefaultRepairQueueSize
+	}
+	return &RepairQueue{
+		orderRepo:  orderRepo,
+		orderCache: orderCache,
+		opts:       opts,
+		logger:     logger,
+		jobs:       make(chan string, opts.Size),
+		pending:    make(map[string]*repairState),
+	}
+}
+
+func (q *RepairQueue) Enqueue(orderUID string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if state, ok := q.pending[orderUID]; ok {
+		if state.running {
+			state.again = true
+		}
+		return
+	}
+	if len(q.pending) >= q.opts.Size {
+		q.outcome(RepairDropped)
+		q.logger.Warn("Cache repair queue is full, leaving the order to the reconciler", zap.String("order_uid", orderUID))
+		return
+	}
+	q.pending[orderUID] = &repairState{}
+	q.queueSizeChanged()
+	q.jobs <- orderUID
+}
+
+// Run repairs orders until ctx is cancelled. Repairs still waiting then are
+// dropped; the reconciler finds those orders on its next pass.
+func (q *RepairQueue) Run(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	workers := &sync.WaitGroup{}
+	for range q.opts.Workers {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case orderUID := <-q.jobs:
+					q.repair(ctx, orderUID)
+				}
+			}
+		}()
+	}
+	workers.Wait()
+
+	q.mu.Lock()
+	left := len(q.pending)
+	q.mu.Unlock()
+	if left > 0 {
+		q.logger.Warn("Cache repairs left unfinished at shutdown", zap.Int("orders", left))
+	}
+}
+
+func (q *RepairQueue) repair(ctx context.Context, orderUID string) {
+	q.mu.Lock()
+	state := q.pending[orderUID]
+	state.running = true
+	state.again = false
+	q.mu.Unlock()
+
+	err := q.refresh(ctx, orderUID)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	defer q.queueSizeChanged()
+
+	state.running = false
+	switch {
+	case err == nil && state.again:
+		state.attempts = 0
+		q.jobs <- orderUID
+	case err == nil:
+		delete(q.pending, orderUID)
+		q.outcome(RepairRepaired)
+		q.logger.Info("Cached order repaired", zap.String("order_uid", orderUID))
+	case ctx.Err() != nil:
+		// Shutting down; Run reports what is left.
+	default:
+		state.attempts++
+		if state.attempts >= q.opts.MaxAttempts {
+			delete(q.pending, orderUID)
+			q.outcome(RepairFailed)
+			q.logger.Error("Giving up on cache repair, leaving the order to the reconciler",
+				zap.Error(err), zap.String("order_uid", orderUID), zap.Int("attempts", state.attempts))
+			return
+		}
+		delay := q.backoff(state.attempts)
+		q.outcome(RepairRetried)
+		q.logger.Warn("Cache repair failed, retrying",
+			zap.Error(err), zap.String("order_uid", orderUID), zap.Int("attempt", state.attempts), zap.Duration("backoff", delay))
+		time.AfterFunc(delay, func() { q.jobs <- orderUID })
+	}
+}
+
+func (q *RepairQueue) outcome(outcome RepairOutcome) {
+	if q.opts.OnOutcome != nil {
+		q.opts.OnOutcome(outcome)
+	}
+}
+
+// queueSizeChanged must be called with mu held.
+func (q *RepairQueue) queueSizeChanged() {
+	if q.opts.OnQueueSize != nil {
+		q.opts.OnQueueSize(len(q.pending))
+	}
+}
+
+// refresh writes the DB's version of the order to the cache.
+func (q *RepairQueue) refresh(ctx context.Context, orderUID string) error {
+	order, err := q.orderRepo.GetByUID(ctx, orderUID)
+	if errors.Is(err, model.ErrOrderNotFound) {
+		if err := q.orderCache.Delete(ctx, orderUID); err != nil {
+			return fmt.Errorf("failed to delete order from cache: %w", err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get order from DB: %w", err)
+	}
+	if err := q.orderCache.Set(ctx, order); err != nil {
+		return fmt.Errorf("failed to save order to cache: %w", err)
+	}
+	return nil
+}
+
+func (q *RepairQueue) backoff(attempt int) time.Duration {
+	delay := q.opts.Backoff
+	for range attempt - 1 {
+		delay *= 2
+		if delay >= q.opts.MaxBackoff {
+			return q.opts.MaxBackoff
+		}
+	}
+	return delay
+}
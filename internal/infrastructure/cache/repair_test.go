@@ -0,0 +1,122 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"l0/internal/domain/model"
+	"l0/internal/domain/repository/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+// runRepairQueue runs q until the test ends.
+func runRepairQueue(t *testing.T, q *RepairQueue) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go q.Run(ctx, wg)
+	t.Cleanup(func() {
+		cancel()
+		wg.Wait()
+	})
+}
+
+func repairsDone(q *RepairQueue) func() bool {
+	return func() bool {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		return len(q.pending) == 0
+	}
+}
+
+func TestRepairQueue_RetriesFromFreshDBRead(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockOrderRepository(ctrl)
+	orderCache := mocks.NewMockOrderCache(ctrl)
+	q := NewRepairQueue(repo, orderCache, RepairOptions{Workers: 1, Backoff: 5 * time.Millisecond}, zap.NewNop())
+
+	created := &model.Order{OrderUID: "order-1", Status: model.OrderStatusCreated}
+	paid := &model.Order{OrderUID: "order-1", Status: model.OrderStatusPaid}
+	gomock.InOrder(
+		repo.EXPECT().GetByUID(gomock.Any(), "order-1").Return(created, nil),
+		orderCache.EXPECT().Set(gomock.Any(), created).Return(errors.New("redis down")),
+		repo.EXPECT().GetByUID(gomock.Any(), "order-1").Return(paid, nil),
+		orderCache.EXPECT().Set(gomock.Any(), paid).Return(nil),
+	)
+
+	// Enqueued twice before running: repaired once.
+	q.Enqueue("order-1")
+	q.Enqueue("order-1")
+	runRepairQueue(t, q)
+
+	require.Eventually(t, repairsDone(q), time.Second, 5*time.Millisecond)
+}
+
+func TestRepairQueue_GivesUpAfterMaxAttempts(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockOrderRepository(ctrl)
+	orderCache := mocks.NewMockOrderCache(ctrl)
+	q := NewRepairQueue(repo, orderCache, RepairOptions{Workers: 1, MaxAttempts: 3, Backoff: time.Millisecond}, zap.NewNop())
+
+	repo.EXPECT().GetByUID(gomock.Any(), "order-1").Return(nil, errors.New("db down")).Times(3)
+
+	q.Enqueue("order-1")
+	runRepairQueue(t, q)
+
+	require.Eventually(t, repairsDone(q), time.Second, 5*time.Millisecond)
+}
+
+func TestRepairQueue_DeletesOrdersGoneFromDB(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockOrderRepository(ctrl)
+	orderCache := mocks.NewMockOrderCache(ctrl)
+	q := NewRepairQueue(repo, orderCache, RepairOptions{}, zap.NewNop())
+
+	repo.EXPECT().GetByUID(gomock.Any(), "order-1").Return(nil, model.ErrOrderNotFound)
+	orderCache.EXPECT().Delete(gomock.Any(), "order-1").Return(nil)
+
+	q.Enqueue("order-1")
+	runRepairQueue(t, q)
+
+	require.Eventually(t, repairsDone(q), time.Second, 5*time.Millisecond)
+}
+
+func TestRepairQueue_DropsWhenFull(t *testing.T) {
+	t.Parallel()
+
+	q := NewRepairQueue(nil, nil, RepairOptions{Size: 2}, zap.NewNop())
+
+	q.Enqueue("order-1")
+	q.Enqueue("order-2")
+	q.Enqueue("order-3")
+
+	assert.Len(t, q.pending, 2)
+	assert.NotContains(t, q.pending, "order-3")
+	assert.Len(t, q.jobs, 2)
+}
+
+func TestRepairQueue_Backoff(t *testing.T) {
+	t.Parallel()
+
+	q := NewRepairQueue(nil, nil, RepairOptions{Backoff: time.Second, MaxBackoff: 5 * time.Second}, zap.NewNop())
+
+	assert.Equal(t, time.Second, q.backoff(1))
+	assert.Equal(t, 2*time.Second, q.backoff(2))
+	assert.Equal(t, 4*time.Second, q.backoff(3))
+	assert.Equal(t, 5*time.Second, q.backoff(4))
+	assert.Equal(t, 5*time.Second, q.backoff(40))
+}
@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"l0/internal/domain/model"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	restoreCheckpointPrefix = "cache_restore:checkpoint:"
+	// restoreCheckpointTTL drops a checkpoint left by a restore that never
+	// resumed; by then newer orders have been cached by saves anyway.
+	restoreCheckpointTTL = time.Hour
+)
+
+// RestoreCheckpointStore keeps the cache restore checkpoint next to the data
+// it describes, so flushing Redis also restarts the restore from scratch.
+// Checkpoints are keyed by the restore's Since: instances restoring the same
+// window share one, as they fill the same cache, and a different window
+// starts from scratch.
+type RestoreCheckpointStore struct {
+	client *redis.Client
+}
+
+func NewRestoreCheckpointStore(cache *Cache) *RestoreCheckpointStore {
+	return &RestoreCheckpointStore{client: cache.client}
+}
+
+func restoreCheckpointKey(since time.Time) string {
+	if since.IsZero() {
+		return restoreCheckpointPrefix + "all"
+	}
+	return restoreCheckpointPrefix + since.UTC().Format(time.RFC3339Nano)
+}
+
+func (s *RestoreCheckpointStore) Load(ctx context.Context, since time.Time) (*model.RestoreCheckpoint, error) {
+	data, err := s.client.Get(ctx, restoreCheckpointKey(since)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("redis get failed: %w", err)
+	}
+
+	var checkpoint model.RestoreCheckpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, fmt.Errorf("unmarshal restore checkpoint failed: %w", err)
+	}
+	return &checkpoint, nil
+}
+
+func (s *RestoreCheckpointStore) Save(ctx context.Context, checkpoint *model.RestoreCheckpoint) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return fmt.Errorf("marshal restore checkpoint failed: %w", err)
+	}
+	if err := s.client.Set(ctx, restoreCheckpointKey(checkpoint.Since), data, restoreCheckpointTTL).Err(); err != nil {
+		return fmt.Errorf("redis set failed: %w", err)
+	}
+	return nil
+}
+
+func (s *RestoreCheckpointStore) Clear(ctx context.Context, since time.Time) error {
+	return s.client.Del(ctx, restoreCheckpointKey(since)).Err()
+}
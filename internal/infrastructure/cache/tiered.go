@@ -0,0 +1,140 @@
+package cache
+
+import (
+	"context"
+
+	"l0/internal/domain/model"
+	"l0/internal/domain/repository"
+
+	"go.uber.org/zap"
+)
+
+// TieredOrderCache serves orders from a LocalCache and falls back to the
+// wrapped cache, normally Redis. Orders written or deleted through it are
+// announced on the InvalidationBus, and announcements from other instances
+// evict the order locally; the local TTL bounds how stale an entry can get
+// if one is lost anyway. Track lookups go straight to the wrapped cache.
+type TieredOrderCache struct {
+	next   repository.OrderCache
+	local  *LocalCache
+	bus    *InvalidationBus
+	hooks  TieredHooks
+	logger *zap.Logger
+
+	stop context.CancelFunc
+	done chan struct{}
+}
+
+// TieredHooks report what the local tier does. Nil hooks are skipped.
+type TieredHooks struct {
+	// OnLookup is called for every Get with whether the local tier had the
+	// order.
+	OnLookup func(hit bool)
+	// OnInvalidation is called for every invalidation from another
+	// instance: of one order, or of all of them (reset) after the
+	// subscription was re-established and messages may have been missed.
+	OnInvalidation func(reset bool)
+}
+
+// NewTieredOrderCache starts listening for invalidations from other
+// instances. A nil bus suits a single instance, where every change goes
+// through this cache anyway.
+func NewTieredOrderCache(next repository.OrderCache, local *LocalCache, bus *InvalidationBus, hooks TieredHooks, logger *zap.Logger) *TieredOrderCache {
+	ctx, stop := context.WithCancel(context.Background())
+	c := &TieredOrderCache{next: next, local: local, bus: bus, hooks: hooks, logger: logger, stop: stop, done: make(chan struct{})}
+
+	go func() {
+		defer close(c.done)
+		if bus == nil {
+			return
+		}
+		bus.Listen(ctx, func(orderUID string) {
+			c.invalidated(false)
+			local.Remove(orderUID)
+		}, func() {
+			c.invalidated(true)
+			local.Purge()
+		})
+	}()
+	return c
+}
+
+func (c *TieredOrderCache) Get(ctx context.Context, orderUID string) (*model.Order, error) {
+	order := c.local.Get(orderUID)
+	if c.hooks.OnLookup != nil {
+		c.hooks.OnLookup(order != nil)
+	}
+	if order != nil {
+		return order, nil
+	}
+
+	generation := c.local.Generation()
+	order, err := c.next.Get(ctx, orderUID)
+	if err != nil || order == nil {
+		return order, err
+	}
+	c.local.AddIfUnchanged(order, generation)
+	return order, nil
+}
+
+// Set removes the local entry before writing, so a concurrent Get that read
+// the previous version from the wrapped cache cannot put it back afterwards.
+func (c *TieredOrderCache) Set(ctx context.Context, order *model.Order) error {
+	c.local.Remove(order.OrderUID)
+	if err := c.next.Set(ctx, order); err != nil {
+		return err
+	}
+	c.local.Add(order)
+	c.announce(ctx, order.OrderUID)
+	return nil
+}
+
+func (c *TieredOrderCache) MarkMissing(ctx context.Context, orderUID string) error {
+	return c.next.MarkMissing(ctx, orderUID)
+}
+
+func (c *TieredOrderCache) GetByTrack(ctx context.Context, trackNumber string) ([]*model.Order, error) {
+	return c.next.GetByTrack(ctx, trackNumber)
+}
+
+func (c *TieredOrderCache) SetByTrack(ctx context.Context, trackNumber string, orders []*model.Order) error {
+	return c.next.SetByTrack(ctx, trackNumber, orders)
+}
+
+// Fill only writes orders missing from the wrapped cache, so nothing cached
+// anywhere changes and there is nothing to invalidate.
+func (c *TieredOrderCache) Fill(ctx context.Context, orders []*model.Order) error {
+	return c.next.Fill(ctx, orders)
+}
+
+func (c *TieredOrderCache) Delete(ctx context.Context, orderUID string) error {
+	c.local.Remove(orderUID)
+	if err := c.next.Delete(ctx, orderUID); err != nil {
+		return err
+	}
+	c.announce(ctx, orderUID)
+	return nil
+}
+
+func (c *TieredOrderCache) Close() error {
+	c.stop()
+	<-c.done
+	return c.next.Close()
+}
+
+func (c *TieredOrderCache) invalidated(reset bool) {
+	if c.hooks.OnInvalidation != nil {
+		c.hooks.OnInvalidation(reset)
+	}
+}
+
+// announce is best effort: the write itself has succeeded, and instances that
+// miss the message drop the order when its local TTL runs out.
+func (c *TieredOrderCache) announce(ctx context.Context, orderUID string) {
+	if c.bus == nil {
+		return
+	}
+	if err := c.bus.Publish(ctx, orderUID); err != nil {
+		c.logger.Warn("Failed to publish order invalidation", zap.Error(err), zap.String("order_uid", orderUID))
+	}
+}
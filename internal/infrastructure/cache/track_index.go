@@ -0,0 +1,152 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"l0/internal/domain/model"
+
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+)
+
+// The track index maps a track number to the order_uids found for it in the
+// DB. Orders themselves stay under their order_uid key; the index only holds
+// UIDs, so there is a single copy of every order. Saving or deleting an order
+// drops the index entries for its track numbers, and the TTL bounds how long
+// an index written by a lookup that raced with such a save can stay stale.
+const (
+	trackKeyPrefix = "track:"
+	trackIndexTTL  = 10 * time.Minute
+)
+
+func trackKey(trackNumber string) string {
+	return trackKeyPrefix + trackNumber
+}
+
+// trackKeys returns the index keys of every track number the order can be
+// found by: its own and those of its items.
+func trackKeys(order *model.Order) []string {
+	seen := make(map[string]struct{}, len(order.Items)+1)
+	keys := make([]string, 0, len(order.Items)+1)
+	add := func(trackNumber string) {
+		if trackNumber == "" {
+			return
+		}
+		if _, ok := seen[trackNumber]; ok {
+			return
+		}
+		seen[trackNumber] = struct{}{}
+		keys = append(keys, trackKey(trackNumber))
+	}
+
+	add(order.TrackNumber)
+	for _, item := range order.Items {
+		add(item.TrackNumber)
+	}
+	return keys
+}
+
+// GetOrdersByTrack resolves the track index and loads the referenced orders.
+// It returns nil, nil on a miss, including when any indexed order has been
+// evicted, so the caller goes back to the DB rather than serving a partial
+// result.
+func (c *Cache) GetOrdersByTrack(ctx context.Context, trackNumber string) ([]*model.Order, error) {
+	data, err := c.client.Get(ctx, trackKey(trackNumber)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("redis get track index failed: %w", err)
+	}
+
+	var uids []string
+	if err := json.Unmarshal(data, &uids); err != nil {
+		return nil, fmt.Errorf("unmarshal track index failed: %w", err)
+	}
+	if len(uids) == 0 {
+		return []*model.Order{}, nil
+	}
+
+	values, err := c.client.MGet(ctx, uids...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis mget failed: %w", err)
+	}
+
+	orders := make([]*model.Order, 0, len(values))
+	for i, v := range values {
+		raw, ok := v.(string)
+		if !ok {
+			c.logger.Debug("Indexed order missing from cache",
+				zap.String("track_number", trackNumber), zap.String("order_uid", uids[i]))
+			return nil, nil
+		}
+		var order model.Order
+		if err := json.Unmarshal([]byte(raw), &order); err != nil {
+			return nil, fmt.Errorf("unmarshal failed: %w", err)
+		}
+		orders = append(orders, &order)
+	}
+	if c.sliding() {
+		c.touch(ctx, uids)
+	}
+	return orders, nil
+}
+
+// touch restarts the TTL of orders read through the track index. A failure
+// only shortens their stay in the cache, so it is logged, not returned.
+func (c *Cache) touch(ctx context.Context, orderUIDs []string) {
+	pipe := c.client.Pipeline()
+	for _, uid := range orderUIDs {
+		pipe.Expire(ctx, uid, c.orderTTL)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		c.logger.Warn("Failed to extend TTL of cached orders", zap.Error(err), zap.Int("count", len(orderUIDs)))
+	}
+}
+
+// SaveTrackIndex caches the orders and the index pointing at them in one
+// MULTI, so a reader never sees the index without its orders. Orders are
+// written with SETNX: an entry already in the cache was written by Set after
+// a save or status change and is at least as fresh as this DB read.
+func (c *Cache) SaveTrackIndex(ctx context.Context, trackNumber string, orders []*model.Order) error {
+	uids := make([]string, 0, len(orders))
+	pipe := c.client.TxPipeline()
+	for _, order := range orders {
+		data, err := json.Marshal(order)
+		if err != nil {
+			return fmt.Errorf("marshal order %s failed: %w", order.OrderUID, err)
+		}
+		pipe.SetNX(ctx, order.OrderUID, data, c.orderTTL)
+		uids = append(uids, order.OrderUID)
+	}
+
+	index, err := json.Marshal(uids)
+	if err != nil {
+		return fmt.Errorf("marshal track index failed: %w", err)
+	}
+	pipe.Set(ctx, trackKey(trackNumber), index, trackIndexTTL)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		c.logger.Error("Failed to save track index to Redis", zap.Error(err), zap.String("track_number", trackNumber))
+		return err
+	}
+	return nil
+}
+
+// DeleteOrder removes the order and the track index entries that may list it.
+func (c *Cache) DeleteOrder(ctx context.Context, orderUID string) error {
+	keys := []string{orderUID}
+	order, err := c.GetOrder(ctx, orderUID)
+	if err != nil && !errors.Is(err, model.ErrOrderNotFound) {
+		c.logger.Warn("Failed to read order before delete, track index left to expire",
+			zap.Error(err), zap.String("order_uid", orderUID))
+	}
+	if order != nil {
+		keys = append(keys, trackKeys(order)...)
+	}
+	return c.client.Del(ctx, keys...).Err()
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/caarlos0/env/v11"
@@ -16,29 +17,173 @@ type DatabaseConfig struct {
 }
 
 type KafkaConfig struct {
-	Broker  string `env:"KAFKA_BROKER" envDefault:"localhost:9092"`
-	Topic   string `env:"KAFKA_TOPIC" envDefault:"orders"`
-	GroupID string `env:"KAFKA_GROUP_ID" envDefault:"orders_group"`
+	Brokers     []string `env:"KAFKA_BROKERS" envDefault:"localhost:9092" envSeparator:","`
+	ClientID    string   `env:"KAFKA_CLIENT_ID" envDefault:"l0"`
+	Topic       string   `env:"KAFKA_TOPIC" envDefault:"orders"`
+	StatusTopic string   `env:"KAFKA_STATUS_TOPIC" envDefault:"order_status"`
+	GroupID     string   `env:"KAFKA_GROUP_ID" envDefault:"orders_group"`
+	DLQTopic    string   `env:"KAFKA_DLQ_TOPIC" envDefault:"orders_dlq"`
+	EventsTopic string   `env:"KAFKA_EVENTS_TOPIC" envDefault:"order_events"`
+	Workers     int      `env:"KAFKA_WORKERS" envDefault:"4"`
+
+	BatchSize    int           `env:"KAFKA_BATCH_SIZE" envDefault:"1"`
+	BatchTimeout time.Duration `env:"KAFKA_BATCH_TIMEOUT" envDefault:"100ms"`
+
+	RetryAttempts int           `env:"KAFKA_RETRY_ATTEMPTS" envDefault:"3"`
+	RetryBackoff  time.Duration `env:"KAFKA_RETRY_BACKOFF" envDefault:"1s"`
+
+	Reader   KafkaReaderConfig
+	Security KafkaSecurityConfig
+}
+
+// KafkaReaderConfig tunes the consumer's fetches. StartOffset is where a
+// group without committed offsets starts reading: first or last.
+type KafkaReaderConfig struct {
+	MinBytes       int           `env:"KAFKA_READER_MIN_BYTES" envDefault:"10000"`
+	MaxBytes       int           `env:"KAFKA_READER_MAX_BYTES" envDefault:"10000000"`
+	MaxWait        time.Duration `env:"KAFKA_READER_MAX_WAIT" envDefault:"1s"`
+	StartOffset    string        `env:"KAFKA_READER_START_OFFSET" envDefault:"first"`
+	SessionTimeout time.Duration `env:"KAFKA_READER_SESSION_TIMEOUT" envDefault:"30s"`
 }
 
+// KafkaSecurityConfig authenticates to the brokers. SASLMechanism is none,
+// plain, scram-sha-256 or scram-sha-512. With TLS enabled the brokers are
+// verified against CAFile, or the system roots if it is empty; CertFile and
+// KeyFile set a client certificate.
+type KafkaSecurityConfig struct {
+	SASLMechanism string `env:"KAFKA_SASL_MECHANISM" envDefault:"none"`
+	SASLUsername  string `env:"KAFKA_SASL_USERNAME"`
+	SASLPassword  string `env:"KAFKA_SASL_PASSWORD"`
+
+	TLSEnabled            bool   `env:"KAFKA_TLS_ENABLED" envDefault:"false"`
+	TLSCAFile             string `env:"KAFKA_TLS_CA_FILE"`
+	TLSCertFile           string `env:"KAFKA_TLS_CERT_FILE"`
+	TLSKeyFile            string `env:"KAFKA_TLS_KEY_FILE"`
+	TLSInsecureSkipVerify bool   `env:"KAFKA_TLS_INSECURE_SKIP_VERIFY" envDefault:"false"`
+}
+
+// RedisConfig bounds how long orders stay cached. OrderTTL of 0 keeps them
+// until Redis evicts them; RestoreDays of 0 restores every order at startup.
 type RedisConfig struct {
-	Addr string `env:"REDIS_ADDR" envDefault:"localhost:6379"`
+	Addr        string        `env:"REDIS_ADDR" envDefault:"localhost:6379"`
+	OrderTTL    time.Duration `env:"REDIS_ORDER_TTL" envDefault:"24h"`
+	SlidingTTL  bool          `env:"REDIS_SLIDING_TTL" envDefault:"true"`
+	RestoreDays int           `env:"REDIS_RESTORE_DAYS" envDefault:"7"`
+	// MissingTTL is how long a lookup of an unknown order_uid is answered
+	// from Redis before the DB is asked again.
+	MissingTTL time.Duration `env:"REDIS_MISSING_TTL" envDefault:"30s"`
+
+	// Calls are kept short so an outage trips the breaker quickly instead
+	// of stalling requests.
+	DialTimeout time.Duration `env:"REDIS_DIAL_TIMEOUT" envDefault:"1s"`
+	IOTimeout   time.Duration `env:"REDIS_IO_TIMEOUT" envDefault:"500ms"`
+	MaxRetries  int           `env:"REDIS_MAX_RETRIES" envDefault:"1"`
+
+	// BreakerFailures consecutive failed calls open the circuit breaker,
+	// which then pings Redis every BreakerProbeInterval until it answers.
+	BreakerFailures      int           `env:"REDIS_BREAKER_FAILURES" envDefault:"5"`
+	BreakerProbeInterval time.Duration `env:"REDIS_BREAKER_PROBE_INTERVAL" envDefault:"5s"`
+	// RestoreChunkSize is how many orders the restore reads and writes per
+	// round trip.
+	RestoreChunkSize int `env:"REDIS_RESTORE_CHUNK_SIZE" envDefault:"500"`
+	// RestoreWorkers bounds how many chunks the background restore writes
+	// to Redis at once.
+	RestoreWorkers int `env:"REDIS_RESTORE_WORKERS" envDefault:"4"`
+}
+
+// LocalCacheConfig bounds the in-process order cache in front of Redis. Size
+// of 0 disables it.
+type LocalCacheConfig struct {
+	Size int           `env:"LOCAL_CACHE_SIZE" envDefault:"10000"`
+	TTL  time.Duration `env:"LOCAL_CACHE_TTL" envDefault:"1m"`
+}
+
+// CacheRepairConfig tunes the background rewrite of orders whose cache write
+// failed, and the reconciler that periodically compares orders changed within
+// ReconcileWindow with the cache. ReconcileInterval of 0 disables it.
+type CacheRepairConfig struct {
+	Workers     int           `env:"CACHE_REPAIR_WORKERS" envDefault:"2"`
+	MaxAttempts int           `env:"CACHE_REPAIR_ATTEMPTS" envDefault:"10"`
+	Backoff     time.Duration `env:"CACHE_REPAIR_BACKOFF" envDefault:"1s"`
+	MaxBackoff  time.Duration `env:"CACHE_REPAIR_MAX_BACKOFF" envDefault:"1m"`
+	QueueSize   int           `env:"CACHE_REPAIR_QUEUE_SIZE" envDefault:"10000"`
+
+	ReconcileInterval time.Duration `env:"CACHE_RECONCILE_INTERVAL" envDefault:"5m"`
+	ReconcileWindow   time.Duration `env:"CACHE_RECONCILE_WINDOW" envDefault:"1h"`
+}
+
+// SchemaConfig points at the order schemas: every <subject>.v<N>.avsc file of
+// Dir is a version of an Avro schema.
+type SchemaConfig struct {
+	Dir string `env:"SCHEMA_DIR" envDefault:"schemas"`
 }
 
 type HTTPConfig struct {
 	Port            string        `env:"HTTP_PORT" envDefault:"8080"`
 	ShutdownTimeout time.Duration `env:"SHUTDOWN_TIMEOUT" envDefault:"10s"`
+	IdempotencyTTL  time.Duration `env:"HTTP_IDEMPOTENCY_TTL" envDefault:"24h"`
+	HealthTimeout   time.Duration `env:"HTTP_HEALTH_TIMEOUT" envDefault:"2s"`
+}
+
+// ValidationConfig sets the mode of each business rule: reject, warn or off.
+type ValidationConfig struct {
+	GoodsTotal      string `env:"VALIDATION_GOODS_TOTAL" envDefault:"warn"`
+	PaymentAmount   string `env:"VALIDATION_PAYMENT_AMOUNT" envDefault:"warn"`
+	ItemTotal       string `env:"VALIDATION_ITEM_TOTAL" envDefault:"warn"`
+	Transaction     string `env:"VALIDATION_TRANSACTION" envDefault:"warn"`
+	ItemTrackNumber string `env:"VALIDATION_ITEM_TRACK_NUMBER" envDefault:"warn"`
+}
+
+type OutboxConfig struct {
+	BatchSize       int           `env:"OUTBOX_BATCH_SIZE" envDefault:"100"`
+	PollInterval    time.Duration `env:"OUTBOX_POLL_INTERVAL" envDefault:"1s"`
+	Retention       time.Duration `env:"OUTBOX_RETENTION" envDefault:"168h"`
+	CleanupInterval time.Duration `env:"OUTBOX_CLEANUP_INTERVAL" envDefault:"1h"`
+}
+
+// TracingConfig selects the span exporter: none, stdout or otlp. The OTLP
+// exporter sends over HTTP to OTLPEndpoint (host:port).
+type TracingConfig struct {
+	Exporter     string  `env:"TRACING_EXPORTER" envDefault:"none"`
+	OTLPEndpoint string  `env:"TRACING_OTLP_ENDPOINT" envDefault:"localhost:4318"`
+	OTLPInsecure bool    `env:"TRACING_OTLP_INSECURE" envDefault:"true"`
+	SampleRatio  float64 `env:"TRACING_SAMPLE_RATIO" envDefault:"1"`
 }
 
 type ProducerConfig struct {
-	Kafka KafkaConfig
+	Kafka   KafkaConfig
+	Schemas SchemaConfig
+	Tracing TracingConfig
 }
 
 type ConsumerConfig struct {
-	Kafka    KafkaConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
-	HTTP     HTTPConfig
+	Kafka       KafkaConfig
+	Schemas     SchemaConfig
+	Database    DatabaseConfig
+	Redis       RedisConfig
+	LocalCache  LocalCacheConfig
+	CacheRepair CacheRepairConfig
+	HTTP        HTTPConfig
+	Outbox      OutboxConfig
+	Validation  ValidationConfig
+	Tracing     TracingConfig
+}
+
+// legacyBrokerEnv is the single-broker setting KAFKA_BROKERS replaced. It is
+// still read on its own, so an existing deployment keeps its broker instead
+// of silently falling back to localhost.
+const legacyBrokerEnv = "KAFKA_BROKER"
+
+func applyLegacyBroker(cfg *KafkaConfig) error {
+	broker, ok := os.LookupEnv(legacyBrokerEnv)
+	if !ok {
+		return nil
+	}
+	if _, ok := os.LookupEnv("KAFKA_BROKERS"); ok {
+		return fmt.Errorf("%s is replaced by KAFKA_BROKERS, set only KAFKA_BROKERS", legacyBrokerEnv)
+	}
+	cfg.Brokers = []string{broker}
+	return nil
 }
 
 func LoadProducerConfig() (*ProducerConfig, error) {
@@ -46,6 +191,9 @@ func LoadProducerConfig() (*ProducerConfig, error) {
 	if err := env.Parse(cfg); err != nil {
 		return nil, err
 	}
+	if err := applyLegacyBroker(&cfg.Kafka); err != nil {
+		return nil, err
+	}
 	return cfg, nil
 }
 
@@ -54,10 +202,19 @@ func LoadConsumerConfig() (*ConsumerConfig, error) {
 	if err := env.Parse(cfg); err != nil {
 		return nil, err
 	}
+	if err := applyLegacyBroker(&cfg.Kafka); err != nil {
+		return nil, err
+	}
 
 	if cfg.Database.User == "" || cfg.Database.Password == "" {
 		return nil, fmt.Errorf("database credentials required for consumer")
 	}
+	if cfg.Redis.BreakerFailures <= 0 {
+		return nil, fmt.Errorf("REDIS_BREAKER_FAILURES must be positive, got %d", cfg.Redis.BreakerFailures)
+	}
+	if cfg.Redis.BreakerProbeInterval <= 0 {
+		return nil, fmt.Errorf("REDIS_BREAKER_PROBE_INTERVAL must be positive, got %s", cfg.Redis.BreakerProbeInterval)
+	}
 
 	return cfg, nil
 }
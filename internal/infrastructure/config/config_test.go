@@ -0,0 +1,54 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The tests set environment variables, so they cannot run in parallel.
+
+func TestLoadConsumerConfig_Breaker(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr string
+	}{
+		{name: "defaults"},
+		{
+			name:    "zero failures",
+			env:     map[string]string{"REDIS_BREAKER_FAILURES": "0"},
+			wantErr: "REDIS_BREAKER_FAILURES must be positive",
+		},
+		{
+			name:    "negative failures",
+			env:     map[string]string{"REDIS_BREAKER_FAILURES": "-1"},
+			wantErr: "REDIS_BREAKER_FAILURES must be positive",
+		},
+		{
+			name:    "zero probe interval",
+			env:     map[string]string{"REDIS_BREAKER_PROBE_INTERVAL": "0s"},
+			wantErr: "REDIS_BREAKER_PROBE_INTERVAL must be positive",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("POSTGRES_USER", "user")
+			t.Setenv("POSTGRES_PASSWORD", "password")
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := LoadConsumerConfig()
+
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, 5, cfg.Redis.BreakerFailures)
+		})
+	}
+}
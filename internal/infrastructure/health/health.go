@@ -0,0 +1,136 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+	// StatusWarn marks a failed optional check, which is reported but does
+	// not fail the report.
+	StatusWarn = "warn"
+)
+
+// Check reports whether a dependency is usable. A nil error means healthy.
+type Check func(ctx context.Context) error
+
+type CheckResult struct {
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+func (r Report) OK() bool {
+	return r.Status == StatusOK
+}
+
+// Checker runs named checks concurrently, each bounded by the same timeout.
+type Checker struct {
+	timeout  time.Duration
+	names    []string
+	checks   []Check
+	optional []bool
+}
+
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout}
+}
+
+// Register adds a check. It is not safe to call once Run is in use.
+func (c *Checker) Register(name string, check Check) {
+	c.register(name, check, false)
+}
+
+// RegisterOptional adds a check that is reported, as StatusWarn when it
+// fails, without affecting the overall status. It suits conditions the
+// service can serve without, such as a cache that is still warming up.
+func (c *Checker) RegisterOptional(name string, check Check) {
+	c.register(name, check, true)
+}
+
+func (c *Checker) register(name string, check Check, optional bool) {
+	c.names = append(c.names, name)
+	c.checks = append(c.checks, check)
+	c.optional = append(c.optional, optional)
+}
+
+// Run executes all checks and reports ok only if every required one passed.
+func (c *Checker) Run(ctx context.Context) Report {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	results := make([]CheckResult, len(c.checks))
+	wg := &sync.WaitGroup{}
+	for i, check := range c.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = run(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]CheckResult, len(results))}
+	for i, result := range results {
+		switch {
+		case result.Status == StatusOK:
+		case c.optional[i]:
+			result.Status = StatusWarn
+		default:
+			report.Status = StatusFail
+		}
+		report.Checks[c.names[i]] = result
+	}
+	return report
+}
+
+func run(ctx context.Context, check Check) CheckResult {
+	start := time.Now()
+	err := check(ctx)
+	result := CheckResult{
+		Status:    StatusOK,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// Flag is a condition that becomes true once and stays true, such as the end
+// of the startup cache restore.
+type Flag struct {
+	done    atomic.Bool
+	pending error
+}
+
+// NewFlag returns an unset flag whose check fails with pending until Set.
+func NewFlag(pending string) *Flag {
+	return &Flag{pending: errors.New(pending)}
+}
+
+func (f *Flag) Set() {
+	f.done.Store(true)
+}
+
+func (f *Flag) IsSet() bool {
+	return f.done.Load()
+}
+
+func (f *Flag) Check(context.Context) error {
+	if f.IsSet() {
+		return nil
+	}
+	return f.pending
+}
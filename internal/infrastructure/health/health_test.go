@@ -0,0 +1,101 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecker_Run(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		checks     map[string]Check
+		wantStatus string
+		wantFailed []string
+	}{
+		{
+			name: "all_pass",
+			checks: map[string]Check{
+				"postgres": func(context.Context) error { return nil },
+				"redis":    func(context.Context) error { return nil },
+			},
+			wantStatus: StatusOK,
+		},
+		{
+			name: "one_fails",
+			checks: map[string]Check{
+				"postgres": func(context.Context) error { return nil },
+				"redis":    func(context.Context) error { return errors.New("connection refused") },
+			},
+			wantStatus: StatusFail,
+			wantFailed: []string{"redis"},
+		},
+		{
+			name: "timeout",
+			checks: map[string]Check{
+				"kafka": func(ctx context.Context) error {
+					<-ctx.Done()
+					return ctx.Err()
+				},
+			},
+			wantStatus: StatusFail,
+			wantFailed: []string{"kafka"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			checker := NewChecker(50 * time.Millisecond)
+			for name, check := range tt.checks {
+				checker.Register(name, check)
+			}
+
+			report := checker.Run(context.Background())
+			assert.Equal(t, tt.wantStatus, report.Status)
+			require.Len(t, report.Checks, len(tt.checks))
+
+			var failed []string
+			for name, result := range report.Checks {
+				if result.Status == StatusFail {
+					failed = append(failed, name)
+					assert.NotEmpty(t, result.Error)
+				}
+				assert.GreaterOrEqual(t, result.LatencyMS, 0.0)
+			}
+			assert.ElementsMatch(t, tt.wantFailed, failed)
+		})
+	}
+}
+
+func TestChecker_RunOptional(t *testing.T) {
+	t.Parallel()
+
+	checker := NewChecker(time.Second)
+	checker.Register("postgres", func(context.Context) error { return nil })
+	checker.RegisterOptional("cache_restore", NewFlag("cache restore in progress").Check)
+
+	report := checker.Run(context.Background())
+
+	assert.True(t, report.OK())
+	assert.Equal(t, StatusWarn, report.Checks["cache_restore"].Status)
+	assert.Equal(t, "cache restore in progress", report.Checks["cache_restore"].Error)
+	assert.Equal(t, StatusOK, report.Checks["postgres"].Status)
+}
+
+func TestFlag(t *testing.T) {
+	t.Parallel()
+
+	flag := NewFlag("cache restore in progress")
+	assert.EqualError(t, flag.Check(context.Background()), "cache restore in progress")
+
+	flag.Set()
+	assert.NoError(t, flag.Check(context.Background()))
+}
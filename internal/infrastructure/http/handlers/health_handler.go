@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"net/http"
+
+	"l0/internal/infrastructure/health"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type HealthHandler struct {
+	readiness *health.Checker
+	logger    *zap.Logger
+}
+
+func NewHealthHandler(readiness *health.Checker, logger *zap.Logger) *HealthHandler {
+	return &HealthHandler{readiness: readiness, logger: logger}
+}
+
+// Live answers as long as the process can serve HTTP at all; it checks no
+// dependencies, so an orchestrator won't restart the instance over an outage
+// elsewhere.
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": health.StatusOK})
+}
+
+// Ready reports every dependency with its latency and answers 503 until all
+// of them pass.
+func (h *HealthHandler) Ready(c *gin.Context) {
+	report := h.readiness.Run(c.Request.Context())
+	if !report.OK() {
+		h.logger.Warn("Readiness check failed", zap.Any("checks", report.Checks))
+		c.JSON(http.StatusServiceUnavailable, report)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,63 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"l0/internal/infrastructure/health"
+	"l0/internal/infrastructure/http/handlers"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestHealthHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		path       string
+		redisErr   error
+		wantCode   int
+		wantStatus string
+	}{
+		{name: "live ignores dependencies", path: "/healthz", redisErr: errors.New("down"), wantCode: http.StatusOK, wantStatus: health.StatusOK},
+		{name: "ready", path: "/readyz", wantCode: http.StatusOK, wantStatus: health.StatusOK},
+		{name: "not ready", path: "/readyz", redisErr: errors.New("connection refused"), wantCode: http.StatusServiceUnavailable, wantStatus: health.StatusFail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			gin.SetMode(gin.TestMode)
+
+			checker := health.NewChecker(time.Second)
+			checker.Register("postgres", func(context.Context) error { return nil })
+			checker.Register("redis", func(context.Context) error { return tt.redisErr })
+
+			h := handlers.NewHealthHandler(checker, zap.NewNop())
+			r := gin.New()
+			r.GET("/healthz", h.Live)
+			r.GET("/readyz", h.Ready)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			assert.Equal(t, tt.wantCode, w.Code)
+
+			var report health.Report
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+			assert.Equal(t, tt.wantStatus, report.Status)
+			if tt.path == "/readyz" {
+				require.Contains(t, report.Checks, "redis")
+				assert.Equal(t, tt.redisErr != nil, report.Checks["redis"].Error != "")
+			}
+		})
+	}
+}
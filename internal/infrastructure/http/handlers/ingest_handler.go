@@ -0,0 +1,305 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+
+	"l0/internal/domain/model"
+	"l0/internal/domain/repository"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	IdempotencyKeyHeader       = "Idempotency-Key"
+	IdempotentReplayedHeader   = "Idempotent-Replayed"
+	maxOrderBodyBytes          = 1 << 20
+	maxBulkBodyBytes           = 16 << 20
+	maxBulkOrders              = 1000
+	ndjsonContentType          = "application/x-ndjson"
+	jsonLinesContentType       = "application/jsonl"
+	jsonContentTypeWithCharset = "application/json; charset=utf-8"
+	// idempotencyRetryAfter is the Retry-After, in seconds, of requests
+	// refused while the idempotency store is unavailable. It matches the
+	// default probe interval of the Redis circuit breaker.
+	idempotencyRetryAfter = "5"
+)
+
+// IngestHandler accepts orders over HTTP for partners that can't publish to
+// Kafka. Orders go through the same SaveOrderUseCase as consumed messages.
+type IngestHandler struct {
+	saveOrderUC repository.OrderSaver
+	idempotency repository.IdempotencyStore
+	logger      *zap.Logger
+}
+
+func NewIngestHandler(saveOrderUC repository.OrderSaver, idempotency repository.IdempotencyStore, logger *zap.Logger) *IngestHandler {
+	return &IngestHandler{saveOrderUC: saveOrderUC, idempotency: idempotency, logger: logger}
+}
+
+type errorResponse struct {
+	Error  string                 `json:"error"`
+	Fields []model.FieldViolation `json:"fields,omitempty"`
+}
+
+type createdResponse struct {
+	OrderUID string `json:"order_uid"`
+}
+
+type bulkResult struct {
+	Index    int                    `json:"index"`
+	OrderUID string                 `json:"order_uid,omitempty"`
+	Status   int                    `json:"status"`
+	Error    string                 `json:"error,omitempty"`
+	Fields   []model.FieldViolation `json:"fields,omitempty"`
+}
+
+type bulkResponse struct {
+	Created int          `json:"created"`
+	Results []bulkResult `json:"results"`
+}
+
+// readBody reads the request body up to limit bytes. If it cannot, it writes
+// the error response and returns false: 413 for a body over the limit, 400
+// for any other read error, such as a client that hung up mid-request.
+func readBody(c *gin.Context, limit int64) ([]byte, bool) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, limit))
+	if err == nil {
+		return body, true
+	}
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		c.JSON(http.StatusRequestEntityTooLarge, errorResponse{Error: "request body too large"})
+	} else {
+		c.JSON(http.StatusBadRequest, errorResponse{Error: "failed to read request body: " + err.Error()})
+	}
+	return nil, false
+}
+
+// Create serves POST /orders.
+func (h *IngestHandler) Create(c *gin.Context) {
+	body, ok := readBody(c, maxOrderBodyBytes)
+	if !ok {
+		return
+	}
+
+	h.withIdempotency(c, body, func() outcome {
+		var order model.Order
+		if err := json.Unmarshal(body, &order); err != nil {
+			return outcome{status: http.StatusBadRequest, body: errorResponse{Error: "invalid JSON: " + err.Error()}}
+		}
+
+		err := h.saveOrderUC.Execute(c.Request.Context(), &order)
+		if err == nil {
+			return outcome{status: http.StatusCreated, body: createdResponse{OrderUID: order.OrderUID}}
+		}
+		status, resp := h.saveError(err, order.OrderUID)
+		return outcome{status: status, body: resp, retryable: status >= http.StatusInternalServerError}
+	})
+}
+
+// CreateBulk serves POST /orders/bulk. The body is either a JSON array of
+// orders or, with an NDJSON content type, one order per line. Each order gets
+// its own status in the response, so one bad order doesn't fail the rest.
+func (h *IngestHandler) CreateBulk(c *gin.Context) {
+	body, ok := readBody(c, maxBulkBodyBytes)
+	if !ok {
+		return
+	}
+
+	h.withIdempotency(c, body, func() outcome {
+		orders, results, err := decodeBulk(c.ContentType(), body)
+		if err != nil {
+			return outcome{status: http.StatusBadRequest, body: errorResponse{Error: err.Error()}}
+		}
+		if len(results) > maxBulkOrders {
+			return outcome{
+				status: http.StatusRequestEntityTooLarge,
+				body:   errorResponse{Error: fmt.Sprintf("at most %d orders per request", maxBulkOrders)},
+			}
+		}
+
+		batch := make([]*model.Order, 0, len(orders))
+		batchIdx := make([]int, 0, len(orders))
+		for i, order := range orders {
+			if order != nil {
+				batch = append(batch, order)
+				batchIdx = append(batchIdx, i)
+			}
+		}
+
+		resp := bulkResponse{Results: results}
+		retryable := false
+		if len(batch) > 0 {
+			errs := h.saveOrderUC.ExecuteBatch(c.Request.Context(), batch)
+			for j, err := range errs {
+				result := &resp.Results[batchIdx[j]]
+				if err == nil {
+					result.Status = http.StatusCreated
+					resp.Created++
+					continue
+				}
+				status, errResp := h.saveError(err, result.OrderUID)
+				result.Status, result.Error, result.Fields = status, errResp.Error, errResp.Fields
+				retryable = retryable || status >= http.StatusInternalServerError
+			}
+		}
+		return outcome{status: http.StatusOK, body: resp, retryable: retryable}
+	})
+}
+
+// decodeBulk returns the decoded orders with a result slot for each; orders
+// that could not be decoded are nil and their result is already final.
+func decodeBulk(contentType string, body []byte) ([]*model.Order, []bulkResult, error) {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType == ndjsonContentType || mediaType == jsonLinesContentType {
+		var (
+			orders  []*model.Order
+			results []bulkResult
+		)
+		scanner := bufio.NewScanner(bytes.NewReader(body))
+		scanner.Buffer(make([]byte, 0, 64<<10), maxOrderBodyBytes)
+		for scanner.Scan() {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+			result := bulkResult{Index: len(results)}
+			var order model.Order
+			if err := json.Unmarshal(line, &order); err != nil {
+				result.Status, result.Error = http.StatusBadRequest, "invalid JSON: "+err.Error()
+				orders = append(orders, nil)
+			} else {
+				result.OrderUID = order.OrderUID
+				orders = append(orders, &order)
+			}
+			results = append(results, result)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, nil, fmt.Errorf("invalid NDJSON: %w", err)
+		}
+		return orders, results, nil
+	}
+
+	var orders []*model.Order
+	if err := json.Unmarshal(body, &orders); err != nil {
+		return nil, nil, fmt.Errorf("invalid JSON: expected an array of orders: %w", err)
+	}
+	results := make([]bulkResult, len(orders))
+	for i, order := range orders {
+		results[i] = bulkResult{Index: i}
+		if order == nil {
+			results[i].Status, results[i].Error = http.StatusBadRequest, "order must be an object"
+			continue
+		}
+		results[i].OrderUID = order.OrderUID
+	}
+	return orders, results, nil
+}
+
+func (h *IngestHandler) saveError(err error, orderUID string) (int, errorResponse) {
+	switch {
+	case errors.Is(err, model.ErrOrderAlreadyExists):
+		return http.StatusConflict, errorResponse{Error: "order already exists"}
+	case errors.Is(err, model.ErrInvalidOrderData):
+		return http.StatusUnprocessableEntity, errorResponse{Error: "invalid order data", Fields: fieldErrors(err)}
+	default:
+		h.logger.Error("Failed to save order", zap.Error(err), zap.String("order_uid", orderUID))
+		return http.StatusInternalServerError, errorResponse{Error: "failed to save order"}
+	}
+}
+
+func fieldErrors(err error) []model.FieldViolation {
+	var validationErr *model.ValidationError
+	if !errors.As(err, &validationErr) {
+		return nil
+	}
+	return validationErr.Violations
+}
+
+// outcome is a response produced under an Idempotency-Key. Retryable
+// outcomes, i.e. those with a server-side failure, are not stored.
+type outcome struct {
+	status    int
+	body      any
+	retryable bool
+}
+
+// withIdempotency runs handle at most once per Idempotency-Key. A retry with
+// the same key and body gets the stored response back; the same key with a
+// different body is refused. Retryable outcomes release the key so the client
+// can try again. If the store is unavailable the request is refused with 503:
+// processing it without the key could repeat a bulk insert the client is
+// retrying, which is what the key is there to prevent.
+func (h *IngestHandler) withIdempotency(c *gin.Context, body []byte, handle func() outcome) {
+	key := c.GetHeader(IdempotencyKeyHeader)
+	if key == "" {
+		h.respond(c, handle)
+		return
+	}
+
+	ctx := c.Request.Context()
+	hash := requestHash(c.Request.Method, c.FullPath(), body)
+
+	record, reserved, err := h.idempotency.Reserve(ctx, key, hash)
+	if err != nil {
+		h.logger.Warn("Idempotency store unavailable, refusing request",
+			zap.Error(err), zap.String("idempotency_key", key))
+		c.Header("Retry-After", idempotencyRetryAfter)
+		c.JSON(http.StatusServiceUnavailable, errorResponse{Error: "idempotency store is unavailable, retry later"})
+		return
+	}
+
+	if !reserved {
+		switch {
+		case record.RequestHash != hash:
+			c.JSON(http.StatusUnprocessableEntity, errorResponse{Error: "Idempotency-Key was already used for a different request"})
+		case !record.Completed:
+			c.JSON(http.StatusConflict, errorResponse{Error: "a request with this Idempotency-Key is still in progress"})
+		default:
+			c.Header(IdempotentReplayedHeader, "true")
+			c.Data(record.StatusCode, jsonContentTypeWithCharset, record.Body)
+		}
+		return
+	}
+
+	result, data := h.respond(c, handle)
+	if result.retryable {
+		if err := h.idempotency.Release(ctx, key); err != nil {
+			h.logger.Warn("Failed to release idempotency key", zap.Error(err), zap.String("idempotency_key", key))
+		}
+		return
+	}
+	record = &model.IdempotencyRecord{RequestHash: hash, StatusCode: result.status, Body: data}
+	if err := h.idempotency.Complete(ctx, key, record); err != nil {
+		h.logger.Warn("Failed to store idempotent response", zap.Error(err), zap.String("idempotency_key", key))
+	}
+}
+
+func (h *IngestHandler) respond(c *gin.Context, handle func() outcome) (outcome, []byte) {
+	result := handle()
+	data, err := json.Marshal(result.body)
+	if err != nil {
+		h.logger.Error("Failed to encode response", zap.Error(err))
+		result = outcome{status: http.StatusInternalServerError, retryable: true}
+		data = []byte(`{"error":"failed to encode response"}`)
+	}
+	c.Data(result.status, jsonContentTypeWithCharset, data)
+	return result, data
+}
+
+func requestHash(method, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,367 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"l0/internal/application/validation"
+	"l0/internal/domain/model"
+	"l0/internal/domain/repository/mocks"
+	"l0/internal/infrastructure/http/handlers"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func setupIngestTest(t *testing.T) (*mocks.MockOrderSaver, *mocks.MockIdempotencyStore, *gin.Engine) {
+	gin.SetMode(gin.TestMode)
+
+	ctrl := gomock.NewController(t)
+	saver := mocks.NewMockOrderSaver(ctrl)
+	store := mocks.NewMockIdempotencyStore(ctrl)
+
+	h := handlers.NewIngestHandler(saver, store, zap.NewNop())
+
+	r := gin.New()
+	r.POST("/orders", h.Create)
+	r.POST("/orders/bulk", h.CreateBulk)
+
+	return saver, store, r
+}
+
+func post(t *testing.T, router *gin.Engine, path, contentType, body string, headers map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, path, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", contentType)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func invalidOrderErr(t *testing.T) error {
+	t.Helper()
+
+	err := validation.NewValidator().ValidateOrder(model.Order{})
+	require.ErrorIs(t, err, model.ErrInvalidOrderData)
+	return err
+}
+
+func TestIngestHandler_Create(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		body       string
+		saveErr    error
+		expectSave bool
+		wantCode   int
+		wantBody   string
+	}{
+		{
+			name:       "created",
+			body:       `{"order_uid":"order-1"}`,
+			expectSave: true,
+			wantCode:   http.StatusCreated,
+			wantBody:   `{"order_uid":"order-1"}`,
+		},
+		{
+			name:       "already exists",
+			body:       `{"order_uid":"order-1"}`,
+			saveErr:    model.ErrOrderAlreadyExists,
+			expectSave: true,
+			wantCode:   http.StatusConflict,
+			wantBody:   `{"error":"order already exists"}`,
+		},
+		{
+			name:       "internal error",
+			body:       `{"order_uid":"order-1"}`,
+			saveErr:    errors.New("db down"),
+			expectSave: true,
+			wantCode:   http.StatusInternalServerError,
+			wantBody:   `{"error":"failed to save order"}`,
+		},
+		{
+			name:     "malformed JSON",
+			body:     `{"order_uid":`,
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			saver, _, router := setupIngestTest(t)
+			if tt.expectSave {
+				saver.EXPECT().Execute(gomock.Any(), gomock.Any()).Return(tt.saveErr)
+			}
+
+			w := post(t, router, "/orders", "application/json", tt.body, nil)
+			assert.Equal(t, tt.wantCode, w.Code)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestIngestHandler_Create_ValidationErrorListsFields(t *testing.T) {
+	t.Parallel()
+
+	saver, _, router := setupIngestTest(t)
+	saver.EXPECT().Execute(gomock.Any(), gomock.Any()).Return(invalidOrderErr(t))
+
+	w := post(t, router, "/orders", "application/json", `{}`, nil)
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	var resp struct {
+		Error  string `json:"error"`
+		Fields []struct {
+			Field string `json:"field"`
+			Rule  string `json:"rule"`
+		} `json:"fields"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "invalid order data", resp.Error)
+	require.NotEmpty(t, resp.Fields)
+	assert.Equal(t, "order_uid", resp.Fields[0].Field)
+	assert.Equal(t, "required", resp.Fields[0].Rule)
+}
+
+func TestIngestHandler_CreateBulk(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+	}{
+		{
+			name:        "JSON array",
+			contentType: "application/json",
+			body:        `[{"order_uid":"a"},{"order_uid":"b"},null,{"order_uid":"c"}]`,
+		},
+		{
+			name:        "NDJSON",
+			contentType: "application/x-ndjson",
+			body:        "{\"order_uid\":\"a\"}\n{\"order_uid\":\"b\"}\n{broken\n\n{\"order_uid\":\"c\"}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			saver, _, router := setupIngestTest(t)
+			saver.EXPECT().
+				ExecuteBatch(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, orders []*model.Order) []error {
+					require.Len(t, orders, 3)
+					assert.Equal(t, "c", orders[2].OrderUID)
+					return []error{nil, model.ErrOrderAlreadyExists, invalidOrderErr(t)}
+				})
+
+			w := post(t, router, "/orders/bulk", tt.contentType, tt.body, nil)
+			require.Equal(t, http.StatusOK, w.Code)
+
+			var resp struct {
+				Created int `json:"created"`
+				Results []struct {
+					Index    int    `json:"index"`
+					OrderUID string `json:"order_uid"`
+					Status   int    `json:"status"`
+					Fields   []any  `json:"fields"`
+				} `json:"results"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, 1, resp.Created)
+			require.Len(t, resp.Results, 4)
+
+			statuses := make([]int, len(resp.Results))
+			for i, r := range resp.Results {
+				assert.Equal(t, i, r.Index)
+				statuses[i] = r.Status
+			}
+			assert.Equal(t, []int{http.StatusCreated, http.StatusConflict, http.StatusBadRequest, http.StatusUnprocessableEntity}, statuses)
+			assert.Equal(t, "c", resp.Results[3].OrderUID)
+			assert.NotEmpty(t, resp.Results[3].Fields)
+		})
+	}
+}
+
+func TestIngestHandler_CreateBulk_NotAnArray(t *testing.T) {
+	t.Parallel()
+
+	_, _, router := setupIngestTest(t)
+
+	w := post(t, router, "/orders/bulk", "application/json", `{"order_uid":"a"}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// failingReader fails like a connection the client dropped mid-request.
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("connection reset by peer")
+}
+
+func TestIngestHandler_BodyReadErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		path     string
+		body     io.Reader
+		wantCode int
+	}{
+		{name: "order too large", path: "/orders", body: strings.NewReader(strings.Repeat(" ", 1<<20+1)), wantCode: http.StatusRequestEntityTooLarge},
+		{name: "bulk too large", path: "/orders/bulk", body: strings.NewReader(strings.Repeat(" ", 16<<20+1)), wantCode: http.StatusRequestEntityTooLarge},
+		{name: "order read fails", path: "/orders", body: failingReader{}, wantCode: http.StatusBadRequest},
+		{name: "bulk read fails", path: "/orders/bulk", body: failingReader{}, wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, _, router := setupIngestTest(t)
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, tt.path, tt.body)
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
+
+func TestIngestHandler_Idempotency(t *testing.T) {
+	t.Parallel()
+
+	const body = `{"order_uid":"order-1"}`
+	key := map[string]string{handlers.IdempotencyKeyHeader: "key-1"}
+
+	t.Run("first request stores response", func(t *testing.T) {
+		t.Parallel()
+
+		saver, store, router := setupIngestTest(t)
+		store.EXPECT().Reserve(gomock.Any(), "key-1", gomock.Any()).Return(nil, true, nil)
+		saver.EXPECT().Execute(gomock.Any(), gomock.Any()).Return(nil)
+		store.EXPECT().
+			Complete(gomock.Any(), "key-1", gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ string, record *model.IdempotencyRecord) error {
+				assert.Equal(t, http.StatusCreated, record.StatusCode)
+				assert.JSONEq(t, `{"order_uid":"order-1"}`, string(record.Body))
+				assert.NotEmpty(t, record.RequestHash)
+				return nil
+			})
+
+		w := post(t, router, "/orders", "application/json", body, key)
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("retry replays stored response", func(t *testing.T) {
+		t.Parallel()
+
+		saver, store, router := setupIngestTest(t)
+		var hash string
+		store.EXPECT().
+			Reserve(gomock.Any(), "key-1", gomock.Any()).
+			DoAndReturn(func(_ context.Context, _, requestHash string) (*model.IdempotencyRecord, bool, error) {
+				hash = requestHash
+				return &model.IdempotencyRecord{
+					RequestHash: requestHash,
+					Completed:   true,
+					StatusCode:  http.StatusCreated,
+					Body:        []byte(`{"order_uid":"order-1"}`),
+				}, false, nil
+			})
+		saver.EXPECT().Execute(gomock.Any(), gomock.Any()).Times(0)
+
+		w := post(t, router, "/orders", "application/json", body, key)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, "true", w.Header().Get(handlers.IdempotentReplayedHeader))
+		assert.JSONEq(t, `{"order_uid":"order-1"}`, w.Body.String())
+		assert.NotEmpty(t, hash)
+	})
+
+	t.Run("same key with different body is refused", func(t *testing.T) {
+		t.Parallel()
+
+		_, store, router := setupIngestTest(t)
+		store.EXPECT().
+			Reserve(gomock.Any(), "key-1", gomock.Any()).
+			Return(&model.IdempotencyRecord{RequestHash: "other", Completed: true}, false, nil)
+
+		w := post(t, router, "/orders", "application/json", body, key)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+
+	t.Run("in-flight request conflicts", func(t *testing.T) {
+		t.Parallel()
+
+		_, store, router := setupIngestTest(t)
+		store.EXPECT().
+			Reserve(gomock.Any(), "key-1", gomock.Any()).
+			DoAndReturn(func(_ context.Context, _, requestHash string) (*model.IdempotencyRecord, bool, error) {
+				return &model.IdempotencyRecord{RequestHash: requestHash}, false, nil
+			})
+
+		w := post(t, router, "/orders", "application/json", body, key)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("server error releases key", func(t *testing.T) {
+		t.Parallel()
+
+		saver, store, router := setupIngestTest(t)
+		store.EXPECT().Reserve(gomock.Any(), "key-1", gomock.Any()).Return(nil, true, nil)
+		saver.EXPECT().Execute(gomock.Any(), gomock.Any()).Return(errors.New("db down"))
+		store.EXPECT().Release(gomock.Any(), "key-1").Return(nil)
+		store.EXPECT().Complete(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		w := post(t, router, "/orders", "application/json", body, key)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	t.Run("store unavailable refuses request", func(t *testing.T) {
+		t.Parallel()
+
+		saver, store, router := setupIngestTest(t)
+		store.EXPECT().Reserve(gomock.Any(), "key-1", gomock.Any()).Return(nil, false, errors.New("redis down")).Times(2)
+		saver.EXPECT().Execute(gomock.Any(), gomock.Any()).Times(0)
+		saver.EXPECT().ExecuteBatch(gomock.Any(), gomock.Any()).Times(0)
+
+		for _, path := range []string{"/orders", "/orders/bulk"} {
+			w := post(t, router, path, "application/json", body, key)
+			assert.Equal(t, http.StatusServiceUnavailable, w.Code, path)
+			assert.NotEmpty(t, w.Header().Get("Retry-After"), path)
+		}
+	})
+
+	t.Run("store unavailable without key still saves", func(t *testing.T) {
+		t.Parallel()
+
+		saver, store, router := setupIngestTest(t)
+		store.EXPECT().Reserve(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		saver.EXPECT().Execute(gomock.Any(), gomock.Any()).Return(nil)
+
+		w := post(t, router, "/orders", "application/json", body, nil)
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+}
@@ -12,12 +12,19 @@ import (
 )
 
 type OrderHandler struct {
-	getOrderUC repository.OrderUseCaseProvider
-	logger     *zap.Logger
+	getOrderUC   repository.OrderUseCaseProvider
+	listOrdersUC repository.OrderListProvider
+	getByTrackUC repository.OrderTrackProvider
+	logger       *zap.Logger
 }
 
-func NewOrderHandler(getOrderUC repository.OrderUseCaseProvider, logger *zap.Logger) *OrderHandler {
-	return &OrderHandler{getOrderUC: getOrderUC, logger: logger}
+func NewOrderHandler(
+	getOrderUC repository.OrderUseCaseProvider,
+	listOrdersUC repository.OrderListProvider,
+	getByTrackUC repository.OrderTrackProvider,
+	logger *zap.Logger,
+) *OrderHandler {
+	return &OrderHandler{getOrderUC: getOrderUC, listOrdersUC: listOrdersUC, getByTrackUC: getByTrackUC, logger: logger}
 }
 
 func (h *OrderHandler) GetByUID(c *gin.Context) {
@@ -40,3 +47,24 @@ func (h *OrderHandler) GetByUID(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, order)
 }
+
+func (h *OrderHandler) GetByTrack(c *gin.Context) {
+	trackNumber := c.Param("track_number")
+	if trackNumber == "" {
+		h.logger.Warn("Missing track_number parameter")
+		c.JSON(http.StatusBadRequest, gin.H{"error": "track_number is required"})
+		return
+	}
+
+	orders, err := h.getByTrackUC.Execute(c.Request.Context(), trackNumber)
+	if err != nil {
+		if errors.Is(err, model.ErrOrderNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+			return
+		}
+		h.logger.Error("Failed to get orders by track", zap.Error(err), zap.String("track_number", trackNumber))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get orders"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"orders": orders})
+}
@@ -26,7 +26,7 @@ func setupTest(t *testing.T) (*gomock.Controller, *mocks.MockOrderUseCaseProvide
 	mockUC := mocks.NewMockOrderUseCaseProvider(ctrl)
 	logger := zap.NewNop()
 
-	h := handlers.NewOrderHandler(mockUC, logger)
+	h := handlers.NewOrderHandler(mockUC, mocks.NewMockOrderListProvider(ctrl), mocks.NewMockOrderTrackProvider(ctrl), logger)
 
 	r := gin.New()
 	r.GET("/order/:order_uid", h.GetByUID)
@@ -110,7 +110,7 @@ func TestOrderHandler_GetByUID_EmptyParam(t *testing.T) {
 	defer ctrl.Finish()
 	mockUC := mocks.NewMockOrderUseCaseProvider(ctrl)
 	logger := zap.NewNop()
-	h := handlers.NewOrderHandler(mockUC, logger)
+	h := handlers.NewOrderHandler(mockUC, mocks.NewMockOrderListProvider(ctrl), mocks.NewMockOrderTrackProvider(ctrl), logger)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
@@ -122,3 +122,67 @@ func TestOrderHandler_GetByUID_EmptyParam(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 	assert.JSONEq(t, `{"error": "order_uid is required"}`, w.Body.String())
 }
+
+func TestOrderHandler_GetByTrack(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		result   []*model.Order
+		err      error
+		wantCode int
+		wantBody string
+	}{
+		{
+			name:     "found",
+			result:   []*model.Order{{OrderUID: "order-1"}},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "not found",
+			err:      model.ErrOrderNotFound,
+			wantCode: http.StatusNotFound,
+			wantBody: `{"error": "order not found"}`,
+		},
+		{
+			name:     "internal error",
+			err:      errors.New("db connection lost"),
+			wantCode: http.StatusInternalServerError,
+			wantBody: `{"error": "failed to get orders"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			gin.SetMode(gin.TestMode)
+
+			ctrl := gomock.NewController(t)
+			mockUC := mocks.NewMockOrderTrackProvider(ctrl)
+			h := handlers.NewOrderHandler(
+				mocks.NewMockOrderUseCaseProvider(ctrl), mocks.NewMockOrderListProvider(ctrl), mockUC, zap.NewNop())
+
+			r := gin.New()
+			r.GET("/orders/by-track/:track_number", h.GetByTrack)
+
+			mockUC.EXPECT().Execute(gomock.Any(), "TRACK-1").Return(tt.result, tt.err)
+
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "/orders/by-track/TRACK-1", nil)
+			require.NoError(t, err)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, w.Body.String())
+				return
+			}
+			var resp struct {
+				Orders []model.Order `json:"orders"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			require.Len(t, resp.Orders, 1)
+			assert.Equal(t, "order-1", resp.Orders[0].OrderUID)
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"l0/internal/domain/model"
+	"l0/internal/domain/repository/mocks"
+	"l0/internal/infrastructure/http/handlers"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func setupListTest(t *testing.T) (*mocks.MockOrderListProvider, *gin.Engine) {
+	gin.SetMode(gin.TestMode)
+
+	ctrl := gomock.NewController(t)
+	mockUC := mocks.NewMockOrderListProvider(ctrl)
+
+	h := handlers.NewOrderHandler(mocks.NewMockOrderUseCaseProvider(ctrl), mockUC, mocks.NewMockOrderTrackProvider(ctrl), zap.NewNop())
+
+	r := gin.New()
+	r.GET("/orders", h.List)
+
+	return mockUC, r
+}
+
+func serveList(t *testing.T, router *gin.Engine, query string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "/orders"+query, nil)
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestOrderHandler_List_FiltersAndCursorRoundTrip(t *testing.T) {
+	mockUC, router := setupListTest(t)
+
+	next := &model.OrderCursor{
+		DateCreated: time.Date(2021, 11, 26, 6, 22, 19, 0, time.UTC),
+		OrderUID:    "order-2",
+	}
+
+	mockUC.EXPECT().
+		Execute(gomock.Any(), model.OrderFilter{
+			CustomerID:  "cust-1",
+			Locale:      "en",
+			CreatedFrom: time.Date(2021, 11, 1, 0, 0, 0, 0, time.UTC),
+			Limit:       2,
+		}).
+		Return(&model.OrderPage{
+			Orders:     []*model.Order{{OrderUID: "order-1"}, {OrderUID: "order-2"}},
+			NextCursor: next,
+		}, nil)
+
+	w := serveList(t, router, "?customer_id=cust-1&locale=en&created_from=2021-11-01T00:00:00Z&limit=2")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct {
+		Orders     []model.Order `json:"orders"`
+		NextCursor string        `json:"next_cursor"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Orders, 2)
+	require.NotEmpty(t, resp.NextCursor)
+
+	mockUC.EXPECT().
+		Execute(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, filter model.OrderFilter) (*model.OrderPage, error) {
+			require.NotNil(t, filter.Cursor)
+			assert.Equal(t, next.OrderUID, filter.Cursor.OrderUID)
+			assert.True(t, next.DateCreated.Equal(filter.Cursor.DateCreated))
+			return &model.OrderPage{Orders: []*model.Order{}}, nil
+		})
+
+	w = serveList(t, router, "?cursor="+resp.NextCursor)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"orders": []}`, w.Body.String())
+}
+
+func TestOrderHandler_List_BadRequest(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"negative limit", "?limit=-1", "limit must be a positive integer"},
+		{"non-numeric limit", "?limit=ten", "limit must be a positive integer"},
+		{"bad created_from", "?created_from=yesterday", "created_from must be an RFC 3339 timestamp"},
+		{"inverted range", "?created_from=2021-12-01T00:00:00Z&created_to=2021-11-01T00:00:00Z", "created_from must not be after created_to"},
+		{"garbage cursor", "?cursor=!!!", "invalid cursor"},
+		{"empty cursor payload", "?cursor=e30", "invalid cursor"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, router := setupListTest(t)
+
+			w := serveList(t, router, tt.query)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.JSONEq(t, `{"error": "`+tt.want+`"}`, w.Body.String())
+		})
+	}
+}
+
+func TestOrderHandler_List_InternalError(t *testing.T) {
+	mockUC, router := setupListTest(t)
+
+	mockUC.EXPECT().
+		Execute(gomock.Any(), gomock.Any()).
+		Return(nil, errors.New("db connection lost"))
+
+	w := serveList(t, router, "")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.JSONEq(t, `{"error": "failed to list orders"}`, w.Body.String())
+}
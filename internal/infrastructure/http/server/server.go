@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"l0/internal/infrastructure/http/handlers"
+	"l0/internal/infrastructure/metrics"
+	"l0/internal/infrastructure/tracing"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -18,30 +20,41 @@ type Server struct {
 	logger     *zap.Logger
 }
 
-func NewServer(orderHandler *handlers.OrderHandler, logger *zap.Logger) *Server {
+func NewServer(orderHandler *handlers.OrderHandler, ingestHandler *handlers.IngestHandler, healthHandler *handlers.HealthHandler, logger *zap.Logger) *Server {
 	gin.SetMode(gin.ReleaseMode)
 	r := gin.New()
 	if err := r.SetTrustedProxies(nil); err != nil {
 		logger.Error("Failed to set trusted proxies", zap.Error(err))
 	}
 	r.Use(gin.Logger())
+	r.Use(tracing.GinMiddleware())
 	r.Use(gin.Recovery())
+	r.Use(metrics.GinMiddleware())
 
 	server := &Server{
 		logger: logger,
 		Router: r,
 	}
-	server.setupRoutes(*orderHandler)
+	server.setupRoutes(*orderHandler, ingestHandler, healthHandler)
 	return server
 }
 
-func (s *Server) setupRoutes(orderHandler handlers.OrderHandler) {
+func (s *Server) setupRoutes(orderHandler handlers.OrderHandler, ingestHandler *handlers.IngestHandler, healthHandler *handlers.HealthHandler) {
 	s.Router.Static("/web", "./web")
 	s.Router.GET("/", func(c *gin.Context) {
 		c.File("./web/index.html")
 	})
 
+	s.Router.GET("/metrics", gin.WrapH(metrics.Handler()))
+	s.Router.GET("/healthz", healthHandler.Live)
+	s.Router.GET("/readyz", healthHandler.Ready)
+
 	s.Router.GET("/order/:order_uid", orderHandler.GetByUID)
+	s.Router.GET("/orders", orderHandler.List)
+	s.Router.GET("/orders/by-track/:track_number", orderHandler.GetByTrack)
+
+	s.Router.POST("/orders", ingestHandler.Create)
+	s.Router.POST("/orders/bulk", ingestHandler.CreateBulk)
 }
 
 func (s *Server) Start(addr string) error {
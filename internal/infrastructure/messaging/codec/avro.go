@@ -0,0 +1,110 @@
+package codec
+
+import (
+	"errors"
+	"fmt"
+
+	"l0/internal/domain/model"
+
+	"github.com/hamba/avro/v2"
+	"github.com/hamba/avro/v2/soe"
+)
+
+// OrderSubject names the order schemas in the schema registry.
+const OrderSubject = "order"
+
+// avroAPI maps record fields by the model's json tags, so the domain types
+// need no Avro tags of their own.
+var avroAPI = avro.Config{TagKey: "json"}.Freeze()
+
+// avroOrderVersions maps the registry versions of the order subject to the
+// order schema versions they hold. Avro orders were introduced at order
+// version 2, which was registered as version 1. A new order schema version
+// gets the next registry version and an entry here; registered versions are
+// never renumbered, as producers already wrote their fingerprints.
+var avroOrderVersions = map[int]int{
+	1: 2,
+}
+
+// Avro encodes orders in Avro single object encoding: each payload starts
+// with the fingerprint of the schema it was written with. Orders are written
+// with the registry's order schema of CurrentSchemaVersion and read with
+// whichever version wrote them; older versions are upcast. A fingerprint
+// the registry does not know, or a registry version without an order
+// version, is a version this service cannot read.
+type Avro struct {
+	registry *SchemaRegistry
+	encoder  *soe.Codec
+	// orderVersions maps registry versions to order schema versions.
+	orderVersions map[int]int
+}
+
+func NewAvro(registry *SchemaRegistry) (*Avro, error) {
+	return newAvro(registry, avroOrderVersions)
+}
+
+func newAvro(registry *SchemaRegistry, orderVersions map[int]int) (*Avro, error) {
+	registryVersion := 0
+	for rv, ov := range orderVersions {
+		if ov == CurrentSchemaVersion {
+			registryVersion = rv
+		}
+	}
+	if registryVersion == 0 {
+		return nil, fmt.Errorf("%w: no registry version of %s holds order version %d", ErrUnknownSchema, OrderSubject, CurrentSchemaVersion)
+	}
+	current, err := registry.Version(OrderSubject, registryVersion)
+	if err != nil {
+		return nil, err
+	}
+	encoder, err := soe.NewCodecWithAPI(current.Schema, avroAPI)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create avro encoder: %w", err)
+	}
+	return &Avro{registry: registry, encoder: encoder, orderVersions: orderVersions}, nil
+}
+
+func (*Avro) ContentType() string {
+	return ContentTypeAvro
+}
+
+func (a *Avro) Encode(order *model.Order) ([]byte, error) {
+	return a.encoder.Encode(order)
+}
+
+func (a *Avro) Decode(data []byte, version int, order *model.Order) (int, error) {
+	fingerprint, body, err := soe.ParseHeader(data)
+	if err != nil {
+		return 0, fmt.Errorf("failed to decode avro order: %w", err)
+	}
+	writer, err := a.registry.Lookup(fingerprint)
+	if errors.Is(err, ErrUnknownSchema) {
+		return 0, fmt.Errorf("%w: %w", ErrUnsupportedSchemaVersion, err)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if writer.Subject != OrderSubject {
+		return 0, fmt.Errorf("avro payload is a %s, not an order", writer.Subject)
+	}
+	orderVersion, ok := a.orderVersions[writer.Version]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s registry version %d", ErrUnsupportedSchemaVersion, OrderSubject, writer.Version)
+	}
+	version, err = resolveVersion(version, orderVersion, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	if version == CurrentSchemaVersion {
+		if err := avroAPI.Unmarshal(writer.Schema, body, order); err != nil {
+			return version, fmt.Errorf("failed to decode avro order: %w", err)
+		}
+		return version, nil
+	}
+	var doc map[string]any
+	if err := avroAPI.Unmarshal(writer.Schema, body, &doc); err != nil {
+		return version, fmt.Errorf("failed to decode avro order: %w", err)
+	}
+	return version, upcast(doc, version, order)
+}
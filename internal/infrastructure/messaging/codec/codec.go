@@ -0,0 +1,104 @@
+// Package codec encodes and decodes order payloads. The encoding of a message
+// is named by its content type; messages without one are JSON. Payloads of an
+// older schema version are upcast to the current one, and versions newer than
+// the current one are rejected rather than half-read.
+package codec
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+
+	"l0/internal/domain/model"
+)
+
+const (
+	ContentTypeJSON     = "application/json"
+	ContentTypeProtobuf = "application/x-protobuf"
+	ContentTypeAvro     = "application/avro"
+)
+
+var (
+	ErrUnsupportedContentType   = errors.New("unsupported content type")
+	ErrUnsupportedSchemaVersion = errors.New("unsupported schema version")
+)
+
+// Codec converts orders to and from one wire format. Orders are encoded in
+// CurrentSchemaVersion.
+type Codec interface {
+	ContentType() string
+	Encode(order *model.Order) ([]byte, error)
+	// Decode reads data written in the given schema version, which is 0 if
+	// the message does not name one, and returns the version it was
+	// written in.
+	Decode(data []byte, version int, order *model.Order) (int, error)
+}
+
+// Registry picks the codec for a message by its content type.
+type Registry struct {
+	fallback Codec
+	codecs   map[string]Codec
+}
+
+// NewRegistry serves fallback for messages without a content type, and
+// every codec, fallback included, for its own content type.
+func NewRegistry(fallback Codec, codecs ...Codec) *Registry {
+	r := &Registry{fallback: fallback, codecs: make(map[string]Codec, len(codecs)+1)}
+	for _, c := range append([]Codec{fallback}, codecs...) {
+		r.codecs[c.ContentType()] = c
+	}
+	return r
+}
+
+// NewOrderRegistry serves JSON by default, and Protobuf and Avro with the
+// order schemas of schemaDir.
+func NewOrderRegistry(schemaDir string) (*Registry, error) {
+	schemas, err := LoadSchemaRegistry(schemaDir)
+	if err != nil {
+		return nil, err
+	}
+	avroCodec, err := NewAvro(schemas)
+	if err != nil {
+		return nil, err
+	}
+	return NewRegistry(NewJSON(), NewProtobuf(), avroCodec), nil
+}
+
+// Lookup returns the codec for contentType. Parameters such as charset are
+// ignored.
+func (r *Registry) Lookup(contentType string) (Codec, error) {
+	if strings.TrimSpace(contentType) == "" {
+		return r.fallback, nil
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedContentType, contentType)
+	}
+	c, ok := r.codecs[mediaType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedContentType, contentType)
+	}
+	return c, nil
+}
+
+// Decode decodes data with the codec for contentType and returns the order
+// with the schema version it was written in. version is the one the message
+// names, or 0.
+func (r *Registry) Decode(contentType string, version int, data []byte) (*model.Order, int, error) {
+	c, err := r.Lookup(contentType)
+	if err != nil {
+		return nil, 0, err
+	}
+	if version != 0 {
+		if err := checkVersion(version); err != nil {
+			return nil, 0, err
+		}
+	}
+	var order model.Order
+	version, err = c.Decode(data, version, &order)
+	if err != nil {
+		return nil, version, err
+	}
+	return &order, version, nil
+}
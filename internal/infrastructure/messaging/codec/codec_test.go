@@ -0,0 +1,234 @@
+package codec
+
+import (
+	"testing"
+
+	"l0/internal/domain/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// schemaDir holds the schemas the service ships with.
+const schemaDir = "../../../../schemas"
+
+func testOrder() *model.Order {
+	return &model.Order{
+		OrderUID:    "b563feb7b2b84b6test",
+		TrackNumber: "WBILMTESTTRACK",
+		Entry:       "WBIL",
+		Delivery: model.Delivery{
+			Name:    "Test Testov",
+			Phone:   "+9720000000",
+			Zip:     "2639809",
+			City:    "Kiryat Mozkin",
+			Address: "Ploshad Mira 15",
+			Region:  "Kraiot",
+			Email:   "test@gmail.com",
+		},
+		Payment: model.Payment{
+			Transaction:  "b563feb7b2b84b6test",
+			Currency:     "USD",
+			Provider:     "wbpay",
+			Amount:       1817,
+			PaymentDt:    1637907727,
+			Bank:         "alpha",
+			DeliveryCost: 1500,
+			GoodsTotal:   317,
+		},
+		Items: []model.Item{
+			{ChrtID: 9934930, TrackNumber: "WBILMTESTTRACK", Price: 453, Rid: "ab4219087a764ae0btest", Name: "Mascaras", Sale: 30, Size: "0", TotalPrice: 317, NmID: 2389212, Brand: "Vivienne Sabo", Status: 202},
+			{ChrtID: 1, TrackNumber: "WBILMTESTTRACK", Price: 1, Rid: "rid-2", Name: "Brush", Size: "0", TotalPrice: 1, NmID: 2, Brand: "Sabo", Status: 202},
+		},
+		Locale:          "en",
+		CustomerID:      "test",
+		DeliveryService: "meest",
+		Shardkey:        "9",
+		SmID:            99,
+		DateCreated:     "2021-11-26T06:22:19Z",
+		OofShard:        "1",
+	}
+}
+
+func newTestRegistry(t *testing.T) *Registry {
+	t.Helper()
+	registry, err := NewOrderRegistry(schemaDir)
+	require.NoError(t, err)
+	return registry
+}
+
+func TestCodecs_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	registry := newTestRegistry(t)
+	for _, contentType := range []string{ContentTypeJSON, ContentTypeProtobuf, ContentTypeAvro} {
+		t.Run(contentType, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := registry.Lookup(contentType)
+			require.NoError(t, err)
+			data, err := c.Encode(testOrder())
+			require.NoError(t, err)
+
+			decoded, version, err := registry.Decode(contentType, CurrentSchemaVersion, data)
+			require.NoError(t, err)
+			assert.Equal(t, CurrentSchemaVersion, version)
+			assert.Equal(t, testOrder(), decoded)
+		})
+	}
+}
+
+func TestRegistry_Lookup(t *testing.T) {
+	t.Parallel()
+
+	registry := NewRegistry(NewJSON(), NewProtobuf())
+
+	tests := []struct {
+		contentType string
+		want        string
+		wantErr     bool
+	}{
+		{contentType: "", want: ContentTypeJSON},
+		{contentType: "application/json; charset=utf-8", want: ContentTypeJSON},
+		{contentType: "Application/X-Protobuf", want: ContentTypeProtobuf},
+		{contentType: ContentTypeAvro, wantErr: true},
+		{contentType: "not a media type;", wantErr: true},
+	}
+	for _, tt := range tests {
+		c, err := registry.Lookup(tt.contentType)
+		if tt.wantErr {
+			require.ErrorIs(t, err, ErrUnsupportedContentType, tt.contentType)
+			continue
+		}
+		require.NoError(t, err, tt.contentType)
+		assert.Equal(t, tt.want, c.ContentType())
+	}
+}
+
+func TestProtobuf_Decode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unknown fields are skipped", func(t *testing.T) {
+		t.Parallel()
+
+		data, err := NewProtobuf().Encode(testOrder())
+		require.NoError(t, err)
+		data = protowire.AppendTag(data, 99, protowire.Fixed64Type)
+		data = protowire.AppendFixed64(data, 42)
+		data = protowire.AppendTag(data, 100, protowire.BytesType)
+		data = protowire.AppendString(data, "added by a newer producer")
+
+		var order model.Order
+		_, err = NewProtobuf().Decode(data, 0, &order)
+		require.NoError(t, err)
+		assert.Equal(t, testOrder(), &order)
+	})
+
+	t.Run("invalid utf-8", func(t *testing.T) {
+		t.Parallel()
+
+		data := protowire.AppendTag(nil, 1, protowire.BytesType)
+		data = protowire.AppendBytes(data, []byte{0xff, 0xfe})
+
+		var order model.Order
+		_, err := NewProtobuf().Decode(data, 0, &order)
+		assert.ErrorContains(t, err, "failed to decode protobuf order")
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		t.Parallel()
+
+		data, err := NewProtobuf().Encode(testOrder())
+		require.NoError(t, err)
+
+		var order model.Order
+		_, err = NewProtobuf().Decode(data[:len(data)-1], 0, &order)
+		assert.Error(t, err)
+	})
+
+	t.Run("older version", func(t *testing.T) {
+		t.Parallel()
+
+		var order model.Order
+		_, err := NewProtobuf().Decode(nil, 1, &order)
+		assert.ErrorIs(t, err, ErrUnsupportedSchemaVersion)
+	})
+}
+
+func TestRegistry_Decode_SchemaVersions(t *testing.T) {
+	t.Parallel()
+
+	registry := NewRegistry(NewJSON(), NewProtobuf())
+	legacy := `{"order_uid":"order-1","locale":"ru"}`
+	upcast := &model.Order{OrderUID: "order-1", Locale: "ru", Status: model.OrderStatusCreated}
+
+	tests := []struct {
+		name        string
+		contentType string
+		version     int
+		payload     string
+		want        *model.Order
+		wantVersion int
+		wantErr     error
+	}{
+		{name: "json without a version is upcast from v1", payload: legacy, want: upcast, wantVersion: 1},
+		{name: "json v1 in header", version: 1, payload: legacy, want: upcast, wantVersion: 1},
+		{name: "json v1 in payload", payload: `{"schema_version":1,"order_uid":"order-1","locale":"ru"}`, want: upcast, wantVersion: 1},
+		{
+			name:        "json current version is read as is",
+			version:     CurrentSchemaVersion,
+			payload:     `{"order_uid":"order-1","status":"paid"}`,
+			want:        &model.Order{OrderUID: "order-1", Status: model.OrderStatusPaid},
+			wantVersion: CurrentSchemaVersion,
+		},
+		{
+			name:        "json current version in payload",
+			payload:     `{"schema_version":2,"order_uid":"order-1"}`,
+			want:        &model.Order{OrderUID: "order-1"},
+			wantVersion: CurrentSchemaVersion,
+		},
+		{
+			name:        "large numbers survive upcasting",
+			payload:     `{"order_uid":"order-1","payment":{"payment_dt":9007199254740993}}`,
+			want:        &model.Order{OrderUID: "order-1", Payment: model.Payment{PaymentDt: 9007199254740993}, Status: model.OrderStatusCreated},
+			wantVersion: 1,
+		},
+		{name: "future version in header", version: CurrentSchemaVersion + 1, payload: legacy, wantErr: ErrUnsupportedSchemaVersion},
+		{name: "future version in payload", payload: `{"schema_version":3,"order_uid":"order-1"}`, wantErr: ErrUnsupportedSchemaVersion},
+		{name: "future protobuf version", contentType: ContentTypeProtobuf, version: CurrentSchemaVersion + 1, wantErr: ErrUnsupportedSchemaVersion},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			order, version, err := registry.Decode(tt.contentType, tt.version, []byte(tt.payload))
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantVersion, version)
+			assert.Equal(t, tt.want, order)
+		})
+	}
+}
+
+func TestRegistry_Decode_ConflictingVersions(t *testing.T) {
+	t.Parallel()
+
+	_, _, err := NewRegistry(NewJSON()).Decode("", 1, []byte(`{"schema_version":2}`))
+
+	require.ErrorIs(t, err, ErrUnsupportedSchemaVersion)
+	assert.ErrorContains(t, err, "conflicts")
+}
+
+func TestUpcasters_CoverEveryOlderVersion(t *testing.T) {
+	t.Parallel()
+
+	for v := 1; v < CurrentSchemaVersion; v++ {
+		assert.Contains(t, upcasters, v, "no upcaster from version %d", v)
+	}
+}
@@ -0,0 +1,53 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"l0/internal/domain/model"
+)
+
+// JSON reads the schema version from the message or, failing that, from a
+// schema_version field of the payload. Payloads naming neither are version 1.
+type JSON struct{}
+
+func NewJSON() JSON {
+	return JSON{}
+}
+
+func (JSON) ContentType() string {
+	return ContentTypeJSON
+}
+
+func (JSON) Encode(order *model.Order) ([]byte, error) {
+	return json.Marshal(order)
+}
+
+// Decode reads the payload once, along with its schema_version, and only
+// reads it again as a document when it has to be upcast.
+func (JSON) Decode(data []byte, version int, order *model.Order) (int, error) {
+	var payload struct {
+		model.Order
+		SchemaVersion int `json:"schema_version"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return 0, err
+	}
+	version, err := resolveVersion(version, payload.SchemaVersion, legacySchemaVersion)
+	if err != nil {
+		return 0, err
+	}
+	if version == CurrentSchemaVersion {
+		*order = payload.Order
+		return version, nil
+	}
+
+	var doc map[string]any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	// Numbers stay as written, so large IDs survive the round trip.
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return version, err
+	}
+	return version, upcast(doc, version, order)
+}
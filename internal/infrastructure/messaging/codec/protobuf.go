@@ -0,0 +1,157 @@
+package codec
+
+import (
+	"fmt"
+
+	"l0/internal/domain/model"
+	"l0/internal/infrastructure/messaging/codec/orderpb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+//go:generate protoc -I ../../../../schemas --go_out=orderpb --go_opt=paths=source_relative order.proto
+
+// Protobuf encodes orders as the messages of schemas/order.proto, using the
+// types generated into orderpb. Unknown fields are skipped and missing ones
+// decode as zero values. Protobuf orders were introduced at
+// CurrentSchemaVersion, so there is nothing to upcast.
+type Protobuf struct{}
+
+func NewProtobuf() Protobuf {
+	return Protobuf{}
+}
+
+func (Protobuf) ContentType() string {
+	return ContentTypeProtobuf
+}
+
+func (Protobuf) Encode(order *model.Order) ([]byte, error) {
+	data, err := proto.Marshal(toProto(order))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode protobuf order: %w", err)
+	}
+	return data, nil
+}
+
+func (Protobuf) Decode(data []byte, version int, order *model.Order) (int, error) {
+	if version != 0 && version != CurrentSchemaVersion {
+		return 0, fmt.Errorf("%w: protobuf orders have version %d only, got %d", ErrUnsupportedSchemaVersion, CurrentSchemaVersion, version)
+	}
+	var msg orderpb.Order
+	if err := proto.Unmarshal(data, &msg); err != nil {
+		return CurrentSchemaVersion, fmt.Errorf("failed to decode protobuf order: %w", err)
+	}
+	fromProto(&msg, order)
+	return CurrentSchemaVersion, nil
+}
+
+func toProto(o *model.Order) *orderpb.Order {
+	items := make([]*orderpb.Item, len(o.Items))
+	for i, it := range o.Items {
+		items[i] = &orderpb.Item{
+			ChrtId:      int64(it.ChrtID),
+			TrackNumber: it.TrackNumber,
+			Price:       int64(it.Price),
+			Rid:         it.Rid,
+			Name:        it.Name,
+			Sale:        int64(it.Sale),
+			Size:        it.Size,
+			TotalPrice:  int64(it.TotalPrice),
+			NmId:        int64(it.NmID),
+			Brand:       it.Brand,
+			Status:      int64(it.Status),
+		}
+	}
+	return &orderpb.Order{
+		OrderUid:    o.OrderUID,
+		TrackNumber: o.TrackNumber,
+		Entry:       o.Entry,
+		Delivery: &orderpb.Delivery{
+			Name:    o.Delivery.Name,
+			Phone:   o.Delivery.Phone,
+			Zip:     o.Delivery.Zip,
+			City:    o.Delivery.City,
+			Address: o.Delivery.Address,
+			Region:  o.Delivery.Region,
+			Email:   o.Delivery.Email,
+		},
+		Payment: &orderpb.Payment{
+			Transaction:  o.Payment.Transaction,
+			RequestId:    o.Payment.RequestID,
+			Currency:     o.Payment.Currency,
+			Provider:     o.Payment.Provider,
+			Amount:       int64(o.Payment.Amount),
+			PaymentDt:    o.Payment.PaymentDt,
+			Bank:         o.Payment.Bank,
+			DeliveryCost: int64(o.Payment.DeliveryCost),
+			GoodsTotal:   int64(o.Payment.GoodsTotal),
+			CustomFee:    int64(o.Payment.CustomFee),
+		},
+		Items:             items,
+		Locale:            o.Locale,
+		InternalSignature: o.InternalSignature,
+		CustomerId:        o.CustomerID,
+		DeliveryService:   o.DeliveryService,
+		Shardkey:          o.Shardkey,
+		SmId:              int64(o.SmID),
+		DateCreated:       o.DateCreated,
+		OofShard:          o.OofShard,
+		Status:            string(o.Status),
+	}
+}
+
+// fromProto relies on the generated getters returning zero values for
+// missing messages.
+func fromProto(msg *orderpb.Order, o *model.Order) {
+	delivery, payment := msg.GetDelivery(), msg.GetPayment()
+	*o = model.Order{
+		OrderUID:    msg.GetOrderUid(),
+		TrackNumber: msg.GetTrackNumber(),
+		Entry:       msg.GetEntry(),
+		Delivery: model.Delivery{
+			Name:    delivery.GetName(),
+			Phone:   delivery.GetPhone(),
+			Zip:     delivery.GetZip(),
+			City:    delivery.GetCity(),
+			Address: delivery.GetAddress(),
+			Region:  delivery.GetRegion(),
+			Email:   delivery.GetEmail(),
+		},
+		Payment: model.Payment{
+			Transaction:  payment.GetTransaction(),
+			RequestID:    payment.GetRequestId(),
+			Currency:     payment.GetCurrency(),
+			Provider:     payment.GetProvider(),
+			Amount:       int(payment.GetAmount()),
+			PaymentDt:    payment.GetPaymentDt(),
+			Bank:         payment.GetBank(),
+			DeliveryCost: int(payment.GetDeliveryCost()),
+			GoodsTotal:   int(payment.GetGoodsTotal()),
+			CustomFee:    int(payment.GetCustomFee()),
+		},
+		Locale:            msg.GetLocale(),
+		InternalSignature: msg.GetInternalSignature(),
+		CustomerID:        msg.GetCustomerId(),
+		DeliveryService:   msg.GetDeliveryService(),
+		Shardkey:          msg.GetShardkey(),
+		SmID:              int(msg.GetSmId()),
+		DateCreated:       msg.GetDateCreated(),
+		OofShard:          msg.GetOofShard(),
+		Status:            model.OrderStatus(msg.GetStatus()),
+	}
+	for _, it := range msg.GetItems() {
+		o.Items = append(o.Items, model.Item{
+			ChrtID:      int(it.GetChrtId()),
+			TrackNumber: it.GetTrackNumber(),
+			Price:       int(it.GetPrice()),
+			Rid:         it.GetRid(),
+			Name:        it.GetName(),
+			Sale:        int(it.GetSale()),
+			Size:        it.GetSize(),
+			TotalPrice:  int(it.GetTotalPrice()),
+			NmID:        int(it.GetNmId()),
+			Brand:       it.GetBrand(),
+			Status:      int(it.GetStatus()),
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package codec
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/hamba/avro/v2"
+	"github.com/hamba/avro/v2/soe"
+)
+
+var ErrUnknownSchema = errors.New("unknown schema")
+
+// schemaFileName matches <subject>.v<version>.avsc.
+var schemaFileName = regexp.MustCompile(`^([A-Za-z0-9_-]+)\.v([0-9]+)\.avsc$`)
+
+// SchemaVersion is one registered version of a subject's Avro schema.
+type SchemaVersion struct {
+	Subject     string
+	Version     int
+	Schema      avro.Schema
+	Fingerprint []byte
+}
+
+// SchemaRegistry is a local stand-in for a schema registry service: every
+// <subject>.v<version>.avsc file of a directory is one version of a subject.
+// Readers find the writer's version by the schema fingerprint in the message,
+// so a new version only needs a new file. Registry versions are numbered per
+// subject; the Avro codec maps those of the order subject to order schema
+// versions.
+type SchemaRegistry struct {
+	subjects      map[string][]SchemaVersion
+	byFingerprint map[string]SchemaVersion
+}
+
+// LoadSchemaRegistry reads the Avro schemas of dir. Other files are ignored.
+func LoadSchemaRegistry(dir string) (*SchemaRegistry, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema dir: %w", err)
+	}
+
+	r := &SchemaRegistry{
+		subjects:      make(map[string][]SchemaVersion),
+		byFingerprint: make(map[string]SchemaVersion),
+	}
+	for _, entry := range entries {
+		match := schemaFileName.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		version, err := strconv.Atoi(match[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid schema version in %s: %w", entry.Name(), err)
+		}
+		if err := r.register(match[1], version, filepath.Join(dir, entry.Name())); err != nil {
+			return nil, err
+		}
+	}
+
+	for subject, versions := range r.subjects {
+		sort.Slice(versions, func(i, j int) bool { return versions[i].Version < versions[j].Version })
+		for i := 1; i < len(versions); i++ {
+			if versions[i].Version == versions[i-1].Version {
+				return nil, fmt.Errorf("schema %s version %d is defined twice", subject, versions[i].Version)
+			}
+		}
+	}
+	return r, nil
+}
+
+func (r *SchemaRegistry) register(subject string, version int, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read schema: %w", err)
+	}
+	// Each file gets its own cache, so versions can reuse record names.
+	schema, err := avro.ParseBytesWithCache(data, "", &avro.SchemaCache{})
+	if err != nil {
+		return fmt.Errorf("failed to parse schema %s: %w", path, err)
+	}
+	fingerprint, err := soe.ComputeFingerprint(schema)
+	if err != nil {
+		return fmt.Errorf("failed to fingerprint schema %s: %w", path, err)
+	}
+
+	sv := SchemaVersion{Subject: subject, Version: version, Schema: schema, Fingerprint: fingerprint}
+	if existing, ok := r.byFingerprint[hex.EncodeToString(fingerprint)]; ok {
+		return fmt.Errorf("schema %s version %d is identical to %s version %d", subject, version, existing.Subject, existing.Version)
+	}
+	r.subjects[subject] = append(r.subjects[subject], sv)
+	r.byFingerprint[hex.EncodeToString(fingerprint)] = sv
+	return nil
+}
+
+// Version returns the given version of subject.
+func (r *SchemaRegistry) Version(subject string, version int) (SchemaVersion, error) {
+	for _, sv := range r.subjects[subject] {
+		if sv.Version == version {
+			return sv, nil
+		}
+	}
+	return SchemaVersion{}, fmt.Errorf("%w: %s version %d", ErrUnknownSchema, subject, version)
+}
+
+// Lookup returns the schema with the given fingerprint.
+func (r *SchemaRegistry) Lookup(fingerprint []byte) (SchemaVersion, error) {
+	sv, ok := r.byFingerprint[hex.EncodeToString(fingerprint)]
+	if !ok {
+		return SchemaVersion{}, fmt.Errorf("%w: fingerprint %x", ErrUnknownSchema, fingerprint)
+	}
+	return sv, nil
+}
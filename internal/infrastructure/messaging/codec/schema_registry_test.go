@@ -0,0 +1,156 @@
+package codec
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"l0/internal/domain/model"
+
+	"github.com/hamba/avro/v2"
+	"github.com/hamba/avro/v2/soe"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeSchema stores a minimal order schema as the given file of dir.
+func writeSchema(t *testing.T, dir, name, fields string) {
+	t.Helper()
+	schema := `{"type":"record","name":"Order","namespace":"l0.order","fields":[` + fields + `]}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(schema), 0o600))
+}
+
+// encodeWith writes order in Avro single object encoding with schema.
+func encodeWith(t *testing.T, schema avro.Schema, order *model.Order) []byte {
+	t.Helper()
+	c, err := soe.NewCodecWithAPI(schema, avroAPI)
+	require.NoError(t, err)
+	data, err := c.Encode(order)
+	require.NoError(t, err)
+	return data
+}
+
+const (
+	v1Fields = `{"name":"order_uid","type":"string"},{"name":"locale","type":"string"}`
+	v2Fields = v1Fields + `,{"name":"status","type":"string","default":""}`
+	v3Fields = v2Fields + `,{"name":"channel","type":"string","default":"web"}`
+)
+
+func TestAvro_RegisteredSchemas(t *testing.T) {
+	t.Parallel()
+
+	registry, err := LoadSchemaRegistry(filepath.Join("..", "..", "..", "..", "schemas"))
+	require.NoError(t, err)
+	avroCodec, err := NewAvro(registry)
+	require.NoError(t, err)
+
+	// Producers have been writing order version 2 with registry version 1
+	// since Avro was introduced, so that fingerprint must keep decoding.
+	v1, err := registry.Version(OrderSubject, 1)
+	require.NoError(t, err)
+	data, err := avroCodec.Encode(&model.Order{OrderUID: "order-1", Status: model.OrderStatusPaid})
+	require.NoError(t, err)
+	fingerprint, _, err := soe.ParseHeader(data)
+	require.NoError(t, err)
+	assert.Equal(t, v1.Fingerprint, fingerprint)
+
+	var order model.Order
+	version, err := avroCodec.Decode(data, 0, &order)
+	require.NoError(t, err)
+	assert.Equal(t, CurrentSchemaVersion, version)
+	assert.Equal(t, model.OrderStatusPaid, order.Status)
+}
+
+func TestAvro_DecodesEveryVersion(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeSchema(t, dir, "order.v1.avsc", v1Fields)
+	writeSchema(t, dir, "order.v2.avsc", v2Fields)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a schema"), 0o600))
+
+	registry, err := LoadSchemaRegistry(dir)
+	require.NoError(t, err)
+	// As if Avro had carried order version 1 too, to exercise the upcast.
+	avroCodec, err := newAvro(registry, map[int]int{1: 1, 2: 2})
+	require.NoError(t, err)
+
+	// A message from a producer still on v1.
+	v1, err := registry.Version(OrderSubject, 1)
+	require.NoError(t, err)
+	old := encodeWith(t, v1.Schema, &model.Order{OrderUID: "order-1", Locale: "ru"})
+
+	var order model.Order
+	version, err := avroCodec.Decode(old, 0, &order)
+	require.NoError(t, err)
+	assert.Equal(t, 1, version)
+	assert.Equal(t, model.Order{OrderUID: "order-1", Locale: "ru", Status: model.OrderStatusCreated}, order)
+
+	current, err := avroCodec.Encode(&model.Order{OrderUID: "order-2", Locale: "ru", Status: model.OrderStatusPaid})
+	require.NoError(t, err)
+	order = model.Order{}
+	version, err = avroCodec.Decode(current, 0, &order)
+	require.NoError(t, err)
+	assert.Equal(t, CurrentSchemaVersion, version)
+	assert.Equal(t, model.Order{OrderUID: "order-2", Locale: "ru", Status: model.OrderStatusPaid}, order)
+
+	_, err = avroCodec.Decode(current, 1, &order)
+	require.Error(t, err, "the header contradicts the writer schema")
+}
+
+func TestAvro_RejectsUnknownVersions(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeSchema(t, dir, "order.v1.avsc", v2Fields)
+	// A schema added to the directory before the service supports it.
+	writeSchema(t, dir, "order.v2.avsc", v3Fields)
+	registry, err := LoadSchemaRegistry(dir)
+	require.NoError(t, err)
+	avroCodec, err := NewAvro(registry)
+	require.NoError(t, err)
+
+	unsupported, err := registry.Version(OrderSubject, 2)
+	require.NoError(t, err)
+	unregistered := avro.MustParse(`{"type":"record","name":"Order","fields":[{"name":"track_number","type":"string"}]}`)
+
+	var order model.Order
+	_, err = avroCodec.Decode(encodeWith(t, unsupported.Schema, &model.Order{OrderUID: "order-1"}), 0, &order)
+	require.ErrorIs(t, err, ErrUnsupportedSchemaVersion)
+	_, err = avroCodec.Decode(encodeWith(t, unregistered, &model.Order{TrackNumber: "WB"}), 0, &order)
+	require.ErrorIs(t, err, ErrUnsupportedSchemaVersion)
+	_, err = avroCodec.Decode([]byte(`{"order_uid":"json"}`), 0, &order)
+	require.Error(t, err, "payload without the avro header")
+	assert.NotErrorIs(t, err, ErrUnsupportedSchemaVersion)
+}
+
+func TestLoadSchemaRegistry_Errors(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid schema", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "order.v1.avsc"), []byte(`{"type":"record"`), 0o600))
+		_, err := LoadSchemaRegistry(dir)
+		assert.ErrorContains(t, err, "failed to parse schema")
+	})
+
+	t.Run("duplicate version", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		writeSchema(t, dir, "order.v1.avsc", v1Fields)
+		writeSchema(t, dir, "order.v01.avsc", v2Fields)
+		_, err := LoadSchemaRegistry(dir)
+		assert.ErrorContains(t, err, "defined twice")
+	})
+
+	t.Run("no current order schema", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		writeSchema(t, dir, "order.v2.avsc", v2Fields)
+		registry, err := LoadSchemaRegistry(dir)
+		require.NoError(t, err)
+		_, err = NewAvro(registry)
+		assert.ErrorIs(t, err, ErrUnknownSchema)
+	})
+}
@@ -0,0 +1,87 @@
+package codec
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"l0/internal/domain/model"
+)
+
+// CurrentSchemaVersion is the order schema the service reads into
+// model.Order and writes. Version history:
+//
+//  1. The original order, without a status. JSON payloads that name no
+//     version are of this version.
+//  2. Adds status. Protobuf and Avro payloads start at this version. The
+//     status of a new order is owned by the service, which stores it as
+//     created whatever the payload says.
+const CurrentSchemaVersion = 2
+
+// legacySchemaVersion is assumed for JSON payloads that name no version,
+// which were published before versions were introduced.
+const legacySchemaVersion = 1
+
+// Upcaster rewrites a decoded order document of one schema version into the
+// next one. Documents have the shape of the JSON order.
+type Upcaster func(doc map[string]any) error
+
+// upcasters[v] turns a version v document into version v+1. Every version
+// below CurrentSchemaVersion needs one.
+var upcasters = map[int]Upcaster{
+	1: upcastV1,
+}
+
+// upcastV1 gives orders that predate statuses the status every new order
+// starts in. It only makes the decoded order a complete version 2 one:
+// SaveOrderUseCase sets that status anyway, so stored orders do not change.
+func upcastV1(doc map[string]any) error {
+	doc["status"] = string(model.OrderStatusCreated)
+	return nil
+}
+
+func checkVersion(version int) error {
+	if version < 1 || version > CurrentSchemaVersion {
+		return fmt.Errorf("%w: %d, want 1 to %d", ErrUnsupportedSchemaVersion, version, CurrentSchemaVersion)
+	}
+	return nil
+}
+
+// resolveVersion picks the version of a payload from the one the message
+// names and the one found in the payload itself, both 0 if absent. When the
+// two disagree, the payload's version cannot be told, so it is unsupported.
+func resolveVersion(named, inPayload, fallback int) (int, error) {
+	if named != 0 && inPayload != 0 && named != inPayload {
+		return 0, fmt.Errorf("%w: version %d of the payload conflicts with version %d of the message", ErrUnsupportedSchemaVersion, inPayload, named)
+	}
+	version := fallback
+	switch {
+	case named != 0:
+		version = named
+	case inPayload != 0:
+		version = inPayload
+	}
+	if err := checkVersion(version); err != nil {
+		return 0, err
+	}
+	return version, nil
+}
+
+// upcast runs doc of schema version from through the upcaster chain and
+// reads the result into order.
+func upcast(doc map[string]any, from int, order *model.Order) error {
+	for v := from; v < CurrentSchemaVersion; v++ {
+		up, ok := upcasters[v]
+		if !ok {
+			return fmt.Errorf("%w: no upcaster from version %d", ErrUnsupportedSchemaVersion, v)
+		}
+		if err := up(doc); err != nil {
+			return fmt.Errorf("failed to upcast order from version %d: %w", v, err)
+		}
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("failed to encode upcast order: %w", err)
+	}
+	return json.Unmarshal(data, order)
+}
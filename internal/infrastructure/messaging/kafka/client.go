@@ -0,0 +1,171 @@
+package kafka
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"time"
+
+	"l0/internal/infrastructure/config"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/segmentio/kafka-go/sasl/scram"
+)
+
+const (
+	SASLNone        = "none"
+	SASLPlain       = "plain"
+	SASLScramSHA256 = "scram-sha-256"
+	SASLScramSHA512 = "scram-sha-512"
+)
+
+const (
+	StartOffsetFirst = "first"
+	StartOffsetLast  = "last"
+)
+
+// dialTimeout matches kafka-go's default dialer.
+const dialTimeout = 10 * time.Second
+
+// ClientOptions are the connection settings shared by every reader and
+// writer: the brokers to bootstrap from and how to authenticate to them.
+type ClientOptions struct {
+	Brokers  []string
+	ClientID string
+	TLS      *tls.Config
+	SASL     sasl.Mechanism
+}
+
+// NewClientOptions builds the connection settings from cfg, loading the TLS
+// certificates it names.
+func NewClientOptions(cfg config.KafkaConfig) (ClientOptions, error) {
+	if len(cfg.Brokers) == 0 {
+		return ClientOptions{}, fmt.Errorf("no Kafka brokers configured")
+	}
+	mechanism, err := newSASLMechanism(cfg.Security)
+	if err != nil {
+		return ClientOptions{}, err
+	}
+	tlsConfig, err := newTLSConfig(cfg.Security)
+	if err != nil {
+		return ClientOptions{}, err
+	}
+	return ClientOptions{
+		Brokers:  cfg.Brokers,
+		ClientID: cfg.ClientID,
+		TLS:      tlsConfig,
+		SASL:     mechanism,
+	}, nil
+}
+
+// dialer connects readers, which dial the brokers themselves.
+func (o ClientOptions) dialer() *kafka.Dialer {
+	return &kafka.Dialer{
+		Timeout:       dialTimeout,
+		DualStack:     true,
+		ClientID:      o.ClientID,
+		TLS:           o.TLS,
+		SASLMechanism: o.SASL,
+	}
+}
+
+// transport connects writers. Each writer gets its own, so closing a writer
+// can close the transport's idle connections.
+func (o ClientOptions) transport() *kafka.Transport {
+	return &kafka.Transport{
+		DialTimeout: dialTimeout,
+		ClientID:    o.ClientID,
+		TLS:         o.TLS,
+		SASL:        o.SASL,
+	}
+}
+
+func newSASLMechanism(cfg config.KafkaSecurityConfig) (sasl.Mechanism, error) {
+	switch cfg.SASLMechanism {
+	case SASLNone, "":
+		return nil, nil
+	case SASLPlain:
+		return plain.Mechanism{Username: cfg.SASLUsername, Password: cfg.SASLPassword}, nil
+	case SASLScramSHA256, SASLScramSHA512:
+		algo := scram.SHA256
+		if cfg.SASLMechanism == SASLScramSHA512 {
+			algo = scram.SHA512
+		}
+		mechanism, err := scram.Mechanism(algo, cfg.SASLUsername, cfg.SASLPassword)
+		if err != nil {
+			return nil, fmt.Errorf("failed to set up %s: %w", cfg.SASLMechanism, err)
+		}
+		return mechanism, nil
+	default:
+		return nil, fmt.Errorf("unknown SASL mechanism %q, want none, plain, scram-sha-256 or scram-sha-512", cfg.SASLMechanism)
+	}
+}
+
+// newTLSConfig returns nil if TLS is disabled.
+func newTLSConfig(cfg config.KafkaSecurityConfig) (*tls.Config, error) {
+	if !cfg.TLSEnabled {
+		return nil, nil
+	}
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: cfg.TLSInsecureSkipVerify,
+	}
+
+	if cfg.TLSCAFile != "" {
+		pem, err := os.ReadFile(cfg.TLSCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read Kafka CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in Kafka CA file %s", cfg.TLSCAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.TLSCertFile != "" || cfg.TLSKeyFile != "" {
+		if cfg.TLSCertFile == "" || cfg.TLSKeyFile == "" {
+			return nil, fmt.Errorf("kafka client certificate needs both a cert and a key file")
+		}
+		cert, err := tls.LoadX509KeyPair(cfg.TLSCertFile, cfg.TLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load Kafka client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// ReaderOptions tune how a Source fetches. StartOffset is a kafka-go offset:
+// kafka.FirstOffset or kafka.LastOffset.
+type ReaderOptions struct {
+	MinBytes       int
+	MaxBytes       int
+	MaxWait        time.Duration
+	StartOffset    int64
+	SessionTimeout time.Duration
+}
+
+func NewReaderOptions(cfg config.KafkaReaderConfig) (ReaderOptions, error) {
+	opts := ReaderOptions{
+		MinBytes:       cfg.MinBytes,
+		MaxBytes:       cfg.MaxBytes,
+		MaxWait:        cfg.MaxWait,
+		SessionTimeout: cfg.SessionTimeout,
+	}
+	switch cfg.StartOffset {
+	case StartOffsetFirst, "":
+		opts.StartOffset = kafka.FirstOffset
+	case StartOffsetLast:
+		opts.StartOffset = kafka.LastOffset
+	default:
+		return ReaderOptions{}, fmt.Errorf("unknown start offset %q, want first or last", cfg.StartOffset)
+	}
+	if opts.MinBytes > opts.MaxBytes {
+		return ReaderOptions{}, fmt.Errorf("reader min bytes %d exceed max bytes %d", opts.MinBytes, opts.MaxBytes)
+	}
+	return opts, nil
+}
@@ -0,0 +1,165 @@
+package kafka
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"l0/internal/infrastructure/config"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestCert writes a self-signed certificate and its key to dir.
+func writeTestCert(t *testing.T, dir string) (certFile, keyFile string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "kafka"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return certFile, keyFile
+}
+
+func TestNewClientOptions(t *testing.T) {
+	t.Parallel()
+
+	certFile, keyFile := writeTestCert(t, t.TempDir())
+
+	tests := []struct {
+		name     string
+		security config.KafkaSecurityConfig
+		check    func(t *testing.T, opts ClientOptions)
+		wantErr  string
+	}{
+		{
+			name: "plaintext",
+			check: func(t *testing.T, opts ClientOptions) {
+				assert.Nil(t, opts.TLS)
+				assert.Nil(t, opts.SASL)
+			},
+		},
+		{
+			name:     "sasl plain",
+			security: config.KafkaSecurityConfig{SASLMechanism: SASLPlain, SASLUsername: "user", SASLPassword: "secret"},
+			check: func(t *testing.T, opts ClientOptions) {
+				assert.Equal(t, plain.Mechanism{Username: "user", Password: "secret"}, opts.SASL)
+			},
+		},
+		{
+			name:     "scram",
+			security: config.KafkaSecurityConfig{SASLMechanism: SASLScramSHA512, SASLUsername: "user", SASLPassword: "secret"},
+			check: func(t *testing.T, opts ClientOptions) {
+				require.NotNil(t, opts.SASL)
+				assert.Equal(t, "SCRAM-SHA-512", opts.SASL.Name())
+			},
+		},
+		{
+			name: "tls with ca and client certificate",
+			security: config.KafkaSecurityConfig{
+				TLSEnabled:  true,
+				TLSCAFile:   certFile,
+				TLSCertFile: certFile,
+				TLSKeyFile:  keyFile,
+			},
+			check: func(t *testing.T, opts ClientOptions) {
+				require.NotNil(t, opts.TLS)
+				assert.NotNil(t, opts.TLS.RootCAs)
+				assert.Len(t, opts.TLS.Certificates, 1)
+			},
+		},
+		{
+			name:     "unknown mechanism",
+			security: config.KafkaSecurityConfig{SASLMechanism: "gssapi"},
+			wantErr:  "unknown SASL mechanism",
+		},
+		{
+			name:     "missing ca file",
+			security: config.KafkaSecurityConfig{TLSEnabled: true, TLSCAFile: filepath.Join(t.TempDir(), "missing.pem")},
+			wantErr:  "failed to read Kafka CA file",
+		},
+		{
+			name:     "ca file without certificates",
+			security: config.KafkaSecurityConfig{TLSEnabled: true, TLSCAFile: keyFile},
+			wantErr:  "no certificates found",
+		},
+		{
+			name:     "cert without key",
+			security: config.KafkaSecurityConfig{TLSEnabled: true, TLSCertFile: certFile},
+			wantErr:  "needs both a cert and a key file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts, err := NewClientOptions(config.KafkaConfig{
+				Brokers:  []string{"kafka-1:9093", "kafka-2:9093"},
+				ClientID: "l0-test",
+				Security: tt.security,
+			})
+
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []string{"kafka-1:9093", "kafka-2:9093"}, opts.Brokers)
+			assert.Equal(t, "l0-test", opts.dialer().ClientID)
+			assert.Equal(t, "l0-test", opts.transport().ClientID)
+			tt.check(t, opts)
+		})
+	}
+}
+
+func TestNewClientOptions_NoBrokers(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewClientOptions(config.KafkaConfig{})
+	assert.Error(t, err)
+}
+
+func TestNewReaderOptions(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.KafkaReaderConfig{MinBytes: 1, MaxBytes: 100, MaxWait: time.Second, StartOffset: StartOffsetLast, SessionTimeout: 10 * time.Second}
+	opts, err := NewReaderOptions(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, ReaderOptions{MinBytes: 1, MaxBytes: 100, MaxWait: time.Second, StartOffset: kafka.LastOffset, SessionTimeout: 10 * time.Second}, opts)
+
+	cfg.StartOffset = "middle"
+	_, err = NewReaderOptions(cfg)
+	require.Error(t, err)
+
+	cfg.StartOffset = StartOffsetFirst
+	cfg.MinBytes = 1000
+	_, err = NewReaderOptions(cfg)
+	assert.Error(t, err)
+}
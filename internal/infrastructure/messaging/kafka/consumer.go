@@ -2,75 +2,217 @@ package kafka
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
+	"hash/fnv"
+	"strconv"
 	"sync"
 	"time"
 
-	"l0/internal/application/usecases"
 	"l0/internal/domain/model"
+	"l0/internal/domain/repository"
+	"l0/internal/infrastructure/metrics"
 
-	"github.com/segmentio/kafka-go"
 	"go.uber.org/zap"
 )
 
-func ConsumeOrders(ctx context.Context, wg *sync.WaitGroup, broker, topic, groupID string, saveOrderUC *usecases.SaveOrderUseCase, logger *zap.Logger) {
+const commitTimeout = 5 * time.Second
+
+// Processor handles a single message and reports whether its offset may be
+// committed. Processors keep trying to retry or dead-letter a failed message,
+// so false means processing was interrupted by shutdown: the offset tracker
+// holds back the partition's commits until the message is done, and a
+// message that is never done must be redelivered after a restart.
+type Processor interface {
+	Process(ctx context.Context, msg model.Message) bool
+}
+
+// BatchProcessor is implemented by processors that can handle several messages
+// at once.
+type BatchProcessor interface {
+	Processor
+	ProcessBatch(ctx context.Context, batch []model.Message) []bool
+}
+
+// BatchOptions controls how many messages a worker collects before saving them
+// together. A Size of 1 or less, or a processor that does not implement
+// BatchProcessor, processes messages one at a time.
+type BatchOptions struct {
+	Size    int
+	Timeout time.Duration
+}
+
+// Consume reads topic from source with the given number of workers and
+// closes source when ctx is cancelled. Messages with the same key (order_uid)
+// always go to the same worker, so per-order ordering is preserved, and
+// offsets are committed only up to the highest contiguous processed offset of
+// each partition. A message that is not yet due for retry holds up the fetch
+// of source rather than a worker: every message of a retry topic waits the
+// same delay, so those after it are not due earlier, while messages already
+// dispatched keep being processed and committed. A source that joins a Kafka
+// consumer group reports the join to membership, which may be nil.
+func Consume(ctx context.Context, wg *sync.WaitGroup, source repository.MessageSource, committer repository.Committer, topic string, workers int, batch BatchOptions, processor Processor, membership *Membership, logger *zap.Logger) {
 	defer wg.Done()
-	reader := kafka.NewReader(kafka.ReaderConfig{
-		Brokers:  []string{broker},
-		Topic:    topic,
-		GroupID:  groupID,
-		MinBytes: 10e3,
-		MaxBytes: 10e6,
-		MaxWait:  1 * time.Second,
-	})
 	defer func() {
-		if err := reader.Close(); err != nil {
-			logger.Error("Failed to close Kafka reader", zap.Error(err))
+		if err := source.Close(); err != nil {
+			logger.Error("Failed to close message source", zap.Error(err), zap.String("topic", topic))
 		}
 	}()
 
-	logger.Info("Starting Kafka consumer", zap.String("topic", topic), zap.String("groupID", groupID))
+	if member, ok := source.(groupMember); ok {
+		go membership.watchJoin(ctx, member, topic)
+	}
+
+	workers = max(workers, 1)
+	batchProcessor, canBatch := processor.(BatchProcessor)
+	if !canBatch {
+		batch.Size = 1
+	}
+	logger.Info("Starting consumer", zap.String("topic", topic),
+		zap.Int("workers", workers), zap.Int("batch_size", batch.Size))
+
+	tracker := newOffsetTracker()
+	processed := make(chan model.Message, workers)
+	committerDone := make(chan struct{})
+	go func() {
+		defer close(committerDone)
+		commitProcessed(ctx, committer, tracker, processed, logger)
+	}()
+
+	queues := make([]chan model.Message, workers)
+	workersWG := &sync.WaitGroup{}
+	for i := range queues {
+		queues[i] = make(chan model.Message, 1)
+		workersWG.Add(1)
+		go func(queue <-chan model.Message) {
+			defer workersWG.Done()
+			if batch.Size > 1 {
+				runBatchWorker(ctx, queue, batch, batchProcessor, processed)
+				return
+			}
+			for msg := range queue {
+				if processor.Process(ctx, msg) {
+					processed <- msg
+				}
+			}
+		}(queues[i])
+	}
+
+	defer func() {
+		for _, queue := range queues {
+			close(queue)
+		}
+		workersWG.Wait()
+		close(processed)
+		<-committerDone
+	}()
 
 	for {
-		msg, err := reader.FetchMessage(ctx)
+		msg, err := source.Fetch(ctx)
 		if err != nil {
-			if errors.Is(err, context.Canceled) {
-				logger.Info("Kafka consumer context canceled, stopping...")
+			if errors.Is(err, context.Canceled) || ctx.Err() != nil {
+				logger.Info("Consumer context canceled, stopping...", zap.String("topic", topic))
 				return
 			}
-			logger.Error("Failed to fetch message from Kafka", zap.Error(err))
+			logger.Error("Failed to fetch message", zap.Error(err), zap.String("topic", topic))
 			continue
 		}
 
-		var order model.Order
-		if err := json.Unmarshal(msg.Value, &order); err != nil {
-			logger.Error("Failed to unmarshal order", zap.Error(err), zap.String("message", string(msg.Value)))
-			if err := reader.CommitMessages(ctx, msg); err != nil {
-				logger.Error("Failed to commit message", zap.Error(err), zap.String("order_uid", order.OrderUID))
-			}
-			continue
+		membership.joined(topic)
+		if err := waitUntilDue(ctx, msg); err != nil {
+			logger.Info("Consumer context canceled while waiting for retry delay, stopping...", zap.String("topic", topic))
+			return
+		}
+		observeFetched(msg)
+		msg, span := traceReceived(ctx, msg)
+		tracker.track(msg.Partition, msg.Offset)
+		select {
+		case queues[workerFor(msg, workers)] <- msg:
+			span.End()
+		case <-ctx.Done():
+			span.End()
+			logger.Info("Consumer context canceled, stopping...", zap.String("topic", topic))
+			return
 		}
+	}
+}
+
+// runBatchWorker collects messages until the batch is full or its timeout
+// expires, whichever comes first, and processes them together.
+func runBatchWorker(ctx context.Context, queue <-chan model.Message, opts BatchOptions, processor BatchProcessor, processed chan<- model.Message) {
+	batch := make([]model.Message, 0, opts.Size)
+	timer := time.NewTimer(opts.Timeout)
+	timer.Stop()
 
-		shouldCommit := true
-		if err := saveOrderUC.Execute(ctx, &order); err != nil {
-			switch {
-			case errors.Is(err, model.ErrOrderAlreadyExists):
-				logger.Info("Order already exists, skipping", zap.String("order_uid", order.OrderUID))
-			case errors.Is(err, model.ErrInvalidOrderData):
-				logger.Info("Invalid order data, skipping", zap.String("order_uid", order.OrderUID))
-			default:
-				logger.Error("Failed to save order", zap.Error(err), zap.String("order_uid", order.OrderUID))
-				shouldCommit = false
+	flush := func() {
+		for i, ok := range processor.ProcessBatch(ctx, batch) {
+			if ok {
+				processed <- batch[i]
 			}
 		}
+		batch = batch[:0]
+	}
 
-		if shouldCommit {
-			if err := reader.CommitMessages(ctx, msg); err != nil {
-				logger.Error("Failed to commit message", zap.Error(err), zap.String("order_uid", order.OrderUID))
-			} else {
-				logger.Info("Order processed from Kafka", zap.String("order_uid", order.OrderUID))
+	for {
+		select {
+		case msg, open := <-queue:
+			if !open {
+				timer.Stop()
+				if len(batch) > 0 {
+					flush()
+				}
+				return
+			}
+			batch = append(batch, msg)
+			if len(batch) == 1 {
+				timer.Reset(opts.Timeout)
+			}
+			if len(batch) >= opts.Size {
+				timer.Stop()
+				flush()
+			}
+		case <-timer.C:
+			if len(batch) > 0 {
+				flush()
 			}
 		}
 	}
 }
+
+func commitProcessed(ctx context.Context, committer repository.Committer, tracker *offsetTracker, processed <-chan model.Message, logger *zap.Logger) {
+	commitCtx := context.WithoutCancel(ctx)
+	for msg := range processed {
+		offset, n := tracker.complete(msg.Partition, msg.Offset)
+		if n == 0 {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(commitCtx, commitTimeout)
+		err := committer.Commit(ctx, model.Message{Topic: msg.Topic, Partition: msg.Partition, Offset: offset})
+		cancel()
+		if err != nil {
+			logger.Error("Failed to commit offset", zap.Error(err), zap.Int("partition", msg.Partition), zap.Int64("offset", offset))
+			continue
+		}
+		metrics.KafkaMessagesCommitted.WithLabelValues(msg.Topic).Add(float64(n))
+	}
+}
+
+// observeFetched counts the message and records the partition lag it reveals.
+// HighWaterMark is the offset the next produced message will get, so the
+// messages still to be read after this one are HighWaterMark - Offset - 1.
+func observeFetched(msg model.Message) {
+	metrics.KafkaMessagesConsumed.WithLabelValues(msg.Topic).Inc()
+	metrics.KafkaConsumerLag.
+		WithLabelValues(msg.Topic, strconv.Itoa(msg.Partition)).
+		Set(float64(max(msg.HighWaterMark-msg.Offset-1, 0)))
+}
+
+func workerFor(msg model.Message, workers int) int {
+	h := fnv.New32a()
+	if len(msg.Key) > 0 {
+		_, _ = h.Write(msg.Key)
+	} else {
+		_, _ = h.Write([]byte{byte(msg.Partition >> 8), byte(msg.Partition)})
+	}
+	return int(h.Sum32() % uint32(workers))
+}
@@ -0,0 +1,317 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"l0/internal/domain/model"
+	"l0/internal/domain/repository"
+	"l0/internal/domain/repository/mocks"
+	"l0/internal/infrastructure/messaging/codec"
+	"l0/internal/infrastructure/messaging/memory"
+	"l0/internal/infrastructure/metrics"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+const (
+	testTopic = "orders"
+	testGroup = "orders-group"
+	testDLQ   = "orders_dlq"
+)
+
+func newTestOrderProcessor(publisher repository.MessagePublisher, saver repository.OrderSaver) *OrderProcessor {
+	logger := zap.NewNop()
+	dlq := NewDeadLetterPublisher(publisher, testDLQ, logger)
+	retrier := NewRetryPublisher(publisher, RetryTopics(testTopic, 1), time.Millisecond, dlq, logger)
+	return NewOrderProcessor(saver, codec.NewRegistry(codec.NewJSON(), codec.NewProtobuf()), retrier, dlq, logger)
+}
+
+// runConsumer consumes testTopic from broker until the returned stop is
+// called or the test ends. stop returns once every commit has been made.
+func runConsumer(t *testing.T, broker *memory.Broker, processor Processor) (stop func()) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	wg := &sync.WaitGroup{}
+	sub := broker.Subscribe(testTopic, testGroup)
+	wg.Add(1)
+	go Consume(ctx, wg, sub, sub, testTopic, 2, BatchOptions{}, processor, nil, zap.NewNop())
+
+	var once sync.Once
+	stop = func() {
+		once.Do(func() {
+			cancel()
+			wg.Wait()
+		})
+	}
+	t.Cleanup(stop)
+	return stop
+}
+
+func publishOrders(t *testing.T, broker *memory.Broker, values ...string) {
+	t.Helper()
+	for i, value := range values {
+		err := broker.Publish(context.Background(), model.Message{
+			Topic: testTopic,
+			Key:   fmt.Appendf(nil, "order-%d", i),
+			Value: []byte(value),
+		})
+		require.NoError(t, err)
+	}
+}
+
+func committed(broker *memory.Broker) int64 {
+	return broker.Committed(testTopic, testGroup, 0)
+}
+
+func TestConsume_CommitsProcessedMessages(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	saver := mocks.NewMockOrderSaver(ctrl)
+	broker := memory.NewBroker(1)
+
+	saver.EXPECT().Execute(gomock.Any(), gomock.Any()).Return(nil)
+	saver.EXPECT().Execute(gomock.Any(), gomock.Any()).Return(model.ErrOrderAlreadyExists)
+
+	publishOrders(t, broker, `{"order_uid":"order-0"}`, `{"order_uid":"order-1"}`)
+	runConsumer(t, broker, newTestOrderProcessor(broker, saver))
+
+	require.Eventually(t, func() bool { return committed(broker) == 2 }, time.Second, 5*time.Millisecond)
+	assert.Empty(t, broker.Messages(testDLQ))
+}
+
+func TestConsume_InvalidDataIsDeadLetteredAndCommitted(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	saver := mocks.NewMockOrderSaver(ctrl)
+	broker := memory.NewBroker(1)
+
+	saver.EXPECT().Execute(gomock.Any(), gomock.Any()).
+		Return(fmt.Errorf("%w: missing items", model.ErrInvalidOrderData))
+
+	publishOrders(t, broker, `{"order_uid": "broken`, `{"order_uid":"order-1"}`)
+	runConsumer(t, broker, newTestOrderProcessor(broker, saver))
+
+	require.Eventually(t, func() bool { return committed(broker) == 2 }, time.Second, 5*time.Millisecond)
+	dead := broker.Messages(testDLQ)
+	require.Len(t, dead, 2)
+	reasons := []string{}
+	for _, msg := range dead {
+		reason, _ := headerValue(msg.Headers, HeaderDLQReason)
+		reasons = append(reasons, reason)
+	}
+	assert.ElementsMatch(t, []string{ReasonUnmarshalFailed, ReasonInvalidOrder}, reasons)
+}
+
+func TestConsume_DecodesByContentType(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	saver := mocks.NewMockOrderSaver(ctrl)
+	broker := memory.NewBroker(1)
+
+	payload, err := codec.NewProtobuf().Encode(&model.Order{OrderUID: "order-0", Locale: "ru"})
+	require.NoError(t, err)
+	saver.EXPECT().Execute(gomock.Any(), &model.Order{OrderUID: "order-0", Locale: "ru"}).Return(nil)
+
+	require.NoError(t, broker.Publish(context.Background(),
+		model.Message{
+			Topic:   testTopic,
+			Key:     []byte("order-0"),
+			Value:   payload,
+			Headers: []model.MessageHeader{{Key: HeaderContentType, Value: []byte(codec.ContentTypeProtobuf)}},
+		},
+		model.Message{
+			Topic:   testTopic,
+			Key:     []byte("order-1"),
+			Value:   []byte("<order/>"),
+			Headers: []model.MessageHeader{{Key: HeaderContentType, Value: []byte("application/xml")}},
+		},
+	))
+	runConsumer(t, broker, newTestOrderProcessor(broker, saver))
+
+	require.Eventually(t, func() bool { return committed(broker) == 2 }, time.Second, 5*time.Millisecond)
+	dead := broker.Messages(testDLQ)
+	require.Len(t, dead, 1)
+	reason, _ := headerValue(dead[0].Headers, HeaderDLQReason)
+	assert.Equal(t, ReasonUnsupportedContentType, reason)
+}
+
+func TestConsume_RejectsUnsupportedSchemaVersions(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	saver := mocks.NewMockOrderSaver(ctrl)
+	broker := memory.NewBroker(1)
+	unsupported := metrics.KafkaOrderSchemaVersions.WithLabelValues(testTopic, metrics.SchemaVersionUnsupported)
+	before := testutil.ToFloat64(unsupported)
+
+	saver.EXPECT().Execute(gomock.Any(), &model.Order{OrderUID: "order-0", Status: model.OrderStatusCreated}).Return(nil)
+
+	versioned := func(key, version, value string) model.Message {
+		return model.Message{
+			Topic:   testTopic,
+			Key:     []byte(key),
+			Value:   []byte(value),
+			Headers: []model.MessageHeader{{Key: HeaderSchemaVersion, Value: []byte(version)}},
+		}
+	}
+	require.NoError(t, broker.Publish(context.Background(),
+		versioned("order-0", "1", `{"order_uid":"order-0"}`),
+		versioned("order-1", "99", `{"order_uid":"order-1"}`),
+		versioned("order-2", "v2", `{"order_uid":"order-2"}`),
+	))
+	runConsumer(t, broker, newTestOrderProcessor(broker, saver))
+
+	require.Eventually(t, func() bool { return committed(broker) == 3 }, time.Second, 5*time.Millisecond)
+	dead := broker.Messages(testDLQ)
+	require.Len(t, dead, 2)
+	for _, msg := range dead {
+		reason, _ := headerValue(msg.Headers, HeaderDLQReason)
+		assert.Equal(t, ReasonUnsupportedSchemaVersion, reason, string(msg.Key))
+	}
+	assert.InDelta(t, 2, testutil.ToFloat64(unsupported)-before, 0)
+}
+
+func TestConsume_TransientErrorIsRetriedAndCommitted(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	saver := mocks.NewMockOrderSaver(ctrl)
+	broker := memory.NewBroker(1)
+
+	saver.EXPECT().Execute(gomock.Any(), gomock.Any()).Return(errors.New("db connection lost"))
+
+	publishOrders(t, broker, `{"order_uid":"order-0"}`)
+	runConsumer(t, broker, newTestOrderProcessor(broker, saver))
+
+	require.Eventually(t, func() bool { return committed(broker) == 1 }, time.Second, 5*time.Millisecond)
+	retries := broker.Messages(RetryTopics(testTopic, 1)[0])
+	require.Len(t, retries, 1)
+	attempt, _ := headerValue(retries[0].Headers, HeaderRetryAttempt)
+	assert.Equal(t, "1", attempt)
+}
+
+func TestConsume_WaitsForRetryDelayWithoutHoldingBackEarlierMessages(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	saver := mocks.NewMockOrderSaver(ctrl)
+	broker := memory.NewBroker(1)
+
+	saved := make(chan time.Time, 2)
+	saver.EXPECT().Execute(gomock.Any(), gomock.Any()).DoAndReturn(func(context.Context, *model.Order) error {
+		saved <- time.Now()
+		return nil
+	}).Times(2)
+
+	publishOrders(t, broker, `{"order_uid":"order-0"}`)
+	due := time.Now().Add(200 * time.Millisecond)
+	delayed := dueAt(due)
+	delayed.Topic, delayed.Key, delayed.Value = testTopic, []byte("order-1"), []byte(`{"order_uid":"order-1"}`)
+	require.NoError(t, broker.Publish(context.Background(), delayed))
+	runConsumer(t, broker, newTestOrderProcessor(broker, saver))
+
+	require.Eventually(t, func() bool { return committed(broker) == 1 }, time.Second, 5*time.Millisecond)
+	<-saved
+	require.Eventually(t, func() bool { return committed(broker) == 2 }, time.Second, 5*time.Millisecond)
+	// The header has millisecond precision.
+	assert.False(t, (<-saved).Before(due.Truncate(time.Millisecond)))
+}
+
+func TestConsume_RetriesFailedPublishBeforeCommitting(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	saver := mocks.NewMockOrderSaver(ctrl)
+	publisher := mocks.NewMockMessagePublisher(ctrl)
+	broker := memory.NewBroker(1)
+
+	saver.EXPECT().Execute(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, order *model.Order) error {
+			if order.OrderUID == "order-0" {
+				return errors.New("db connection lost")
+			}
+			return nil
+		}).Times(2)
+	// The retry topic is briefly unavailable.
+	gomock.InOrder(
+		publisher.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(errors.New("broker down")).Times(2),
+		publisher.EXPECT().Publish(gomock.Any(), gomock.Any()).DoAndReturn(broker.Publish),
+	)
+
+	publishOrders(t, broker, `{"order_uid":"order-0"}`, `{"order_uid":"order-1"}`)
+	runConsumer(t, broker, newTestOrderProcessor(publisher, saver))
+
+	require.Eventually(t, func() bool { return committed(broker) == 2 }, 5*time.Second, 5*time.Millisecond)
+	assert.Len(t, broker.Messages(RetryTopics(testTopic, 1)[0]), 1)
+}
+
+func TestConsume_ShutdownWhilePublishFailsLeavesMessageUncommitted(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	saver := mocks.NewMockOrderSaver(ctrl)
+	publisher := mocks.NewMockMessagePublisher(ctrl)
+	broker := memory.NewBroker(1)
+
+	handled := make(chan struct{}, 2)
+	saver.EXPECT().Execute(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, order *model.Order) error {
+			defer func() { handled <- struct{}{} }()
+			if order.OrderUID == "order-0" {
+				return errors.New("db connection lost")
+			}
+			return nil
+		}).Times(2)
+	// Neither the retry topic nor the DLQ can be written to until shutdown.
+	publisher.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(errors.New("broker down")).AnyTimes()
+
+	publishOrders(t, broker, `{"order_uid":"order-0"}`, `{"order_uid":"order-1"}`)
+	stop := runConsumer(t, broker, newTestOrderProcessor(publisher, saver))
+	<-handled
+	<-handled
+	stop()
+
+	assert.Zero(t, committed(broker), "order-1 is done, but committing it would skip order-0")
+
+	// After a restart both messages are delivered again.
+	saver.EXPECT().Execute(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	runConsumer(t, broker, newTestOrderProcessor(broker, saver))
+	require.Eventually(t, func() bool { return committed(broker) == 2 }, time.Second, 5*time.Millisecond)
+}
+
+func TestConsume_ShutdownLeavesInFlightMessageUncommitted(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	saver := mocks.NewMockOrderSaver(ctrl)
+	broker := memory.NewBroker(1)
+
+	started := make(chan struct{})
+	saver.EXPECT().Execute(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, _ *model.Order) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+	publishOrders(t, broker, `{"order_uid":"order-0"}`)
+	stop := runConsumer(t, broker, newTestOrderProcessor(broker, saver))
+	<-started
+	stop()
+
+	assert.Zero(t, committed(broker))
+	assert.Empty(t, broker.Messages(RetryTopics(testTopic, 1)[0]), "a shutdown is not a failure to retry")
+}
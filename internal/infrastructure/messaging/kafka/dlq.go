@@ -0,0 +1,78 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"l0/internal/domain/model"
+	"l0/internal/domain/repository"
+	"l0/internal/infrastructure/metrics"
+	"l0/internal/infrastructure/tracing"
+
+	"go.uber.org/zap"
+)
+
+const (
+	ReasonUnmarshalFailed          = "unmarshal_failed"
+	ReasonUnsupportedContentType   = "unsupported_content_type"
+	ReasonUnsupportedSchemaVersion = "unsupported_schema_version"
+	ReasonInvalidOrder             = "invalid_order"
+)
+
+// DeadLetterPublisher forwards messages the consumer cannot process to a
+// separate topic, keeping the original key and value so they can be replayed.
+type DeadLetterPublisher struct {
+	publisher repository.MessagePublisher
+	topic     string
+	logger    *zap.Logger
+}
+
+func NewDeadLetterPublisher(publisher repository.MessagePublisher, topic string, logger *zap.Logger) *DeadLetterPublisher {
+	return &DeadLetterPublisher{publisher: publisher, topic: topic, logger: logger}
+}
+
+// Publish retries the write until it succeeds, so it only fails once ctx is
+// cancelled.
+func (p *DeadLetterPublisher) Publish(ctx context.Context, msg model.Message, reason string, cause error) error {
+	headers := withHeader(tracing.Inject(ctx, msg.Headers), HeaderDLQReason, reason)
+	headers = withHeader(headers, HeaderSourceTopic, msg.Topic)
+	headers = withHeader(headers, HeaderSourcePartition, formatInt(int64(msg.Partition)))
+	headers = withHeader(headers, HeaderSourceOffset, formatInt(msg.Offset))
+	headers = withHeader(headers, HeaderSourceTimestamp, msg.Time.UTC().Format(time.RFC3339Nano))
+	headers = withHeader(headers, HeaderFailedAt, time.Now().UTC().Format(time.RFC3339Nano))
+	if cause != nil {
+		headers = withHeader(headers, HeaderDLQError, cause.Error())
+	}
+	if violations := validationViolations(cause); violations != "" {
+		headers = withHeader(headers, HeaderDLQValidationErrors, violations)
+	}
+
+	if err := publishUntilDone(ctx, p.publisher, model.Message{Topic: p.topic, Key: msg.Key, Value: msg.Value, Headers: headers}, p.logger); err != nil {
+		return fmt.Errorf("failed to write message to DLQ: %w", err)
+	}
+
+	metrics.KafkaMessagesRejected.WithLabelValues(msg.Topic, reason).Inc()
+	p.logger.Info("Message sent to DLQ",
+		zap.String("reason", reason),
+		zap.String("source_topic", msg.Topic),
+		zap.Int("partition", msg.Partition),
+		zap.Int64("offset", msg.Offset))
+	return nil
+}
+
+// validationViolations renders the field violations of a validation error as
+// a JSON array, the same shape the HTTP API returns, or "" for other errors.
+func validationViolations(err error) string {
+	var validationErr *model.ValidationError
+	if !errors.As(err, &validationErr) {
+		return ""
+	}
+	data, err := json.Marshal(validationErr.Violations)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
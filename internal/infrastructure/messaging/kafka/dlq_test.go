@@ -0,0 +1,203 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"l0/internal/domain/model"
+	"l0/internal/domain/repository/mocks"
+	"l0/internal/infrastructure/messaging/memory"
+	"l0/internal/infrastructure/metrics"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestValidationViolations(t *testing.T) {
+	t.Parallel()
+
+	validationErr := &model.ValidationError{Violations: []model.FieldViolation{
+		{Field: "items[0].price", Rule: "gt", Param: "0", Value: -1},
+		{Field: "order_uid", Rule: "required", Value: ""},
+	}}
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "validation error",
+			err:  validationErr,
+			want: `[{"field":"items[0].price","rule":"gt","param":"0","value":-1},{"field":"order_uid","rule":"required","value":""}]`,
+		},
+		{
+			name: "wrapped validation error",
+			err:  fmt.Errorf("save: %w", validationErr),
+			want: `[{"field":"items[0].price","rule":"gt","param":"0","value":-1},{"field":"order_uid","rule":"required","value":""}]`,
+		},
+		{
+			name: "other error",
+			err:  errors.New("boom"),
+		},
+		{
+			name: "nil",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := validationViolations(tt.err)
+			if tt.want == "" {
+				assert.Empty(t, got)
+				return
+			}
+			assert.JSONEq(t, tt.want, got)
+		})
+	}
+}
+
+func TestDeadLetterPublisher_Publish(t *testing.T) {
+	t.Parallel()
+
+	broker := memory.NewBroker(1)
+	dlq := NewDeadLetterPublisher(broker, testDLQ, zap.NewNop())
+	sourceTime := time.Date(2024, 5, 1, 12, 0, 0, 0, time.FixedZone("MSK", 3*60*60))
+	msg := model.Message{
+		Topic:     testTopic,
+		Partition: 3,
+		Offset:    42,
+		Key:       []byte("order-1"),
+		Value:     []byte(`{"order_uid":"order-1"}`),
+		Headers:   []model.MessageHeader{{Key: HeaderContentType, Value: []byte("application/json")}},
+		Time:      sourceTime,
+	}
+	cause := fmt.Errorf("save: %w", &model.ValidationError{Violations: []model.FieldViolation{
+		{Field: "order_uid", Rule: "required", Value: ""},
+	}})
+
+	require.NoError(t, dlq.Publish(context.Background(), msg, ReasonInvalidOrder, cause))
+
+	dead := broker.Messages(testDLQ)
+	require.Len(t, dead, 1)
+	assert.Equal(t, msg.Key, dead[0].Key)
+	assert.Equal(t, msg.Value, dead[0].Value)
+
+	header := func(key string) string {
+		t.Helper()
+		value, ok := headerValue(dead[0].Headers, key)
+		require.True(t, ok, "missing header %s", key)
+		return value
+	}
+	assert.Equal(t, "application/json", header(HeaderContentType), "original headers are kept")
+	assert.Equal(t, ReasonInvalidOrder, header(HeaderDLQReason))
+	assert.Equal(t, cause.Error(), header(HeaderDLQError))
+	assert.JSONEq(t, `[{"field":"order_uid","rule":"required","value":""}]`, header(HeaderDLQValidationErrors))
+	assert.Equal(t, testTopic, header(HeaderSourceTopic))
+	assert.Equal(t, "3", header(HeaderSourcePartition))
+	assert.Equal(t, "42", header(HeaderSourceOffset))
+	assert.Equal(t, "2024-05-01T09:00:00Z", header(HeaderSourceTimestamp))
+	failedAt, err := time.Parse(time.RFC3339Nano, header(HeaderFailedAt))
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), failedAt, time.Minute)
+}
+
+func TestDeadLetterPublisher_Publish_Reasons(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		reason    string
+		cause     error
+		wantError bool
+	}{
+		{reason: ReasonUnmarshalFailed, cause: errors.New("unexpected end of JSON input"), wantError: true},
+		{reason: ReasonInvalidOrder, cause: model.ErrInvalidOrderData, wantError: true},
+		{reason: ReasonRetriesExhausted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.reason, func(t *testing.T) {
+			t.Parallel()
+
+			broker := memory.NewBroker(1)
+			dlq := NewDeadLetterPublisher(broker, testDLQ, zap.NewNop())
+			rejected := metrics.KafkaMessagesRejected.WithLabelValues(testTopic, tt.reason)
+			before := testutil.ToFloat64(rejected)
+
+			require.NoError(t, dlq.Publish(context.Background(), model.Message{Topic: testTopic}, tt.reason, tt.cause))
+
+			dead := broker.Messages(testDLQ)
+			require.Len(t, dead, 1)
+			reason, _ := headerValue(dead[0].Headers, HeaderDLQReason)
+			assert.Equal(t, tt.reason, reason)
+			_, hasError := headerValue(dead[0].Headers, HeaderDLQError)
+			assert.Equal(t, tt.wantError, hasError)
+			_, hasViolations := headerValue(dead[0].Headers, HeaderDLQValidationErrors)
+			assert.False(t, hasViolations)
+			assert.GreaterOrEqual(t, testutil.ToFloat64(rejected)-before, 1.0)
+		})
+	}
+}
+
+func TestDeadLetterPublisher_Publish_RetriesFailedWrites(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	publisher := mocks.NewMockMessagePublisher(ctrl)
+	broker := memory.NewBroker(1)
+	dlq := NewDeadLetterPublisher(publisher, testDLQ, zap.NewNop())
+
+	gomock.InOrder(
+		publisher.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(errors.New("broker down")).Times(2),
+		publisher.EXPECT().Publish(gomock.Any(), gomock.Any()).DoAndReturn(broker.Publish),
+	)
+
+	require.NoError(t, dlq.Publish(context.Background(), model.Message{Topic: testTopic}, ReasonUnmarshalFailed, nil))
+	assert.Len(t, broker.Messages(testDLQ), 1)
+}
+
+func TestDeadLetterPublisher_Publish_FailsOnceCancelled(t *testing.T) {
+	t.Parallel()
+
+	broker := memory.NewBroker(1)
+	require.NoError(t, broker.Close())
+	dlq := NewDeadLetterPublisher(broker, testDLQ, zap.NewNop())
+	ctx, cancel := context.WithTimeout(context.Background(), 3*publishBackoff)
+	defer cancel()
+
+	err := dlq.Publish(ctx, model.Message{Topic: testTopic}, ReasonUnmarshalFailed, nil)
+
+	require.ErrorIs(t, err, memory.ErrClosed)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestWithHeader(t *testing.T) {
+	t.Parallel()
+
+	headers := []model.MessageHeader{
+		{Key: HeaderDLQReason, Value: []byte("old")},
+		{Key: HeaderContentType, Value: []byte("application/json")},
+	}
+
+	got := withHeader(headers, HeaderDLQReason, "new")
+
+	assert.Equal(t, []model.MessageHeader{
+		{Key: HeaderContentType, Value: []byte("application/json")},
+		{Key: HeaderDLQReason, Value: []byte("new")},
+	}, got)
+	assert.Equal(t, "old", string(headers[0].Value), "the input is not modified")
+
+	value, ok := headerValue(got, HeaderDLQReason)
+	assert.True(t, ok)
+	assert.Equal(t, "new", value)
+	_, ok = headerValue(got, HeaderRetryAttempt)
+	assert.False(t, ok)
+}
@@ -0,0 +1,47 @@
+package kafka
+
+import (
+	"strconv"
+
+	"l0/internal/domain/model"
+)
+
+const (
+	HeaderContentType         = "content-type"
+	HeaderSchemaVersion       = "schema_version"
+	HeaderDLQReason           = "x-dlq-reason"
+	HeaderDLQError            = "x-dlq-error"
+	HeaderDLQValidationErrors = "x-dlq-validation-errors"
+	HeaderSourceTopic         = "x-source-topic"
+	HeaderSourcePartition     = "x-source-partition"
+	HeaderSourceOffset        = "x-source-offset"
+	HeaderSourceTimestamp     = "x-source-timestamp"
+	HeaderFailedAt            = "x-failed-at"
+	HeaderRetryAttempt        = "x-retry-attempt"
+	HeaderRetryNotBefore      = "x-retry-not-before"
+	HeaderRetryError          = "x-retry-error"
+	HeaderRetryOriginalTopic  = "x-retry-original-topic"
+)
+
+func headerValue(headers []model.MessageHeader, key string) (string, bool) {
+	for i := len(headers) - 1; i >= 0; i-- {
+		if headers[i].Key == key {
+			return string(headers[i].Value), true
+		}
+	}
+	return "", false
+}
+
+func withHeader(headers []model.MessageHeader, key, value string) []model.MessageHeader {
+	out := make([]model.MessageHeader, 0, len(headers)+1)
+	for _, h := range headers {
+		if h.Key != key {
+			out = append(out, h)
+		}
+	}
+	return append(out, model.MessageHeader{Key: key, Value: []byte(value)})
+}
+
+func formatInt(v int64) string {
+	return strconv.FormatInt(v, 10)
+}
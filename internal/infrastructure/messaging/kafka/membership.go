@@ -0,0 +1,86 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const membershipPollInterval = time.Second
+
+// Membership tracks which topic consumers have joined their consumer group.
+// Its Check passes once every expected topic has joined at least once.
+type Membership struct {
+	mu      sync.Mutex
+	pending map[string]struct{}
+}
+
+// NewMembership expects a consumer for each of topics. Topics must be known
+// up front so readiness can't pass before a slow consumer registers.
+func NewMembership(topics ...string) *Membership {
+	pending := make(map[string]struct{}, len(topics))
+	for _, topic := range topics {
+		pending[topic] = struct{}{}
+	}
+	return &Membership{pending: pending}
+}
+
+func (m *Membership) joined(topic string) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	delete(m.pending, topic)
+	m.mu.Unlock()
+}
+
+func (m *Membership) isPending(topic string) bool {
+	if m == nil {
+		return false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.pending[topic]
+	return ok
+}
+
+func (m *Membership) Check(context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.pending) == 0 {
+		return nil
+	}
+	topics := make([]string, 0, len(m.pending))
+	for topic := range m.pending {
+		topics = append(topics, topic)
+	}
+	slices.Sort(topics)
+	return fmt.Errorf("consumer group not joined for topics: %s", strings.Join(topics, ", "))
+}
+
+// groupMember is a source that joins a consumer group.
+type groupMember interface {
+	joinedGroup() bool
+}
+
+// watchJoin marks topic as joined once member gets its first group
+// generation.
+func (m *Membership) watchJoin(ctx context.Context, member groupMember, topic string) {
+	ticker := time.NewTicker(membershipPollInterval)
+	defer ticker.Stop()
+
+	for m.isPending(topic) {
+		if member.joinedGroup() {
+			m.joined(topic)
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
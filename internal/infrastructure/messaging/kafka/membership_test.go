@@ -0,0 +1,22 @@
+package kafka
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMembership_Check(t *testing.T) {
+	t.Parallel()
+
+	m := NewMembership("orders", "orders_retry_1")
+	assert.EqualError(t, m.Check(context.Background()), "consumer group not joined for topics: orders, orders_retry_1")
+
+	m.joined("orders")
+	m.joined("orders")
+	assert.EqualError(t, m.Check(context.Background()), "consumer group not joined for topics: orders_retry_1")
+
+	m.joined("orders_retry_1")
+	assert.NoError(t, m.Check(context.Background()))
+}
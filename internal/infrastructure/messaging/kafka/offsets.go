@@ -0,0 +1,60 @@
+package kafka
+
+import "sync"
+
+// offsetTracker records which fetched offsets have been processed so that the
+// consumer never commits past a message that is still in flight.
+type offsetTracker struct {
+	mu         sync.Mutex
+	partitions map[int]*partitionOffsets
+}
+
+type partitionOffsets struct {
+	pending []int64
+	done    map[int64]struct{}
+}
+
+func newOffsetTracker() *offsetTracker {
+	return &offsetTracker{partitions: make(map[int]*partitionOffsets)}
+}
+
+// track registers a fetched offset. Offsets must be tracked in fetch order; an
+// offset at or below the last tracked one means the partition was rewound
+// (e.g. after a rebalance) and its previous state is discarded.
+func (t *offsetTracker) track(partition int, offset int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p, ok := t.partitions[partition]
+	if !ok || (len(p.pending) > 0 && offset <= p.pending[len(p.pending)-1]) {
+		p = &partitionOffsets{done: make(map[int64]struct{})}
+		t.partitions[partition] = p
+	}
+	p.pending = append(p.pending, offset)
+}
+
+// complete marks offset as processed and returns the highest offset up to which
+// every tracked message has been processed, along with how many messages that
+// moved past. n is zero when the committable offset did not move.
+func (t *offsetTracker) complete(partition int, offset int64) (committable int64, n int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p, exists := t.partitions[partition]
+	if !exists {
+		return 0, 0
+	}
+	p.done[offset] = struct{}{}
+
+	for len(p.pending) > 0 {
+		head := p.pending[0]
+		if _, processed := p.done[head]; !processed {
+			break
+		}
+		delete(p.done, head)
+		p.pending = p.pending[1:]
+		committable = head
+		n++
+	}
+	return committable, n
+}
@@ -0,0 +1,110 @@
+package kafka
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOffsetTracker_Complete(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		tracked    []int64
+		completed  []int64
+		wantOffset int64
+		wantOK     bool
+		wantCount  int
+	}{
+		{
+			name:       "in_order",
+			tracked:    []int64{10, 11, 12},
+			completed:  []int64{10, 11},
+			wantOffset: 11,
+			wantOK:     true,
+			wantCount:  2,
+		},
+		{
+			name:      "gap_blocks_commit",
+			tracked:   []int64{10, 11, 12},
+			completed: []int64{11, 12},
+			wantOK:    false,
+		},
+		{
+			name:       "gap_filled",
+			tracked:    []int64{10, 11, 12},
+			completed:  []int64{12, 11, 10},
+			wantOffset: 12,
+			wantOK:     true,
+			wantCount:  3,
+		},
+		{
+			name:       "non_sequential_offsets",
+			tracked:    []int64{3, 7, 20},
+			completed:  []int64{7, 3},
+			wantOffset: 7,
+			wantOK:     true,
+			wantCount:  2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tracker := newOffsetTracker()
+			for _, offset := range tt.tracked {
+				tracker.track(0, offset)
+			}
+
+			var (
+				offset int64
+				ok     bool
+				count  int
+			)
+			for _, o := range tt.completed {
+				if got, n := tracker.complete(0, o); n > 0 {
+					offset, ok = got, true
+					count += n
+				}
+			}
+
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantOffset, offset)
+			assert.Equal(t, tt.wantCount, count)
+		})
+	}
+}
+
+func TestOffsetTracker_PartitionsAreIndependent(t *testing.T) {
+	t.Parallel()
+
+	tracker := newOffsetTracker()
+	tracker.track(0, 1)
+	tracker.track(1, 1)
+	tracker.track(0, 2)
+
+	_, n := tracker.complete(0, 2)
+	assert.Zero(t, n)
+
+	offset, n := tracker.complete(1, 1)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, int64(1), offset)
+}
+
+func TestOffsetTracker_RewindResetsPartition(t *testing.T) {
+	t.Parallel()
+
+	tracker := newOffsetTracker()
+	tracker.track(0, 5)
+	tracker.track(0, 6)
+	tracker.track(0, 5)
+
+	offset, n := tracker.complete(0, 5)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, int64(5), offset)
+
+	_, n = tracker.complete(0, 6)
+	assert.Zero(t, n, "offset 6 was tracked before the rewind and must not be committed")
+}
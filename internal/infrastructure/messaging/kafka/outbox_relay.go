@@ -0,0 +1,113 @@
+package kafka
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"l0/internal/domain/model"
+	"l0/internal/domain/repository"
+
+	"go.uber.org/zap"
+)
+
+const (
+	HeaderEventID   = "x-event-id"
+	HeaderEventType = "x-event-type"
+)
+
+type OutboxRelayOptions struct {
+	BatchSize       int
+	PollInterval    time.Duration
+	Retention       time.Duration
+	CleanupInterval time.Duration
+}
+
+// OutboxRelay publishes pending outbox events to Kafka and marks them as
+// published. An event is marked only after Kafka acknowledged it, so delivery
+// is at-least-once: consumers should deduplicate by the x-event-id header.
+// Events are claimed before they are published, so several instances can run
+// a relay without publishing each other's events.
+type OutboxRelay struct {
+	repo      repository.OutboxRepository
+	publisher repository.MessagePublisher
+	topic     string
+	opts      OutboxRelayOptions
+	logger    *zap.Logger
+}
+
+// NewOutboxRelay publishes to topic through publisher, which the caller closes.
+func NewOutboxRelay(publisher repository.MessagePublisher, topic string, repo repository.OutboxRepository, opts OutboxRelayOptions, logger *zap.Logger) *OutboxRelay {
+	return &OutboxRelay{repo: repo, publisher: publisher, topic: topic, opts: opts, logger: logger}
+}
+
+func (r *OutboxRelay) Run(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	r.logger.Info("Starting outbox relay", zap.String("topic", r.topic), zap.Duration("poll_interval", r.opts.PollInterval))
+
+	pollTicker := time.NewTicker(r.opts.PollInterval)
+	defer pollTicker.Stop()
+	cleanupTicker := time.NewTicker(r.opts.CleanupInterval)
+	defer cleanupTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			r.logger.Info("Outbox relay context canceled, stopping...")
+			return
+		case <-pollTicker.C:
+			r.publishPending(ctx)
+		case <-cleanupTicker.C:
+			r.cleanup(ctx)
+		}
+	}
+}
+
+// publishPending drains the outbox in batches until it is empty or an error occurs.
+func (r *OutboxRelay) publishPending(ctx context.Context) {
+	for ctx.Err() == nil {
+		n, err := r.repo.PublishPending(ctx, r.opts.BatchSize, r.publish)
+		if err != nil {
+			r.logger.Error("Failed to publish outbox events", zap.Error(err))
+			return
+		}
+		if n == 0 {
+			return
+		}
+
+		r.logger.Info("Outbox events published", zap.Int("count", n))
+		if n < r.opts.BatchSize {
+			return
+		}
+	}
+}
+
+func (r *OutboxRelay) publish(ctx context.Context, events []model.OutboxEvent) error {
+	msgs := make([]model.Message, 0, len(events))
+	for _, event := range events {
+		msgs = append(msgs, model.Message{
+			Topic: r.topic,
+			Key:   []byte(event.AggregateID),
+			Value: event.Payload,
+			Time:  event.CreatedAt,
+			Headers: []model.MessageHeader{
+				{Key: HeaderEventID, Value: []byte(strconv.FormatInt(event.ID, 10))},
+				{Key: HeaderEventType, Value: []byte(event.EventType)},
+			},
+		})
+	}
+	return r.publisher.Publish(ctx, msgs...)
+}
+
+func (r *OutboxRelay) cleanup(ctx context.Context) {
+	deleted, err := r.repo.DeletePublishedBefore(ctx, time.Now().Add(-r.opts.Retention))
+	if err != nil {
+		r.logger.Error("Failed to clean up published outbox events", zap.Error(err))
+		return
+	}
+	if deleted > 0 {
+		r.logger.Info("Published outbox events cleaned up", zap.Int64("deleted", deleted))
+	}
+}
@@ -0,0 +1,160 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"l0/internal/domain/model"
+	"l0/internal/domain/repository/mocks"
+	"l0/internal/infrastructure/messaging/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+const testEventsTopic = "order_events"
+
+type publishFunc = func(ctx context.Context, events []model.OutboxEvent) error
+
+// claim stands in for PublishPending: it hands events to publish and reports
+// them as published if publish succeeds.
+func claim(events ...model.OutboxEvent) func(context.Context, int, publishFunc) (int, error) {
+	return func(ctx context.Context, _ int, publish publishFunc) (int, error) {
+		if len(events) == 0 {
+			return 0, nil
+		}
+		if err := publish(ctx, events); err != nil {
+			return 0, err
+		}
+		return len(events), nil
+	}
+}
+
+func outboxEvent(id int64, orderUID string) model.OutboxEvent {
+	return model.OutboxEvent{
+		ID:          id,
+		AggregateID: orderUID,
+		EventType:   model.EventOrderSaved,
+		Payload:     []byte(`{"order_uid":"` + orderUID + `"}`),
+		CreatedAt:   time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func newTestOutboxRelay(repo *mocks.MockOutboxRepository, publisher *memory.Broker, batchSize int) *OutboxRelay {
+	return NewOutboxRelay(publisher, testEventsTopic, repo, OutboxRelayOptions{
+		BatchSize:       batchSize,
+		PollInterval:    time.Millisecond,
+		Retention:       time.Hour,
+		CleanupInterval: time.Hour,
+	}, zap.NewNop())
+}
+
+func TestOutboxRelay_PublishPending(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockOutboxRepository(ctrl)
+	broker := memory.NewBroker(1)
+	relay := newTestOutboxRelay(repo, broker, 2)
+
+	// A full batch means there may be more, so the relay polls again until a
+	// batch comes back short.
+	gomock.InOrder(
+		repo.EXPECT().PublishPending(gomock.Any(), 2, gomock.Any()).DoAndReturn(claim(outboxEvent(1, "order-1"), outboxEvent(2, "order-2"))),
+		repo.EXPECT().PublishPending(gomock.Any(), 2, gomock.Any()).DoAndReturn(claim(outboxEvent(3, "order-3"))),
+	)
+
+	relay.publishPending(context.Background())
+
+	published := broker.Messages(testEventsTopic)
+	require.Len(t, published, 3)
+	first := published[0]
+	assert.Equal(t, []byte("order-1"), first.Key)
+	assert.JSONEq(t, `{"order_uid":"order-1"}`, string(first.Value))
+	assert.True(t, first.Time.Equal(outboxEvent(1, "order-1").CreatedAt))
+	eventID, _ := headerValue(first.Headers, HeaderEventID)
+	assert.Equal(t, "1", eventID)
+	eventType, _ := headerValue(first.Headers, HeaderEventType)
+	assert.Equal(t, model.EventOrderSaved, eventType)
+}
+
+func TestOutboxRelay_PublishPending_StopsOnError(t *testing.T) {
+	t.Parallel()
+
+	t.Run("publish fails", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		repo := mocks.NewMockOutboxRepository(ctrl)
+		broker := memory.NewBroker(1)
+		require.NoError(t, broker.Close())
+		relay := newTestOutboxRelay(repo, broker, 1)
+
+		// The error reaches the repository, which leaves the event pending.
+		repo.EXPECT().PublishPending(gomock.Any(), 1, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, limit int, publish publishFunc) (int, error) {
+				n, err := claim(outboxEvent(1, "order-1"))(ctx, limit, publish)
+				assert.ErrorIs(t, err, memory.ErrClosed)
+				return n, err
+			})
+
+		relay.publishPending(context.Background())
+	})
+
+	t.Run("claim fails", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		repo := mocks.NewMockOutboxRepository(ctrl)
+		broker := memory.NewBroker(1)
+		relay := newTestOutboxRelay(repo, broker, 1)
+
+		repo.EXPECT().PublishPending(gomock.Any(), 1, gomock.Any()).Return(0, errors.New("db down"))
+
+		relay.publishPending(context.Background())
+		assert.Empty(t, broker.Messages(testEventsTopic))
+	})
+}
+
+func TestOutboxRelay_Run(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockOutboxRepository(ctrl)
+	broker := memory.NewBroker(1)
+	relay := NewOutboxRelay(broker, testEventsTopic, repo, OutboxRelayOptions{
+		BatchSize:       10,
+		PollInterval:    time.Millisecond,
+		Retention:       time.Hour,
+		CleanupInterval: time.Millisecond,
+	}, zap.NewNop())
+
+	repo.EXPECT().PublishPending(gomock.Any(), 10, gomock.Any()).DoAndReturn(claim(outboxEvent(1, "order-1")))
+	repo.EXPECT().PublishPending(gomock.Any(), 10, gomock.Any()).DoAndReturn(claim()).AnyTimes()
+	cleanedUp := make(chan time.Time, 1)
+	repo.EXPECT().DeletePublishedBefore(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, before time.Time) (int64, error) {
+			select {
+			case cleanedUp <- before:
+			default:
+			}
+			return 1, nil
+		}).MinTimes(1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go relay.Run(ctx, wg)
+
+	before := <-cleanedUp
+	assert.WithinDuration(t, time.Now().Add(-time.Hour), before, time.Minute)
+	require.Eventually(t, func() bool { return len(broker.Messages(testEventsTopic)) == 1 }, time.Second, time.Millisecond)
+
+	cancel()
+	wg.Wait()
+}
@@ -0,0 +1,164 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"l0/internal/domain/model"
+	"l0/internal/domain/repository"
+	"l0/internal/infrastructure/messaging/codec"
+	"l0/internal/infrastructure/metrics"
+	"l0/internal/infrastructure/tracing"
+
+	"go.uber.org/zap"
+)
+
+// OrderProcessor decodes and saves a single order message. Rejected messages go
+// to the DLQ and transient failures are handed to the retry topics. The
+// decoder is picked by the message's content-type header, and orders of an
+// older schema_version are upcast to the current one.
+type OrderProcessor struct {
+	saveOrderUC repository.OrderSaver
+	codecs      *codec.Registry
+	retrier     *RetryPublisher
+	dlq         *DeadLetterPublisher
+	logger      *zap.Logger
+}
+
+func NewOrderProcessor(saveOrderUC repository.OrderSaver, codecs *codec.Registry, retrier *RetryPublisher, dlq *DeadLetterPublisher, logger *zap.Logger) *OrderProcessor {
+	return &OrderProcessor{saveOrderUC: saveOrderUC, codecs: codecs, retrier: retrier, dlq: dlq, logger: logger}
+}
+
+// Process reports whether msg has been fully handled and its offset may be committed.
+func (p *OrderProcessor) Process(ctx context.Context, msg model.Message) bool {
+	ctx, span := startProcessSpan(ctx, msg)
+	defer span.End()
+
+	order, err := p.decode(msg)
+	if err != nil {
+		span.RecordError(err)
+		return p.rejectUndecodable(ctx, msg, err)
+	}
+	span.SetAttributes(tracing.OrderUID(order.OrderUID))
+
+	err = p.saveOrderUC.Execute(ctx, order)
+	tracing.Record(span, err)
+	return p.handleResult(ctx, msg, order, err)
+}
+
+// ProcessBatch saves all decodable messages of batch with a single use case
+// call and reports, per message, whether its offset may be committed.
+func (p *OrderProcessor) ProcessBatch(ctx context.Context, batch []model.Message) []bool {
+	results := make([]bool, len(batch))
+	orders := make([]*model.Order, 0, len(batch))
+	msgs := make([]model.Message, 0, len(batch))
+	idx := make([]int, 0, len(batch))
+
+	for i, msg := range batch {
+		order, err := p.decode(msg)
+		if err != nil {
+			results[i] = p.rejectUndecodable(ctx, msg, err)
+			continue
+		}
+		orders = append(orders, order)
+		msgs = append(msgs, msg)
+		idx = append(idx, i)
+	}
+	if len(orders) == 0 {
+		return results
+	}
+
+	// Retries and DLQ entries are written with ctx rather than batchCtx, so
+	// each message stays in its own trace.
+	batchCtx, span := startBatchSpan(ctx, msgs[0].Topic, msgs)
+	errs := p.saveOrderUC.ExecuteBatch(batchCtx, orders)
+	span.End()
+
+	for j, order := range orders {
+		results[idx[j]] = p.handleResult(ctx, msgs[j], order, errs[j])
+	}
+	return results
+}
+
+func (p *OrderProcessor) decode(msg model.Message) (*model.Order, error) {
+	contentType, _ := headerValue(msg.Headers, HeaderContentType)
+	version, err := schemaVersion(msg.Headers)
+	if err == nil {
+		var order *model.Order
+		order, version, err = p.codecs.Decode(contentType, version, msg.Value)
+		if err == nil {
+			metrics.KafkaOrderSchemaVersions.WithLabelValues(msg.Topic, strconv.Itoa(version)).Inc()
+			return order, nil
+		}
+	}
+	if errors.Is(err, codec.ErrUnsupportedSchemaVersion) {
+		metrics.KafkaOrderSchemaVersions.WithLabelValues(msg.Topic, metrics.SchemaVersionUnsupported).Inc()
+	}
+	return nil, err
+}
+
+// schemaVersion returns the version the message names in its header, or 0.
+func schemaVersion(headers []model.MessageHeader) (int, error) {
+	value, ok := headerValue(headers, HeaderSchemaVersion)
+	if !ok {
+		return 0, nil
+	}
+	version, err := strconv.Atoi(value)
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("%w: %q", codec.ErrUnsupportedSchemaVersion, value)
+	}
+	return version, nil
+}
+
+func (p *OrderProcessor) rejectUndecodable(ctx context.Context, msg model.Message, cause error) bool {
+	reason := ReasonUnmarshalFailed
+	switch {
+	case errors.Is(cause, codec.ErrUnsupportedContentType):
+		reason = ReasonUnsupportedContentType
+	case errors.Is(cause, codec.ErrUnsupportedSchemaVersion):
+		reason = ReasonUnsupportedSchemaVersion
+	}
+	p.logger.Error("Failed to decode order", zap.Error(cause), zap.String("reason", reason), zap.ByteString("message", msg.Value))
+	if err := p.dlq.Publish(ctx, msg, reason, cause); err != nil {
+		p.logger.Error("Failed to publish message to DLQ, leaving uncommitted", zap.Error(err))
+		return false
+	}
+	return true
+}
+
+func (p *OrderProcessor) handleResult(ctx context.Context, msg model.Message, order *model.Order, err error) bool {
+	switch {
+	case err == nil:
+		p.logger.Info("Order processed from Kafka", zap.String("order_uid", order.OrderUID))
+	case errors.Is(err, model.ErrOrderAlreadyExists):
+		p.logger.Info("Order already exists, skipping", zap.String("order_uid", order.OrderUID))
+	case errors.Is(err, model.ErrInvalidOrderData):
+		p.logger.Info("Invalid order data, sending to DLQ", zap.String("order_uid", order.OrderUID), violationsField(err))
+		if err := p.dlq.Publish(ctx, msg, ReasonInvalidOrder, err); err != nil {
+			p.logger.Error("Failed to publish message to DLQ, leaving uncommitted", zap.Error(err), zap.String("order_uid", order.OrderUID))
+			return false
+		}
+	case ctx.Err() != nil:
+		p.logger.Info("Order processing interrupted by shutdown, leaving uncommitted", zap.String("order_uid", order.OrderUID))
+		return false
+	default:
+		p.logger.Error("Failed to save order, scheduling retry", zap.Error(err), zap.String("order_uid", order.OrderUID))
+		if err := p.retrier.Retry(ctx, msg, err); err != nil {
+			p.logger.Error("Failed to schedule retry, leaving uncommitted", zap.Error(err), zap.String("order_uid", order.OrderUID))
+			return false
+		}
+	}
+	return true
+}
+
+// violationsField logs the field violations of a validation error, falling
+// back to the plain error for anything else.
+func violationsField(err error) zap.Field {
+	var validationErr *model.ValidationError
+	if errors.As(err, &validationErr) {
+		return zap.Any("violations", validationErr.Violations)
+	}
+	return zap.Error(err)
+}
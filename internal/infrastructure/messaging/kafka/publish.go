@@ -0,0 +1,46 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"l0/internal/domain/model"
+	"l0/internal/domain/repository"
+
+	"go.uber.org/zap"
+)
+
+const (
+	publishBackoff    = 100 * time.Millisecond
+	publishMaxBackoff = 30 * time.Second
+)
+
+// publishUntilDone writes msg, retrying with exponential backoff until the
+// write succeeds or ctx is cancelled. Retry and DLQ writes use it: a message
+// that could be neither retried nor dead-lettered would hold back its
+// partition's commits, so the consumer waits for the broker instead. The
+// error is only returned once ctx is done.
+func publishUntilDone(ctx context.Context, publisher repository.MessagePublisher, msg model.Message, logger *zap.Logger) error {
+	delay := publishBackoff
+	for attempt := 1; ; attempt++ {
+		err := publisher.Publish(ctx, msg)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return fmt.Errorf("%w (%w)", err, ctx.Err())
+		}
+		logger.Warn("Failed to publish message, retrying",
+			zap.Error(err), zap.String("topic", msg.Topic), zap.Int("attempt", attempt), zap.Duration("backoff", delay))
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%w (%w)", err, ctx.Err())
+		case <-timer.C:
+		}
+		delay = min(delay*2, publishMaxBackoff)
+	}
+}
@@ -0,0 +1,109 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"l0/internal/domain/model"
+	"l0/internal/domain/repository"
+	"l0/internal/infrastructure/metrics"
+	"l0/internal/infrastructure/tracing"
+
+	"go.uber.org/zap"
+)
+
+const ReasonRetriesExhausted = "retries_exhausted"
+
+// RetryTopics returns the names of the delayed retry topics for topic, one per
+// attempt. Attempt n is consumed from the n-th topic after a delay of
+// backoff * 2^(n-1).
+func RetryTopics(topic string, attempts int) []string {
+	topics := make([]string, 0, attempts)
+	for i := 1; i <= attempts; i++ {
+		topics = append(topics, fmt.Sprintf("%s_retry_%d", topic, i))
+	}
+	return topics
+}
+
+// RetryPublisher moves messages that failed with a transient error to the next
+// retry topic and hands them to the DLQ once every retry topic has been tried.
+type RetryPublisher struct {
+	publisher repository.MessagePublisher
+	topics    []string
+	backoff   time.Duration
+	dlq       *DeadLetterPublisher
+	logger    *zap.Logger
+}
+
+func NewRetryPublisher(publisher repository.MessagePublisher, topics []string, backoff time.Duration, dlq *DeadLetterPublisher, logger *zap.Logger) *RetryPublisher {
+	return &RetryPublisher{publisher: publisher, topics: topics, backoff: backoff, dlq: dlq, logger: logger}
+}
+
+// Retry, like DeadLetterPublisher.Publish, only fails once ctx is cancelled.
+func (p *RetryPublisher) Retry(ctx context.Context, msg model.Message, cause error) error {
+	attempt := retryAttempt(msg) + 1
+	if attempt > len(p.topics) {
+		p.logger.Warn("Retry attempts exhausted, sending to DLQ",
+			zap.Int("attempts", attempt-1), zap.String("source_topic", msg.Topic), zap.Error(cause))
+		return p.dlq.Publish(ctx, msg, ReasonRetriesExhausted, cause)
+	}
+
+	delay := p.backoff << (attempt - 1)
+	notBefore := time.Now().Add(delay)
+
+	headers := withHeader(tracing.Inject(ctx, msg.Headers), HeaderRetryAttempt, strconv.Itoa(attempt))
+	headers = withHeader(headers, HeaderRetryNotBefore, formatInt(notBefore.UnixMilli()))
+	headers = withHeader(headers, HeaderRetryError, cause.Error())
+	if _, ok := headerValue(headers, HeaderRetryOriginalTopic); !ok {
+		headers = withHeader(headers, HeaderRetryOriginalTopic, msg.Topic)
+	}
+
+	topic := p.topics[attempt-1]
+	if err := publishUntilDone(ctx, p.publisher, model.Message{Topic: topic, Key: msg.Key, Value: msg.Value, Headers: headers}, p.logger); err != nil {
+		return fmt.Errorf("failed to write message to retry topic %s: %w", topic, err)
+	}
+
+	metrics.KafkaMessagesRetried.WithLabelValues(msg.Topic).Inc()
+	p.logger.Info("Message scheduled for retry",
+		zap.String("retry_topic", topic), zap.Int("attempt", attempt), zap.Duration("delay", delay))
+	return nil
+}
+
+func retryAttempt(msg model.Message) int {
+	value, ok := headerValue(msg.Headers, HeaderRetryAttempt)
+	if !ok {
+		return 0
+	}
+	attempt, err := strconv.Atoi(value)
+	if err != nil || attempt < 0 {
+		return 0
+	}
+	return attempt
+}
+
+// waitUntilDue blocks until the retry delay recorded in msg has elapsed.
+func waitUntilDue(ctx context.Context, msg model.Message) error {
+	value, ok := headerValue(msg.Headers, HeaderRetryNotBefore)
+	if !ok {
+		return nil
+	}
+	ms, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil
+	}
+	delay := time.Until(time.UnixMilli(ms))
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
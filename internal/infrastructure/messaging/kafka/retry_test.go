@@ -0,0 +1,195 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"l0/internal/domain/model"
+	"l0/internal/infrastructure/messaging/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestRetryTopics(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{"orders_retry_1", "orders_retry_2", "orders_retry_3"}, RetryTopics("orders", 3))
+	assert.Empty(t, RetryTopics("orders", 0))
+}
+
+func TestRetryAttempt(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		headers []model.MessageHeader
+		want    int
+	}{
+		{name: "no header", want: 0},
+		{name: "attempt", headers: []model.MessageHeader{{Key: HeaderRetryAttempt, Value: []byte("2")}}, want: 2},
+		{
+			name: "last header wins",
+			headers: []model.MessageHeader{
+				{Key: HeaderRetryAttempt, Value: []byte("1")},
+				{Key: HeaderRetryAttempt, Value: []byte("3")},
+			},
+			want: 3,
+		},
+		{name: "not a number", headers: []model.MessageHeader{{Key: HeaderRetryAttempt, Value: []byte("two")}}, want: 0},
+		{name: "negative", headers: []model.MessageHeader{{Key: HeaderRetryAttempt, Value: []byte("-1")}}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, retryAttempt(model.Message{Headers: tt.headers}))
+		})
+	}
+}
+
+func newTestRetryPublisher(broker *memory.Broker, attempts int, backoff time.Duration) *RetryPublisher {
+	logger := zap.NewNop()
+	dlq := NewDeadLetterPublisher(broker, testDLQ, logger)
+	return NewRetryPublisher(broker, RetryTopics(testTopic, attempts), backoff, dlq, logger)
+}
+
+func TestRetryPublisher_Retry_MovesToNextTopic(t *testing.T) {
+	t.Parallel()
+
+	broker := memory.NewBroker(1)
+	retrier := newTestRetryPublisher(broker, 2, time.Minute)
+	topics := RetryTopics(testTopic, 2)
+	msg := model.Message{Topic: testTopic, Key: []byte("order-1"), Value: []byte(`{"order_uid":"order-1"}`)}
+
+	start := time.Now()
+	require.NoError(t, retrier.Retry(context.Background(), msg, errors.New("db connection lost")))
+
+	first := broker.Messages(topics[0])
+	require.Len(t, first, 1)
+	assert.Equal(t, msg.Key, first[0].Key)
+	assert.Equal(t, msg.Value, first[0].Value)
+	attempt, _ := headerValue(first[0].Headers, HeaderRetryAttempt)
+	assert.Equal(t, "1", attempt)
+	retryErr, _ := headerValue(first[0].Headers, HeaderRetryError)
+	assert.Equal(t, "db connection lost", retryErr)
+	original, _ := headerValue(first[0].Headers, HeaderRetryOriginalTopic)
+	assert.Equal(t, testTopic, original)
+	assert.WithinDuration(t, start.Add(time.Minute), notBefore(t, first[0]), time.Second)
+
+	// The second failure is read from the first retry topic and waits twice
+	// as long, but still names the topic the message came from.
+	require.NoError(t, retrier.Retry(context.Background(), first[0], errors.New("db connection lost")))
+
+	second := broker.Messages(topics[1])
+	require.Len(t, second, 1)
+	attempt, _ = headerValue(second[0].Headers, HeaderRetryAttempt)
+	assert.Equal(t, "2", attempt)
+	original, _ = headerValue(second[0].Headers, HeaderRetryOriginalTopic)
+	assert.Equal(t, testTopic, original)
+	assert.WithinDuration(t, start.Add(2*time.Minute), notBefore(t, second[0]), time.Second)
+	assert.Empty(t, broker.Messages(testDLQ))
+}
+
+func TestRetryPublisher_Retry_ExhaustedGoesToDLQ(t *testing.T) {
+	t.Parallel()
+
+	broker := memory.NewBroker(1)
+	retrier := newTestRetryPublisher(broker, 2, time.Millisecond)
+	msg := model.Message{
+		Topic:   RetryTopics(testTopic, 2)[1],
+		Key:     []byte("order-1"),
+		Headers: []model.MessageHeader{{Key: HeaderRetryAttempt, Value: []byte("2")}},
+	}
+
+	require.NoError(t, retrier.Retry(context.Background(), msg, errors.New("db connection lost")))
+
+	dead := broker.Messages(testDLQ)
+	require.Len(t, dead, 1)
+	reason, _ := headerValue(dead[0].Headers, HeaderDLQReason)
+	assert.Equal(t, ReasonRetriesExhausted, reason)
+	dlqErr, _ := headerValue(dead[0].Headers, HeaderDLQError)
+	assert.Equal(t, "db connection lost", dlqErr)
+}
+
+func TestRetryPublisher_Retry_WithoutRetryTopics(t *testing.T) {
+	t.Parallel()
+
+	broker := memory.NewBroker(1)
+	retrier := newTestRetryPublisher(broker, 0, time.Millisecond)
+
+	require.NoError(t, retrier.Retry(context.Background(), model.Message{Topic: testTopic}, errors.New("boom")))
+
+	assert.Len(t, broker.Messages(testDLQ), 1)
+}
+
+func TestRetryPublisher_Retry_PublishFailsUntilCancelled(t *testing.T) {
+	t.Parallel()
+
+	broker := memory.NewBroker(1)
+	require.NoError(t, broker.Close())
+	retrier := newTestRetryPublisher(broker, 1, time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 3*publishBackoff)
+	defer cancel()
+
+	err := retrier.Retry(ctx, model.Message{Topic: testTopic}, errors.New("boom"))
+
+	require.ErrorIs(t, err, memory.ErrClosed)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorContains(t, err, RetryTopics(testTopic, 1)[0])
+}
+
+func notBefore(t *testing.T, msg model.Message) time.Time {
+	t.Helper()
+	value, ok := headerValue(msg.Headers, HeaderRetryNotBefore)
+	require.True(t, ok)
+	ms, err := strconv.ParseInt(value, 10, 64)
+	require.NoError(t, err)
+	return time.UnixMilli(ms)
+}
+
+func dueAt(at time.Time) model.Message {
+	return model.Message{Headers: []model.MessageHeader{
+		{Key: HeaderRetryNotBefore, Value: []byte(formatInt(at.UnixMilli()))},
+	}}
+}
+
+func TestWaitUntilDue(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no delay", func(t *testing.T) {
+		t.Parallel()
+
+		for _, msg := range []model.Message{
+			{},
+			dueAt(time.Now().Add(-time.Minute)),
+			{Headers: []model.MessageHeader{{Key: HeaderRetryNotBefore, Value: []byte("soon")}}},
+		} {
+			start := time.Now()
+			require.NoError(t, waitUntilDue(context.Background(), msg))
+			assert.Less(t, time.Since(start), 50*time.Millisecond)
+		}
+	})
+
+	t.Run("waits for the due time", func(t *testing.T) {
+		t.Parallel()
+
+		due := time.Now().Add(50 * time.Millisecond)
+		require.NoError(t, waitUntilDue(context.Background(), dueAt(due)))
+		// The header has millisecond precision.
+		assert.False(t, time.Now().Before(due.Truncate(time.Millisecond)))
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		err := waitUntilDue(ctx, dueAt(time.Now().Add(time.Hour)))
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
@@ -0,0 +1,119 @@
+package kafka
+
+import (
+	"context"
+
+	"l0/internal/domain/model"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// Source reads a topic as a member of a consumer group. It is both the
+// consumer's MessageSource and its Committer.
+type Source struct {
+	reader *kafka.Reader
+}
+
+func NewSource(client ClientOptions, topic, groupID string, opts ReaderOptions) *Source {
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:        client.Brokers,
+		Dialer:         client.dialer(),
+		Topic:          topic,
+		GroupID:        groupID,
+		MinBytes:       opts.MinBytes,
+		MaxBytes:       opts.MaxBytes,
+		MaxWait:        opts.MaxWait,
+		StartOffset:    opts.StartOffset,
+		SessionTimeout: opts.SessionTimeout,
+	})
+	return &Source{reader: reader}
+}
+
+func (s *Source) Fetch(ctx context.Context) (model.Message, error) {
+	msg, err := s.reader.FetchMessage(ctx)
+	if err != nil {
+		return model.Message{}, err
+	}
+	return fromKafkaMessage(msg), nil
+}
+
+func (s *Source) Commit(ctx context.Context, msgs ...model.Message) error {
+	offsets := make([]kafka.Message, len(msgs))
+	for i, msg := range msgs {
+		offsets[i] = kafka.Message{Topic: msg.Topic, Partition: msg.Partition, Offset: msg.Offset}
+	}
+	return s.reader.CommitMessages(ctx, offsets...)
+}
+
+func (s *Source) Close() error {
+	return s.reader.Close()
+}
+
+// joinedGroup reports whether the reader has got a group generation yet.
+// kafka-go has no join callback, but it counts a rebalance each time a
+// generation starts, which the reader's stats expose. Stats resets the
+// reader's counters, which nothing else reads.
+func (s *Source) joinedGroup() bool {
+	return s.reader.Stats().Rebalances > 0
+}
+
+// Publisher writes messages to Kafka, each to the topic it names. Callers
+// commit offsets or mark outbox events as published once Publish returns, so
+// writes wait for every in-sync replica.
+type Publisher struct {
+	writer    *kafka.Writer
+	transport *kafka.Transport
+}
+
+func NewPublisher(client ClientOptions) *Publisher {
+	transport := client.transport()
+	writer := &kafka.Writer{
+		Addr:                   kafka.TCP(client.Brokers...),
+		Transport:              transport,
+		Balancer:               &kafka.Hash{},
+		RequiredAcks:           kafka.RequireAll,
+		AllowAutoTopicCreation: true,
+	}
+	return &Publisher{writer: writer, transport: transport}
+}
+
+func (p *Publisher) Publish(ctx context.Context, msgs ...model.Message) error {
+	out := make([]kafka.Message, len(msgs))
+	for i, msg := range msgs {
+		out[i] = kafka.Message{Topic: msg.Topic, Key: msg.Key, Value: msg.Value, Headers: toKafkaHeaders(msg.Headers)}
+	}
+	return p.writer.WriteMessages(ctx, out...)
+}
+
+// Close flushes pending writes. kafka-go leaves the connections of a writer's
+// own transport open, so they are closed here.
+func (p *Publisher) Close() error {
+	err := p.writer.Close()
+	p.transport.CloseIdleConnections()
+	return err
+}
+
+func fromKafkaMessage(msg kafka.Message) model.Message {
+	headers := make([]model.MessageHeader, len(msg.Headers))
+	for i, h := range msg.Headers {
+		headers[i] = model.MessageHeader(h)
+	}
+	return model.Message{
+		Topic:         msg.Topic,
+		Partition:     msg.Partition,
+		Offset:        msg.Offset,
+		HighWaterMark: msg.HighWaterMark,
+		Key:           msg.Key,
+		Value:         msg.Value,
+		Headers:       headers,
+		Time:          msg.Time,
+	}
+}
+
+func toKafkaHeaders(headers []model.MessageHeader) []kafka.Header {
+	out := make([]kafka.Header, len(headers))
+	for i, h := range headers {
+		out[i] = kafka.Header(h)
+	}
+	return out
+}
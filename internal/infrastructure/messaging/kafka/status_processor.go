@@ -0,0 +1,77 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"l0/internal/domain/model"
+	"l0/internal/domain/repository"
+	"l0/internal/infrastructure/tracing"
+
+	"go.uber.org/zap"
+)
+
+const (
+	ReasonInvalidStatusUpdate     = "invalid_status_update"
+	ReasonInvalidStatusTransition = "invalid_status_transition"
+)
+
+// StatusProcessor applies order status updates. Malformed updates and illegal
+// transitions go to the DLQ; everything else that fails, including updates for
+// orders that have not been saved yet, is retried.
+type StatusProcessor struct {
+	changeStatusUC repository.StatusChanger
+	retrier        *RetryPublisher
+	dlq            *DeadLetterPublisher
+	logger         *zap.Logger
+}
+
+func NewStatusProcessor(changeStatusUC repository.StatusChanger, retrier *RetryPublisher, dlq *DeadLetterPublisher, logger *zap.Logger) *StatusProcessor {
+	return &StatusProcessor{changeStatusUC: changeStatusUC, retrier: retrier, dlq: dlq, logger: logger}
+}
+
+func (p *StatusProcessor) Process(ctx context.Context, msg model.Message) bool {
+	ctx, span := startProcessSpan(ctx, msg)
+	defer span.End()
+
+	var change model.StatusChange
+	if err := json.Unmarshal(msg.Value, &change); err != nil {
+		p.logger.Error("Failed to unmarshal status update", zap.Error(err), zap.String("message", string(msg.Value)))
+		return p.deadLetter(ctx, msg, ReasonUnmarshalFailed, err)
+	}
+
+	span.SetAttributes(tracing.OrderUID(change.OrderUID))
+
+	err := p.changeStatusUC.Execute(ctx, &change)
+	tracing.Record(span, err)
+	switch {
+	case err == nil:
+		p.logger.Info("Status update processed from Kafka",
+			zap.String("order_uid", change.OrderUID), zap.String("status", string(change.Status)))
+	case errors.Is(err, model.ErrInvalidStatusUpdate):
+		p.logger.Info("Invalid status update, sending to DLQ", zap.String("order_uid", change.OrderUID), zap.Error(err))
+		return p.deadLetter(ctx, msg, ReasonInvalidStatusUpdate, err)
+	case errors.Is(err, model.ErrInvalidStatusTransition):
+		p.logger.Info("Illegal status transition, sending to DLQ", zap.String("order_uid", change.OrderUID), zap.Error(err))
+		return p.deadLetter(ctx, msg, ReasonInvalidStatusTransition, err)
+	case ctx.Err() != nil:
+		p.logger.Info("Status update interrupted by shutdown, leaving uncommitted", zap.String("order_uid", change.OrderUID))
+		return false
+	default:
+		p.logger.Error("Failed to apply status update, scheduling retry", zap.Error(err), zap.String("order_uid", change.OrderUID))
+		if err := p.retrier.Retry(ctx, msg, err); err != nil {
+			p.logger.Error("Failed to schedule retry, leaving uncommitted", zap.Error(err), zap.String("order_uid", change.OrderUID))
+			return false
+		}
+	}
+	return true
+}
+
+func (p *StatusProcessor) deadLetter(ctx context.Context, msg model.Message, reason string, cause error) bool {
+	if err := p.dlq.Publish(ctx, msg, reason, cause); err != nil {
+		p.logger.Error("Failed to publish message to DLQ, leaving uncommitted", zap.Error(err))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,121 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"l0/internal/domain/model"
+	"l0/internal/domain/repository/mocks"
+	"l0/internal/infrastructure/messaging/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+const testStatusTopic = "order_status"
+
+func newTestStatusProcessor(broker *memory.Broker, changer *mocks.MockStatusChanger) *StatusProcessor {
+	logger := zap.NewNop()
+	dlq := NewDeadLetterPublisher(broker, testDLQ, logger)
+	retrier := NewRetryPublisher(broker, RetryTopics(testStatusTopic, 1), time.Millisecond, dlq, logger)
+	return NewStatusProcessor(changer, retrier, dlq, logger)
+}
+
+func TestStatusProcessor_Process(t *testing.T) {
+	t.Parallel()
+
+	update := `{"order_uid":"order-1","status":"paid","reason":"payment received"}`
+
+	tests := []struct {
+		name       string
+		value      string
+		changeErr  error
+		wantDLQ    string
+		wantRetry  bool
+		skipChange bool
+	}{
+		{name: "applied", value: update},
+		{name: "malformed", value: `{"order_uid":`, skipChange: true, wantDLQ: ReasonUnmarshalFailed},
+		{
+			name:      "invalid update",
+			value:     update,
+			changeErr: fmt.Errorf("%w: reason is too long", model.ErrInvalidStatusUpdate),
+			wantDLQ:   ReasonInvalidStatusUpdate,
+		},
+		{
+			name:      "illegal transition",
+			value:     update,
+			changeErr: fmt.Errorf("%w: created -> delivered", model.ErrInvalidStatusTransition),
+			wantDLQ:   ReasonInvalidStatusTransition,
+		},
+		{name: "order not saved yet", value: update, changeErr: model.ErrOrderNotFound, wantRetry: true},
+		{name: "transient error", value: update, changeErr: errors.New("db connection lost"), wantRetry: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			changer := mocks.NewMockStatusChanger(ctrl)
+			broker := memory.NewBroker(1)
+			processor := newTestStatusProcessor(broker, changer)
+
+			if !tt.skipChange {
+				changer.EXPECT().Execute(gomock.Any(), &model.StatusChange{
+					OrderUID: "order-1",
+					Status:   model.OrderStatusPaid,
+					Reason:   "payment received",
+				}).Return(tt.changeErr)
+			}
+
+			ok := processor.Process(context.Background(), model.Message{Topic: testStatusTopic, Key: []byte("order-1"), Value: []byte(tt.value)})
+
+			assert.True(t, ok)
+			dead := broker.Messages(testDLQ)
+			if tt.wantDLQ != "" {
+				require.Len(t, dead, 1)
+				reason, _ := headerValue(dead[0].Headers, HeaderDLQReason)
+				assert.Equal(t, tt.wantDLQ, reason)
+			} else {
+				assert.Empty(t, dead)
+			}
+			retries := broker.Messages(RetryTopics(testStatusTopic, 1)[0])
+			if tt.wantRetry {
+				assert.Len(t, retries, 1)
+			} else {
+				assert.Empty(t, retries)
+			}
+		})
+	}
+}
+
+func TestStatusProcessor_Process_Shutdown(t *testing.T) {
+	t.Parallel()
+
+	t.Run("during change", func(t *testing.T) {
+		t.Parallel()
+
+		ctrl := gomock.NewController(t)
+		changer := mocks.NewMockStatusChanger(ctrl)
+		broker := memory.NewBroker(1)
+		processor := newTestStatusProcessor(broker, changer)
+		ctx, cancel := context.WithCancel(context.Background())
+
+		changer.EXPECT().Execute(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, _ *model.StatusChange) error {
+				cancel()
+				return ctx.Err()
+			})
+
+		ok := processor.Process(ctx, model.Message{Topic: testStatusTopic, Value: []byte(`{"order_uid":"order-1","status":"paid"}`)})
+
+		assert.False(t, ok)
+		assert.Empty(t, broker.Messages(RetryTopics(testStatusTopic, 1)[0]), "a shutdown is not a failure to retry")
+	})
+}
@@ -0,0 +1,62 @@
+package kafka
+
+import (
+	"context"
+	"strconv"
+
+	"l0/internal/domain/model"
+	"l0/internal/infrastructure/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func messageAttributes(msg model.Message) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		semconv.MessagingSystemKafka,
+		semconv.MessagingDestinationName(msg.Topic),
+		semconv.MessagingDestinationPartitionID(strconv.Itoa(msg.Partition)),
+		semconv.MessagingKafkaOffset(int(msg.Offset)),
+		semconv.MessagingKafkaMessageKey(string(msg.Key)),
+	}
+}
+
+// traceReceived opens the receive span of a fetched message as a child of
+// the producer's span and writes its context back into the headers, so the
+// processing span, retries and DLQ entries continue the same trace. The span
+// is ended once the message has been handed to a worker.
+func traceReceived(ctx context.Context, msg model.Message) (model.Message, trace.Span) {
+	ctx, span := tracing.Tracer().Start(tracing.Extract(ctx, msg.Headers), msg.Topic+" receive",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(append(messageAttributes(msg), semconv.MessagingOperationTypeReceive)...))
+	msg.Headers = tracing.Inject(ctx, msg.Headers)
+	return msg, span
+}
+
+// startProcessSpan opens the span under which a message is handled.
+func startProcessSpan(ctx context.Context, msg model.Message) (context.Context, trace.Span) {
+	return tracing.Tracer().Start(tracing.Extract(ctx, msg.Headers), msg.Topic+" process",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(append(messageAttributes(msg), semconv.MessagingOperationTypeProcess)...))
+}
+
+// startBatchSpan opens the span under which a batch is saved. A batch has no
+// single parent, so the span links to the trace of every message in it.
+func startBatchSpan(ctx context.Context, topic string, batch []model.Message) (context.Context, trace.Span) {
+	links := make([]trace.Link, 0, len(batch))
+	for _, msg := range batch {
+		if sc := trace.SpanContextFromContext(tracing.Extract(ctx, msg.Headers)); sc.IsValid() {
+			links = append(links, trace.Link{SpanContext: sc})
+		}
+	}
+	return tracing.Tracer().Start(ctx, topic+" process",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithLinks(links...),
+		trace.WithAttributes(
+			semconv.MessagingSystemKafka,
+			semconv.MessagingDestinationName(topic),
+			semconv.MessagingOperationTypeProcess,
+			semconv.MessagingBatchMessageCount(len(batch)),
+		))
+}
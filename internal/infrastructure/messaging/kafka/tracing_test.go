@@ -0,0 +1,44 @@
+package kafka
+
+import (
+	"context"
+	"testing"
+
+	"l0/internal/domain/model"
+	"l0/internal/infrastructure/tracing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTraceReceived_ChainsProducerReceiveAndProcess(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	sendCtx, send := tracing.Tracer().Start(context.Background(), "orders send")
+	msg := model.Message{Topic: "orders", Key: []byte("order-1"), Headers: tracing.Inject(sendCtx, nil)}
+	send.End()
+
+	msg, receive := traceReceived(context.Background(), msg)
+	receive.End()
+
+	_, process := startProcessSpan(context.Background(), msg)
+	process.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	sendSpan, receiveSpan, processSpan := spans[0], spans[1], spans[2]
+
+	assert.Equal(t, "orders receive", receiveSpan.Name())
+	assert.Equal(t, sendSpan.SpanContext().SpanID(), receiveSpan.Parent().SpanID())
+	assert.Equal(t, "orders process", processSpan.Name())
+	assert.Equal(t, receiveSpan.SpanContext().SpanID(), processSpan.Parent().SpanID())
+	assert.Equal(t, sendSpan.SpanContext().TraceID(), processSpan.SpanContext().TraceID())
+}
@@ -0,0 +1,199 @@
+// Package memory is an in-process message broker with the delivery semantics
+// the consumer relies on from Kafka: messages are kept per partition in
+// offset order, keys pick the partition, and a subscription resumes from its
+// group's committed offsets. It lets the consumer run in tests without a
+// broker.
+package memory
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"l0/internal/domain/model"
+)
+
+var ErrClosed = errors.New("broker closed")
+
+type partitionKey struct {
+	topic     string
+	partition int
+}
+
+type Broker struct {
+	partitions int
+
+	mu        sync.Mutex
+	logs      map[string][][]model.Message
+	committed map[string]map[partitionKey]int64
+	// published is closed and replaced on every publish, waking fetches
+	// waiting for new messages.
+	published chan struct{}
+	closed    bool
+}
+
+// NewBroker creates a broker whose topics have the given number of
+// partitions. Topics are created on first use.
+func NewBroker(partitions int) *Broker {
+	return &Broker{
+		partitions: max(partitions, 1),
+		logs:       make(map[string][][]model.Message),
+		committed:  make(map[string]map[partitionKey]int64),
+		published:  make(chan struct{}),
+	}
+}
+
+// Publish appends msgs to the topics they name. Messages with a key go to the
+// partition the key hashes to, others to the first partition.
+func (b *Broker) Publish(_ context.Context, msgs ...model.Message) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return ErrClosed
+	}
+	for _, msg := range msgs {
+		log := b.topicLog(msg.Topic)
+		msg.Partition = b.partitionFor(msg.Key)
+		msg.Offset = int64(len(log[msg.Partition]))
+		msg.Headers = append([]model.MessageHeader(nil), msg.Headers...)
+		if msg.Time.IsZero() {
+			msg.Time = time.Now()
+		}
+		log[msg.Partition] = append(log[msg.Partition], msg)
+	}
+	close(b.published)
+	b.published = make(chan struct{})
+	return nil
+}
+
+// Messages returns what has been published to topic, partition by partition.
+func (b *Broker) Messages(topic string) []model.Message {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var out []model.Message
+	for _, partition := range b.logs[topic] {
+		out = append(out, partition...)
+	}
+	return out
+}
+
+// Committed returns the offset group will resume partition of topic from,
+// which is 0 if nothing has been committed.
+func (b *Broker) Committed(topic, group string, partition int) int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.committed[group][partitionKey{topic, partition}]
+}
+
+// Subscribe reads topic as a member of group, starting after the group's
+// committed offsets. Unlike Kafka, every subscription reads all partitions,
+// so a group should have one subscription at a time.
+func (b *Broker) Subscribe(topic, group string) *Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	next := make([]int64, b.partitions)
+	for p := range next {
+		next[p] = b.committed[group][partitionKey{topic, p}]
+	}
+	return &Subscription{broker: b, topic: topic, group: group, next: next}
+}
+
+// Close fails later publishes and fetches. Messages already published stay
+// readable through Messages and Committed.
+func (b *Broker) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.closed {
+		b.closed = true
+		close(b.published)
+	}
+	return nil
+}
+
+// topicLog must be called with mu held.
+func (b *Broker) topicLog(topic string) [][]model.Message {
+	log, ok := b.logs[topic]
+	if !ok {
+		log = make([][]model.Message, b.partitions)
+		b.logs[topic] = log
+	}
+	return log
+}
+
+func (b *Broker) partitionFor(key []byte) int {
+	if len(key) == 0 {
+		return 0
+	}
+	h := fnv.New32a()
+	_, _ = h.Write(key)
+	return int(h.Sum32() % uint32(b.partitions))
+}
+
+// Subscription is a consumer's MessageSource and Committer. It is not safe
+// for concurrent fetches; commits may come from any goroutine.
+type Subscription struct {
+	broker *Broker
+	topic  string
+	group  string
+	next   []int64
+	// last is the partition fetched from last, so partitions take turns.
+	last int
+}
+
+func (s *Subscription) Fetch(ctx context.Context) (model.Message, error) {
+	for {
+		s.broker.mu.Lock()
+		if s.broker.closed {
+			s.broker.mu.Unlock()
+			return model.Message{}, ErrClosed
+		}
+		log := s.broker.topicLog(s.topic)
+		for i := range s.next {
+			p := (s.last + 1 + i) % len(s.next)
+			if s.next[p] < int64(len(log[p])) {
+				msg := log[p][s.next[p]]
+				msg.HighWaterMark = int64(len(log[p]))
+				msg.Headers = append([]model.MessageHeader(nil), msg.Headers...)
+				s.next[p]++
+				s.last = p
+				s.broker.mu.Unlock()
+				return msg, nil
+			}
+		}
+		published := s.broker.published
+		s.broker.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return model.Message{}, ctx.Err()
+		case <-published:
+		}
+	}
+}
+
+// Commit records, like Kafka, the offset after each message as the one to
+// resume from. Commits never move a partition's offset backwards.
+func (s *Subscription) Commit(_ context.Context, msgs ...model.Message) error {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+
+	committed, ok := s.broker.committed[s.group]
+	if !ok {
+		committed = make(map[partitionKey]int64)
+		s.broker.committed[s.group] = committed
+	}
+	for _, msg := range msgs {
+		key := partitionKey{msg.Topic, msg.Partition}
+		committed[key] = max(committed[key], msg.Offset+1)
+	}
+	return nil
+}
+
+func (s *Subscription) Close() error {
+	return nil
+}
@@ -0,0 +1,95 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"l0/internal/domain/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fetch(t *testing.T, sub *Subscription) model.Message {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	msg, err := sub.Fetch(ctx)
+	require.NoError(t, err)
+	return msg
+}
+
+func TestBroker_KeepsKeyOrderWithinPartition(t *testing.T) {
+	t.Parallel()
+
+	broker := NewBroker(3)
+	ctx := context.Background()
+	for _, v := range []string{"a1", "b1", "a2", "b2", "a3"} {
+		require.NoError(t, broker.Publish(ctx, model.Message{Topic: "orders", Key: []byte(v[:1]), Value: []byte(v)}))
+	}
+
+	sub := broker.Subscribe("orders", "group")
+	byKey := map[string][]string{}
+	partitions := map[string]int{}
+	for range 5 {
+		msg := fetch(t, sub)
+		key := string(msg.Key)
+		byKey[key] = append(byKey[key], string(msg.Value))
+		if p, seen := partitions[key]; seen {
+			assert.Equal(t, p, msg.Partition, "a key always maps to the same partition")
+		}
+		partitions[key] = msg.Partition
+		assert.Greater(t, msg.HighWaterMark, msg.Offset)
+	}
+
+	assert.Equal(t, []string{"a1", "a2", "a3"}, byKey["a"])
+	assert.Equal(t, []string{"b1", "b2"}, byKey["b"])
+	assert.Len(t, broker.Messages("orders"), 5)
+}
+
+func TestBroker_SubscriptionResumesAfterCommit(t *testing.T) {
+	t.Parallel()
+
+	broker := NewBroker(1)
+	ctx := context.Background()
+	for _, v := range []string{"m0", "m1", "m2"} {
+		require.NoError(t, broker.Publish(ctx, model.Message{Topic: "orders", Value: []byte(v)}))
+	}
+
+	sub := broker.Subscribe("orders", "group")
+	first := fetch(t, sub)
+	second := fetch(t, sub)
+	require.NoError(t, sub.Commit(ctx, second))
+	require.NoError(t, sub.Commit(ctx, first), "an older commit must not move the offset back")
+	assert.Equal(t, int64(2), broker.Committed("orders", "group", 0))
+
+	resumed := broker.Subscribe("orders", "group")
+	assert.Equal(t, "m2", string(fetch(t, resumed).Value))
+
+	other := broker.Subscribe("orders", "other-group")
+	assert.Equal(t, "m0", string(fetch(t, other).Value), "groups keep their own offsets")
+}
+
+func TestBroker_FetchWaitsForMessages(t *testing.T) {
+	t.Parallel()
+
+	broker := NewBroker(1)
+	sub := broker.Subscribe("orders", "group")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := sub.Fetch(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		_ = broker.Publish(context.Background(), model.Message{Topic: "orders", Value: []byte("late")})
+	}()
+	assert.Equal(t, "late", string(fetch(t, sub).Value))
+
+	require.NoError(t, broker.Close())
+	_, err = sub.Fetch(context.Background())
+	assert.ErrorIs(t, err, ErrClosed)
+	assert.ErrorIs(t, broker.Publish(context.Background(), model.Message{Topic: "orders"}), ErrClosed)
+}
@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+
+	"l0/internal/domain/model"
+	"l0/internal/domain/repository"
+)
+
+// OrderCache counts hits, misses and errors of the wrapped cache.
+type OrderCache struct {
+	next repository.OrderCache
+}
+
+func NewOrderCache(next repository.OrderCache) repository.OrderCache {
+	return &OrderCache{next: next}
+}
+
+func (c *OrderCache) Get(ctx context.Context, orderUID string) (*model.Order, error) {
+	order, err := c.next.Get(ctx, orderUID)
+	CacheRequests.WithLabelValues("get", lookupResult(order != nil, err)).Inc()
+	if order != nil {
+		observeAge("cache", order)
+	}
+	return order, err
+}
+
+func (c *OrderCache) Set(ctx context.Context, order *model.Order) error {
+	err := c.next.Set(ctx, order)
+	CacheRequests.WithLabelValues("set", result(err)).Inc()
+	return err
+}
+
+func (c *OrderCache) MarkMissing(ctx context.Context, orderUID string) error {
+	err := c.next.MarkMissing(ctx, orderUID)
+	CacheRequests.WithLabelValues("mark_missing", result(err)).Inc()
+	return err
+}
+
+func (c *OrderCache) GetByTrack(ctx context.Context, trackNumber string) ([]*model.Order, error) {
+	orders, err := c.next.GetByTrack(ctx, trackNumber)
+	CacheRequests.WithLabelValues("get_by_track", lookupResult(orders != nil, err)).Inc()
+	return orders, err
+}
+
+func (c *OrderCache) SetByTrack(ctx context.Context, trackNumber string, orders []*model.Order) error {
+	err := c.next.SetByTrack(ctx, trackNumber, orders)
+	CacheRequests.WithLabelValues("set_by_track", result(err)).Inc()
+	return err
+}
+
+func (c *OrderCache) Fill(ctx context.Context, orders []*model.Order) error {
+	err := c.next.Fill(ctx, orders)
+	CacheRequests.WithLabelValues("fill", result(err)).Inc()
+	return err
+}
+
+func (c *OrderCache) Delete(ctx context.Context, orderUID string) error {
+	err := c.next.Delete(ctx, orderUID)
+	CacheRequests.WithLabelValues("delete", result(err)).Inc()
+	return err
+}
+
+func (c *OrderCache) Close() error {
+	return c.next.Close()
+}
+
+func lookupResult(found bool, err error) string {
+	switch {
+	case errors.Is(err, model.ErrOrderNotFound):
+		return ResultMissing
+	case err != nil:
+		return ResultError
+	case found:
+		return ResultHit
+	default:
+		return ResultMiss
+	}
+}
@@ -0,0 +1,33 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// RegisterLocalCacheEntries exports the size of the in-process cache, read
+// on every scrape from entries.
+func RegisterLocalCacheEntries(entries func() int) {
+	prometheus.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "local_cache",
+		Name:      "entries",
+		Help:      "Orders held in the in-process cache.",
+	}, func() float64 { return float64(entries()) }))
+}
+
+// LocalCacheHit records a lookup in the in-process cache.
+func LocalCacheHit(hit bool) {
+	if hit {
+		LocalCacheRequests.WithLabelValues(ResultHit).Inc()
+		return
+	}
+	LocalCacheRequests.WithLabelValues(ResultMiss).Inc()
+}
+
+// LocalCacheInvalidated records an invalidation received from another
+// instance.
+func LocalCacheInvalidated(reset bool) {
+	if reset {
+		LocalCacheInvalidations.WithLabelValues("reset").Inc()
+		return
+	}
+	LocalCacheInvalidations.WithLabelValues("order").Inc()
+}
@@ -0,0 +1,215 @@
+// Package metrics defines the service's Prometheus collectors and the
+// decorators and middleware that feed them, so the application layer stays
+// free of any metrics dependency.
+package metrics
+
+import (
+	"regexp"
+	"time"
+
+	"l0/internal/domain/model"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "orders"
+
+const (
+	ResultOK    = "ok"
+	ResultError = "error"
+
+	ResultHit  = "hit"
+	ResultMiss = "miss"
+	// ResultMissing is a lookup answered by a record that the order does not
+	// exist.
+	ResultMissing = "missing"
+
+	// SchemaVersionUnsupported labels order messages rejected for a schema
+	// version the service cannot read.
+	SchemaVersionUnsupported = "unsupported"
+)
+
+var (
+	KafkaMessagesConsumed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "kafka",
+		Name:      "messages_consumed_total",
+		Help:      "Messages fetched from Kafka.",
+	}, []string{"topic"})
+
+	KafkaMessagesCommitted = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "kafka",
+		Name:      "messages_committed_total",
+		Help:      "Messages whose offsets have been committed.",
+	}, []string{"topic"})
+
+	KafkaMessagesRejected = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "kafka",
+		Name:      "messages_rejected_total",
+		Help:      "Messages sent to the dead-letter topic, by reason.",
+	}, []string{"topic", "reason"})
+
+	KafkaMessagesRetried = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "kafka",
+		Name:      "messages_retried_total",
+		Help:      "Messages scheduled on a retry topic.",
+	}, []string{"topic"})
+
+	KafkaOrderSchemaVersions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "kafka",
+		Name:      "order_schema_versions_total",
+		Help:      "Order messages decoded, by the schema version they were written in; unsupported counts rejected versions.",
+	}, []string{"topic", "version"})
+
+	KafkaConsumerLag = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "kafka",
+		Name:      "consumer_lag",
+		Help:      "Messages behind the partition high watermark as of the last fetch.",
+	}, []string{"topic", "partition"})
+
+	ValidationViolations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "validation",
+		Name:      "violations_total",
+		Help:      "Field violations of rejected orders, by field path and rule.",
+	}, []string{"field", "rule"})
+
+	UseCaseDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "usecase",
+		Name:      "duration_seconds",
+		Help:      "Use case latency.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"usecase", "result"})
+
+	CacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "cache",
+		Name:      "requests_total",
+		Help:      "Order cache calls by operation and result (hit, miss, ok, error).",
+	}, []string{"operation", "result"})
+
+	// OrderAge shows how old the orders served from the cache and from the DB
+	// are, which tells whether the TTL keeps the orders that are actually read.
+	OrderAge = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "cache",
+		Name:      "order_age_seconds",
+		Help:      "Age of orders read by UID, by where they were served from (cache or db).",
+		Buckets:   []float64{3600, 6 * 3600, 86400, 3 * 86400, 7 * 86400, 30 * 86400, 90 * 86400, 365 * 86400},
+	}, []string{"source"})
+
+	CacheRestoredOrders = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "cache",
+		Name:      "restored_orders",
+		Help:      "Orders loaded into the cache by the startup restore so far, including those restored before a resume.",
+	})
+
+	CacheRepairs = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "cache",
+		Name:      "repairs_total",
+		Help:      "Background rewrites of orders whose cache write failed, by result (repaired, retried, failed, dropped).",
+	}, []string{"result"})
+
+	CacheRepairQueueSize = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "cache",
+		Name:      "repair_queue_size",
+		Help:      "Orders waiting for a cache repair, including those waiting to be retried.",
+	})
+
+	CacheReconciledOrders = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "cache",
+		Name:      "reconciled_orders_total",
+		Help:      "Orders the reconciler found out of line with the DB, by problem (missing, stale).",
+	}, []string{"problem"})
+
+	LocalCacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "local_cache",
+		Name:      "requests_total",
+		Help:      "Lookups in the in-process order cache by result (hit, miss).",
+	}, []string{"result"})
+
+	LocalCacheInvalidations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "local_cache",
+		Name:      "invalidations_total",
+		Help:      "Invalidations applied to the in-process cache, by kind (order for one order from another instance, reset for a purge after the subscription was re-established).",
+	}, []string{"kind"})
+
+	BreakerState = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "breaker",
+		Name:      "state",
+		Help:      "Circuit breaker state: 0 closed, 1 open.",
+	}, []string{"breaker"})
+
+	BreakerTransitions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "breaker",
+		Name:      "transitions_total",
+		Help:      "Circuit breaker state changes, by the state entered.",
+	}, []string{"breaker", "state"})
+
+	BreakerShortCircuits = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "breaker",
+		Name:      "short_circuits_total",
+		Help:      "Calls skipped because the circuit breaker was open, by operation.",
+	}, []string{"breaker", "operation"})
+
+	DBQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "query_duration_seconds",
+		Help:      "Order repository call latency by method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "result"})
+
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency by route.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+)
+
+func result(err error) string {
+	if err != nil {
+		return ResultError
+	}
+	return ResultOK
+}
+
+func since(start time.Time) float64 {
+	return time.Since(start).Seconds()
+}
+
+// observeAge records the age of a served order. Orders with an unparsable
+// date_created are skipped rather than counted as brand new.
+func observeAge(source string, order *model.Order) {
+	created, err := time.Parse(time.RFC3339, order.DateCreated)
+	if err != nil {
+		return
+	}
+	OrderAge.WithLabelValues(source).Observe(max(time.Since(created).Seconds(), 0))
+}
+
+var indexPattern = regexp.MustCompile(`\[\d+\]`)
+
+// FieldLabel drops slice indexes from a field path so "items[3].price" and
+// "items[0].price" share one series.
+func FieldLabel(path string) string {
+	return indexPattern.ReplaceAllString(path, "[]")
+}
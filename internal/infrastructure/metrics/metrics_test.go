@@ -0,0 +1,152 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"l0/internal/domain/model"
+	"l0/internal/domain/repository/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestFieldLabel(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "items[].price", FieldLabel("items[12].price"))
+	assert.Equal(t, "payment.amount", FieldLabel("payment.amount"))
+}
+
+func TestOrderCache_CountsLookups(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	next := mocks.NewMockOrderCache(ctrl)
+	c := NewOrderCache(next)
+	ctx := context.Background()
+
+	counter := func(result string) float64 {
+		return testutil.ToFloat64(CacheRequests.WithLabelValues("get", result))
+	}
+	hits, misses, errs := counter(ResultHit), counter(ResultMiss), counter(ResultError)
+
+	next.EXPECT().Get(ctx, "hit").Return(&model.Order{OrderUID: "hit"}, nil)
+	next.EXPECT().Get(ctx, "miss").Return(nil, nil)
+	next.EXPECT().Get(ctx, "err").Return(nil, errors.New("redis down"))
+
+	_, _ = c.Get(ctx, "hit")
+	_, _ = c.Get(ctx, "miss")
+	_, _ = c.Get(ctx, "err")
+
+	assert.Equal(t, hits+1, counter(ResultHit))
+	assert.Equal(t, misses+1, counter(ResultMiss))
+	assert.Equal(t, errs+1, counter(ResultError))
+}
+
+func TestOrderSaver_CountsViolations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	next := mocks.NewMockOrderSaver(ctrl)
+	s := NewOrderSaver(next)
+	ctx := context.Background()
+
+	violations := func() float64 {
+		return testutil.ToFloat64(ValidationViolations.WithLabelValues("items[].price", "gt"))
+	}
+	before := violations()
+
+	validationErr := &model.ValidationError{Violations: []model.FieldViolation{
+		{Field: "items[0].price", Rule: "gt"},
+		{Field: "items[1].price", Rule: "gt"},
+	}}
+	next.EXPECT().Execute(ctx, gomock.Any()).Return(validationErr)
+
+	err := s.Execute(ctx, &model.Order{})
+	require.ErrorIs(t, err, model.ErrInvalidOrderData)
+	assert.Equal(t, before+2, violations())
+}
+
+func TestBatchResults(t *testing.T) {
+	t.Parallel()
+
+	failed := errors.New("db down")
+	tests := []struct {
+		name       string
+		errs       []error
+		wantSave   string
+		wantResult string
+	}{
+		{name: "all saved", errs: []error{nil, nil}, wantSave: ResultOK, wantResult: ResultOK},
+		{name: "empty", wantSave: ResultOK, wantResult: ResultOK},
+		{name: "duplicate", errs: []error{nil, model.ErrOrderAlreadyExists}, wantSave: "duplicate", wantResult: ResultError},
+		{name: "invalid", errs: []error{model.ErrOrderAlreadyExists, model.ErrInvalidOrderData, nil}, wantSave: "invalid", wantResult: ResultError},
+		{name: "one failed", errs: []error{nil, model.ErrInvalidOrderData, failed, nil}, wantSave: ResultError, wantResult: ResultError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.wantSave, batchSaveResult(tt.errs))
+			assert.Equal(t, tt.wantResult, result(errors.Join(tt.errs...)))
+		})
+	}
+}
+
+func TestGinMiddleware_LabelsByRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(GinMiddleware())
+	r.GET("/order/:order_uid", func(c *gin.Context) { c.Status(http.StatusNotFound) })
+
+	count := func() int {
+		return testutil.CollectAndCount(HTTPRequestDuration, "orders_http_request_duration_seconds")
+	}
+	before := count()
+
+	for _, uid := range []string{"a", "b", "c"} {
+		req := httptest.NewRequest(http.MethodGet, "/order/"+uid, nil)
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	assert.Equal(t, before+1, count(), "requests for different UIDs must share one series")
+}
+
+func TestOrderCache_ObservesAgeOfHits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	next := mocks.NewMockOrderCache(ctrl)
+	c := NewOrderCache(next)
+
+	count := func() int {
+		return testutil.CollectAndCount(OrderAge, "orders_cache_order_age_seconds")
+	}
+	created := time.Now().Add(-48 * time.Hour).Format(time.RFC3339)
+	next.EXPECT().Get(gomock.Any(), "old").Return(&model.Order{OrderUID: "old", DateCreated: created}, nil)
+
+	_, _ = c.Get(context.Background(), "old")
+	assert.GreaterOrEqual(t, count(), 1)
+}
+
+func TestRedisCollector(t *testing.T) {
+	t.Parallel()
+
+	collector := &redisCollector{stats: func(context.Context) (RedisStats, error) {
+		return RedisStats{UsedMemory: 2048, Keys: 3, EvictedKeys: 1}, nil
+	}}
+	assert.Equal(t, 5, testutil.CollectAndCount(collector))
+	require.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(`
+# HELP orders_redis_used_memory_bytes Memory used by Redis.
+# TYPE orders_redis_used_memory_bytes gauge
+orders_redis_used_memory_bytes 2048
+`), "orders_redis_used_memory_bytes"))
+
+	failing := &redisCollector{stats: func(context.Context) (RedisStats, error) {
+		return RedisStats{}, errors.New("redis down")
+	}}
+	assert.Zero(t, testutil.CollectAndCount(failing))
+}
@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const redisStatsTimeout = 2 * time.Second
+
+// RedisStats is the part of Redis INFO and DBSIZE exported as metrics.
+type RedisStats struct {
+	UsedMemory  int64
+	MaxMemory   int64
+	Keys        int64
+	ExpiredKeys int64
+	EvictedKeys int64
+}
+
+var (
+	redisUsedMemoryDesc = prometheus.NewDesc(namespace+"_redis_used_memory_bytes",
+		"Memory used by Redis.", nil, nil)
+	redisMaxMemoryDesc = prometheus.NewDesc(namespace+"_redis_maxmemory_bytes",
+		"Redis maxmemory setting, 0 if unlimited.", nil, nil)
+	redisKeysDesc = prometheus.NewDesc(namespace+"_redis_keys",
+		"Keys in the Redis database.", nil, nil)
+	redisExpiredKeysDesc = prometheus.NewDesc(namespace+"_redis_expired_keys_total",
+		"Keys removed by Redis because their TTL ran out.", nil, nil)
+	redisEvictedKeysDesc = prometheus.NewDesc(namespace+"_redis_evicted_keys_total",
+		"Keys evicted by Redis to stay under maxmemory.", nil, nil)
+)
+
+// redisCollector reads Redis stats on every scrape, so the values are never
+// older than the scrape itself.
+type redisCollector struct {
+	stats func(ctx context.Context) (RedisStats, error)
+}
+
+// RegisterRedisStats exports the stats returned by stats. A failed read
+// leaves the Redis series out of that scrape.
+func RegisterRedisStats(stats func(ctx context.Context) (RedisStats, error)) {
+	prometheus.MustRegister(&redisCollector{stats: stats})
+}
+
+func (c *redisCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- redisUsedMemoryDesc
+	ch <- redisMaxMemoryDesc
+	ch <- redisKeysDesc
+	ch <- redisExpiredKeysDesc
+	ch <- redisEvictedKeysDesc
+}
+
+func (c *redisCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisStatsTimeout)
+	defer cancel()
+
+	stats, err := c.stats(ctx)
+	if err != nil {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(redisUsedMemoryDesc, prometheus.GaugeValue, float64(stats.UsedMemory))
+	ch <- prometheus.MustNewConstMetric(redisMaxMemoryDesc, prometheus.GaugeValue, float64(stats.MaxMemory))
+	ch <- prometheus.MustNewConstMetric(redisKeysDesc, prometheus.GaugeValue, float64(stats.Keys))
+	ch <- prometheus.MustNewConstMetric(redisExpiredKeysDesc, prometheus.CounterValue, float64(stats.ExpiredKeys))
+	ch <- prometheus.MustNewConstMetric(redisEvictedKeysDesc, prometheus.CounterValue, float64(stats.EvictedKeys))
+}
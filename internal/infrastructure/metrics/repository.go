@@ -0,0 +1,99 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"l0/internal/domain/model"
+	"l0/internal/domain/repository"
+)
+
+// OrderRepository records the latency of every call to the wrapped repository.
+type OrderRepository struct {
+	next repository.OrderRepository
+}
+
+func NewOrderRepository(next repository.OrderRepository) repository.OrderRepository {
+	return &OrderRepository{next: next}
+}
+
+func observeDB(method string, start time.Time, err error) {
+	DBQueryDuration.WithLabelValues(method, result(err)).Observe(since(start))
+}
+
+func (r *OrderRepository) Save(ctx context.Context, order *model.Order) error {
+	start := time.Now()
+	err := r.next.Save(ctx, order)
+	observeDB("Save", start, err)
+	return err
+}
+
+func (r *OrderRepository) SaveBatch(ctx context.Context, orders []*model.Order) []error {
+	start := time.Now()
+	errs := r.next.SaveBatch(ctx, orders)
+	DBQueryDuration.WithLabelValues("SaveBatch", result(errors.Join(errs...))).Observe(since(start))
+	return errs
+}
+
+func (r *OrderRepository) GetByUID(ctx context.Context, orderUID string) (*model.Order, error) {
+	start := time.Now()
+	order, err := r.next.GetByUID(ctx, orderUID)
+	observeDB("GetByUID", start, ignoreNotFound(err))
+	if order != nil {
+		observeAge("db", order)
+	}
+	return order, err
+}
+
+func (r *OrderRepository) GetAll(ctx context.Context) ([]*model.Order, error) {
+	start := time.Now()
+	orders, err := r.next.GetAll(ctx)
+	observeDB("GetAll", start, err)
+	return orders, err
+}
+
+func (r *OrderRepository) List(ctx context.Context, filter model.OrderFilter) ([]*model.Order, error) {
+	start := time.Now()
+	orders, err := r.next.List(ctx, filter)
+	observeDB("List", start, err)
+	return orders, err
+}
+
+// Iterate observes the fetch of every chunk rather than the whole walk, which
+// would mostly time fn.
+func (r *OrderRepository) Iterate(ctx context.Context, filter model.OrderFilter, fn func(chunk []*model.Order, last model.OrderCursor) error) error {
+	var fnErr error
+	start := time.Now()
+	err := r.next.Iterate(ctx, filter, func(chunk []*model.Order, last model.OrderCursor) error {
+		observeDB("Iterate", start, nil)
+		fnErr = fn(chunk, last)
+		start = time.Now()
+		return fnErr
+	})
+	if err != nil && err != fnErr {
+		observeDB("Iterate", start, err)
+	}
+	return err
+}
+
+func (r *OrderRepository) GetByTrackNumber(ctx context.Context, trackNumber string) ([]*model.Order, error) {
+	start := time.Now()
+	orders, err := r.next.GetByTrackNumber(ctx, trackNumber)
+	observeDB("GetByTrackNumber", start, err)
+	return orders, err
+}
+
+func (r *OrderRepository) Exists(ctx context.Context, orderUID string) (bool, error) {
+	start := time.Now()
+	exists, err := r.next.Exists(ctx, orderUID)
+	observeDB("Exists", start, err)
+	return exists, err
+}
+
+func (r *OrderRepository) UpdateStatus(ctx context.Context, orderUID string, from, to model.OrderStatus, reason string) error {
+	start := time.Now()
+	err := r.next.UpdateStatus(ctx, orderUID, from, to, reason)
+	observeDB("UpdateStatus", start, err)
+	return err
+}
@@ -0,0 +1,89 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"l0/internal/domain/model"
+	"l0/internal/domain/repository"
+)
+
+// OrderSaver times the wrapped save use case and counts the field violations
+// of the orders it rejects.
+type OrderSaver struct {
+	next repository.OrderSaver
+}
+
+func NewOrderSaver(next repository.OrderSaver) repository.OrderSaver {
+	return &OrderSaver{next: next}
+}
+
+func (s *OrderSaver) Execute(ctx context.Context, order *model.Order) error {
+	start := time.Now()
+	err := s.next.Execute(ctx, order)
+	UseCaseDuration.WithLabelValues("save_order", saveResult(err)).Observe(since(start))
+	countViolations(err)
+	return err
+}
+
+func (s *OrderSaver) ExecuteBatch(ctx context.Context, orders []*model.Order) []error {
+	start := time.Now()
+	errs := s.next.ExecuteBatch(ctx, orders)
+	UseCaseDuration.WithLabelValues("save_order_batch", batchSaveResult(errs)).Observe(since(start))
+	for _, err := range errs {
+		countViolations(err)
+	}
+	return errs
+}
+
+// saveResult keeps domain outcomes apart from failures, so the error series
+// only grows when something is actually broken.
+func saveResult(err error) string {
+	switch {
+	case err == nil:
+		return ResultOK
+	case errors.Is(err, model.ErrOrderAlreadyExists):
+		return "duplicate"
+	case errors.Is(err, model.ErrInvalidOrderData):
+		return "invalid"
+	default:
+		return ResultError
+	}
+}
+
+// batchSaveResult labels a batch by its worst order: a batch with one failed
+// order is an error however many others were saved.
+func batchSaveResult(errs []error) string {
+	worst := ResultOK
+	for _, err := range errs {
+		switch r := saveResult(err); r {
+		case ResultError:
+			return ResultError
+		case "invalid":
+			worst = r
+		case "duplicate":
+			if worst == ResultOK {
+				worst = r
+			}
+		}
+	}
+	return worst
+}
+
+func countViolations(err error) {
+	var validationErr *model.ValidationError
+	if !errors.As(err, &validationErr) {
+		return
+	}
+	for _, v := range validationErr.Violations {
+		ValidationViolations.WithLabelValues(FieldLabel(v.Field), v.Rule).Inc()
+	}
+}
+
+func ignoreNotFound(err error) error {
+	if errors.Is(err, model.ErrOrderNotFound) {
+		return nil
+	}
+	return err
+}
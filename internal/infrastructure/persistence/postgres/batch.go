@@ -0,0 +1,211 @@
+package postgres
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"time"
+
+	"l0/internal/domain/model"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/stdlib"
+	"go.uber.org/zap"
+)
+
+const uniqueViolationCode = "23505"
+
+// SaveBatch stores orders, together with their initial status history and
+// outbox events, with one COPY per table inside a single transaction.
+// Orders that already exist are reported as model.ErrOrderAlreadyExists, and so
+// are repeats of a UID within the batch once its first copy is stored. If the
+// bulk copy fails, the remaining orders are saved one by one so that a single
+// bad order only fails itself. The returned slice holds one error per order.
+func (r *OrderRepository) SaveBatch(ctx context.Context, orders []*model.Order) []error {
+	errs := make([]error, len(orders))
+	if len(orders) == 0 {
+		return errs
+	}
+
+	if err := r.copyBatch(ctx, orders, errs); err != nil {
+		r.logger.Warn("Batch copy failed, falling back to per-order inserts",
+			zap.Error(err), zap.Int("batch_size", len(orders)))
+		for i, order := range orders {
+			if errors.Is(errs[i], model.ErrOrderAlreadyExists) {
+				continue
+			}
+			errs[i] = r.Save(ctx, order)
+		}
+	}
+	return errs
+}
+
+func (r *OrderRepository) copyBatch(ctx context.Context, orders []*model.Order, errs []error) error {
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer func() {
+		if closeErr := conn.Close(); closeErr != nil && !errors.Is(closeErr, driver.ErrBadConn) {
+			r.logger.Warn("Failed to release connection in SaveBatch", zap.Error(closeErr))
+		}
+	}()
+
+	return conn.Raw(func(driverConn any) error {
+		pgxConn := driverConn.(*stdlib.Conn).Conn()
+
+		tx, err := pgxConn.Begin(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to begin transaction: %w", err)
+		}
+		defer func() {
+			if rbErr := tx.Rollback(ctx); rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) {
+				r.logger.Error("Failed to rollback batch transaction", zap.Error(rbErr))
+			}
+		}()
+
+		existing, err := existingOrderUIDs(ctx, tx, orders)
+		if err != nil {
+			return err
+		}
+
+		batch := make([]*model.Order, 0, len(orders))
+		seen := make(map[string]struct{}, len(orders))
+		var repeats []int
+		for i, order := range orders {
+			if _, ok := existing[order.OrderUID]; ok {
+				errs[i] = model.ErrOrderAlreadyExists
+				continue
+			}
+			if _, ok := seen[order.OrderUID]; ok {
+				repeats = append(repeats, i)
+				continue
+			}
+			seen[order.OrderUID] = struct{}{}
+			batch = append(batch, order)
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+
+		if err := copyOrders(ctx, tx, batch); err != nil {
+			return err
+		}
+
+		if err := tx.Commit(ctx); err != nil {
+			return fmt.Errorf("failed to commit batch transaction: %w", err)
+		}
+		// Repeats are only known to exist once their first copy is committed;
+		// on failure the fallback saves them again.
+		for _, i := range repeats {
+			errs[i] = model.ErrOrderAlreadyExists
+		}
+		return nil
+	})
+}
+
+func existingOrderUIDs(ctx context.Context, tx pgx.Tx, orders []*model.Order) (map[string]struct{}, error) {
+	uids := make([]string, 0, len(orders))
+	for _, order := range orders {
+		uids = append(uids, order.OrderUID)
+	}
+
+	rows, err := tx.Query(ctx, "SELECT order_uid FROM orders WHERE order_uid = ANY($1)", uids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing orders: %w", err)
+	}
+	existing, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan existing orders: %w", err)
+	}
+
+	set := make(map[string]struct{}, len(existing))
+	for _, uid := range existing {
+		set[uid] = struct{}{}
+	}
+	return set, nil
+}
+
+func copyOrders(ctx context.Context, tx pgx.Tx, orders []*model.Order) error {
+	orderRows := make([][]any, 0, len(orders))
+	deliveryRows := make([][]any, 0, len(orders))
+	paymentRows := make([][]any, 0, len(orders))
+	historyRows := make([][]any, 0, len(orders))
+	outboxRows := make([][]any, 0, len(orders))
+	var itemRows [][]any
+
+	for _, order := range orders {
+		dateCreated, err := time.Parse(time.RFC3339, order.DateCreated)
+		if err != nil {
+			return fmt.Errorf("failed to parse date_created of order %s: %w", order.OrderUID, err)
+		}
+
+		orderRows = append(orderRows, []any{
+			order.OrderUID, order.TrackNumber, order.Entry, order.Locale, order.InternalSignature,
+			order.CustomerID, order.DeliveryService, order.Shardkey, order.SmID, dateCreated, order.OofShard,
+			string(initialStatus(order)),
+		})
+		historyRows = append(historyRows, []any{order.OrderUID, string(initialStatus(order))})
+		deliveryRows = append(deliveryRows, []any{
+			order.OrderUID, order.Delivery.Name, order.Delivery.Phone, order.Delivery.Zip,
+			order.Delivery.City, order.Delivery.Address, order.Delivery.Region, order.Delivery.Email,
+		})
+		paymentRows = append(paymentRows, []any{
+			order.OrderUID, order.Payment.Transaction, order.Payment.RequestID, order.Payment.Currency,
+			order.Payment.Provider, order.Payment.Amount, order.Payment.PaymentDt, order.Payment.Bank,
+			order.Payment.DeliveryCost, order.Payment.GoodsTotal, order.Payment.CustomFee,
+		})
+		event, err := orderSavedEvent(order)
+		if err != nil {
+			return err
+		}
+		outboxRows = append(outboxRows, []any{event.AggregateID, event.EventType, event.Payload})
+		for _, item := range order.Items {
+			itemRows = append(itemRows, []any{
+				order.OrderUID, item.ChrtID, item.TrackNumber, item.Price, item.Rid, item.Name,
+				item.Sale, item.Size, item.TotalPrice, item.NmID, item.Brand, item.Status,
+			})
+		}
+	}
+
+	copies := []struct {
+		table   string
+		columns []string
+		rows    [][]any
+	}{
+		{"orders", []string{
+			"order_uid", "track_number", "entry", "locale", "internal_signature",
+			"customer_id", "delivery_service", "shardkey", "sm_id", "date_created", "oof_shard", "status",
+		}, orderRows},
+		{"delivery", []string{
+			"order_uid", "name", "phone", "zip", "city", "address", "region", "email",
+		}, deliveryRows},
+		{"payment", []string{
+			"order_uid", "transaction", "request_id", "currency", "provider", "amount",
+			"payment_dt", "bank", "delivery_cost", "goods_total", "custom_fee",
+		}, paymentRows},
+		{"items", []string{
+			"order_uid", "chrt_id", "track_number", "price", "rid", "name", "sale",
+			"size", "total_price", "nm_id", "brand", "status",
+		}, itemRows},
+		{"status_history", []string{"order_uid", "to_status"}, historyRows},
+		{"outbox", []string{"aggregate_id", "event_type", "payload"}, outboxRows},
+	}
+
+	for _, c := range copies {
+		if len(c.rows) == 0 {
+			continue
+		}
+		if _, err := tx.CopyFrom(ctx, pgx.Identifier{c.table}, c.columns, pgx.CopyFromRows(c.rows)); err != nil {
+			return fmt.Errorf("failed to copy %s: %w", c.table, err)
+		}
+	}
+	return nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode
+}
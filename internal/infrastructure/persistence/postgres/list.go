@@ -0,0 +1,247 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"l0/internal/domain/model"
+
+	"go.uber.org/zap"
+)
+
+const orderSelect = `SELECT 
+            o.order_uid, o.track_number, o.entry, o.locale, o.internal_signature,
+            o.customer_id, o.delivery_service, o.shardkey, o.sm_id, o.date_created, o.oof_shard, o.status,
+            d.name, d.phone, d.zip, d.city, d.address, d.region, d.email,
+            p.transaction, p.request_id, p.currency, p.provider, p.amount,
+            p.payment_dt, p.bank, p.delivery_cost, p.goods_total, p.custom_fee
+        FROM orders o
+        JOIN delivery d ON o.order_uid = d.order_uid
+        JOIN payment p ON o.order_uid = p.order_uid`
+
+// List returns up to filter.Limit orders matching the filter, newest first.
+// The ordering matches idx_orders_date_created and, when a customer is given,
+// idx_orders_customer_date, so every page is a bounded index range scan.
+func (r *OrderRepository) List(ctx context.Context, filter model.OrderFilter) ([]*model.Order, error) {
+	query, args := buildListQuery(filter)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orders: %w", err)
+	}
+
+	orders, err := r.scanOrders(rows, "List")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.attachItems(ctx, orders, "List"); err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+// DefaultIterateChunkSize is the chunk size Iterate uses when the filter
+// sets no limit.
+const DefaultIterateChunkSize = 500
+
+// Iterate pages through the orders with List, so each chunk costs one index
+// range scan plus one items query bounded by the chunk size.
+func (r *OrderRepository) Iterate(ctx context.Context, filter model.OrderFilter, fn func(chunk []*model.Order, last model.OrderCursor) error) error {
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultIterateChunkSize
+	}
+
+	for {
+		chunk, err := r.List(ctx, filter)
+		if err != nil {
+			return err
+		}
+		if len(chunk) == 0 {
+			return nil
+		}
+
+		last, err := model.CursorOf(chunk[len(chunk)-1])
+		if err != nil {
+			return err
+		}
+		if err := fn(chunk, last); err != nil {
+			return err
+		}
+		if len(chunk) < filter.Limit {
+			return nil
+		}
+		filter.Cursor = &last
+	}
+}
+
+func buildListQuery(filter model.OrderFilter) (string, []any) {
+	var (
+		conditions []string
+		args       []any
+	)
+	arg := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	for _, eq := range []struct {
+		column string
+		value  string
+	}{
+		{"o.customer_id", filter.CustomerID},
+		{"o.track_number", filter.TrackNumber},
+		{"o.delivery_service", filter.DeliveryService},
+		{"o.entry", filter.Entry},
+		{"o.locale", filter.Locale},
+	} {
+		if eq.value != "" {
+			conditions = append(conditions, eq.column+" = "+arg(eq.value))
+		}
+	}
+
+	if !filter.CreatedFrom.IsZero() {
+		conditions = append(conditions, "o.date_created >= "+arg(filter.CreatedFrom))
+	}
+	if !filter.CreatedTo.IsZero() {
+		conditions = append(conditions, "o.date_created <= "+arg(filter.CreatedTo))
+	}
+	if !filter.ChangedFrom.IsZero() {
+		conditions = append(conditions, "o.order_uid IN (SELECT h.order_uid FROM status_history h WHERE h.changed_at >= "+arg(filter.ChangedFrom)+")")
+	}
+	if filter.Cursor != nil {
+		conditions = append(conditions, fmt.Sprintf("(o.date_created, o.order_uid) < (%s, %s)",
+			arg(filter.Cursor.DateCreated), arg(filter.Cursor.OrderUID)))
+	}
+
+	var b strings.Builder
+	b.WriteString(orderSelect)
+	if len(conditions) > 0 {
+		b.WriteString("\n        WHERE ")
+		b.WriteString(strings.Join(conditions, " AND "))
+	}
+	b.WriteString("\n        ORDER BY o.date_created DESC, o.order_uid DESC")
+	if filter.Limit > 0 {
+		b.WriteString("\n        LIMIT " + arg(filter.Limit))
+	}
+
+	return b.String(), args
+}
+
+// scanOrders reads rows produced by orderSelect and closes them. Items are
+// left empty for attachItems to fill in.
+func (r *OrderRepository) scanOrders(rows *sql.Rows, method string) ([]*model.Order, error) {
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			r.logger.Warn("Failed to close rows in "+method, zap.Error(closeErr))
+		}
+	}()
+
+	orders := []*model.Order{}
+	for rows.Next() {
+		var order model.Order
+
+		if err := rows.Scan(
+			&order.OrderUID, &order.TrackNumber, &order.Entry, &order.Locale,
+			&order.InternalSignature, &order.CustomerID, &order.DeliveryService,
+			&order.Shardkey, &order.SmID, &order.DateCreated, &order.OofShard, &order.Status,
+			&order.Delivery.Name, &order.Delivery.Phone, &order.Delivery.Zip,
+			&order.Delivery.City, &order.Delivery.Address, &order.Delivery.Region,
+			&order.Delivery.Email,
+			&order.Payment.Transaction, &order.Payment.RequestID, &order.Payment.Currency,
+			&order.Payment.Provider, &order.Payment.Amount, &order.Payment.PaymentDt,
+			&order.Payment.Bank, &order.Payment.DeliveryCost, &order.Payment.GoodsTotal,
+			&order.Payment.CustomFee,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan order: %w", err)
+		}
+		order.Items = []model.Item{}
+		orders = append(orders, &order)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed iterating orders: %w", err)
+	}
+
+	return orders, nil
+}
+
+// attachItems loads the items of all given orders with a single query.
+func (r *OrderRepository) attachItems(ctx context.Context, orders []*model.Order, method string) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	ordersMap := make(map[string]*model.Order, len(orders))
+	orderUIDs := make([]string, 0, len(orders))
+	for _, order := range orders {
+		ordersMap[order.OrderUID] = order
+		orderUIDs = append(orderUIDs, order.OrderUID)
+	}
+
+	itemsRows, err := r.db.QueryContext(ctx, `
+    SELECT order_uid, chrt_id, track_number, price, rid, name, sale, size, total_price, nm_id, brand, status
+    FROM items
+    WHERE order_uid = ANY($1)
+    ORDER BY order_uid, chrt_id`, orderUIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get items: %w", err)
+	}
+
+	defer func() {
+		if closeErr := itemsRows.Close(); closeErr != nil {
+			r.logger.Warn("Failed to close item rows in "+method, zap.Error(closeErr))
+		}
+	}()
+
+	for itemsRows.Next() {
+		var item model.Item
+		var orderUID string
+
+		if err := itemsRows.Scan(
+			&orderUID, &item.ChrtID, &item.TrackNumber, &item.Price, &item.Rid, &item.Name,
+			&item.Sale, &item.Size, &item.TotalPrice, &item.NmID, &item.Brand, &item.Status,
+		); err != nil {
+			return fmt.Errorf("failed to scan item: %w", err)
+		}
+		if order, exists := ordersMap[orderUID]; exists {
+			order.Items = append(order.Items, item)
+		}
+	}
+	if err := itemsRows.Err(); err != nil {
+		return fmt.Errorf("failed iterating items: %w", err)
+	}
+
+	return nil
+}
+
+// GetByTrackNumber returns orders whose own track number or any of whose
+// items' track numbers match, newest first. The UNION keeps both branches on
+// their indexes (idx_orders_track_number, idx_items_track_number).
+func (r *OrderRepository) GetByTrackNumber(ctx context.Context, trackNumber string) ([]*model.Order, error) {
+	rows, err := r.db.QueryContext(ctx, orderSelect+`
+        WHERE o.order_uid IN (
+            SELECT order_uid FROM orders WHERE track_number = $1
+            UNION
+            SELECT order_uid FROM items WHERE track_number = $1
+        )
+        ORDER BY o.date_created DESC, o.order_uid DESC`, trackNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get orders by track number: %w", err)
+	}
+
+	orders, err := r.scanOrders(rows, "GetByTrackNumber")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.attachItems(ctx, orders, "GetByTrackNumber"); err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
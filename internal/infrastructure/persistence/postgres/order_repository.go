@@ -41,14 +41,25 @@ func (r *OrderRepository) Save(ctx context.Context, order *model.Order) (err err
 	_, err = tx.ExecContext(ctx, `
         INSERT INTO orders (
             order_uid, track_number, entry, locale, internal_signature, 
-            customer_id, delivery_service, shardkey, sm_id, date_created, oof_shard
-        ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+            customer_id, delivery_service, shardkey, sm_id, date_created, oof_shard, status
+        ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
 		order.OrderUID, order.TrackNumber, order.Entry, order.Locale, order.InternalSignature,
-		order.CustomerID, order.DeliveryService, order.Shardkey, order.SmID, order.DateCreated, order.OofShard)
+		order.CustomerID, order.DeliveryService, order.Shardkey, order.SmID, order.DateCreated, order.OofShard,
+		initialStatus(order))
+	if isUniqueViolation(err) {
+		return model.ErrOrderAlreadyExists
+	}
 	if err != nil {
 		return fmt.Errorf("failed to insert order: %w", err)
 	}
 
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO status_history (order_uid, to_status) VALUES ($1, $2)`,
+		order.OrderUID, initialStatus(order))
+	if err != nil {
+		return fmt.Errorf("failed to insert status history: %w", err)
+	}
+
 	_, err = tx.ExecContext(ctx, `
         INSERT INTO delivery (order_uid, name, phone, zip, city, address, region, email)
         VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
@@ -83,6 +94,18 @@ func (r *OrderRepository) Save(ctx context.Context, order *model.Order) (err err
 		}
 	}
 
+	event, err := orderSavedEvent(order)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO outbox (aggregate_id, event_type, payload)
+        VALUES ($1, $2, $3)`,
+		event.AggregateID, event.EventType, event.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to insert outbox event: %w", err)
+	}
+
 	if err = tx.Commit(); err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
@@ -94,11 +117,11 @@ func (r *OrderRepository) GetByUID(ctx context.Context, orderUID string) (*model
 
 	err := r.db.QueryRowContext(ctx, `
         SELECT order_uid, track_number, entry, locale, internal_signature, 
-               customer_id, delivery_service, shardkey, sm_id, date_created, oof_shard
+               customer_id, delivery_service, shardkey, sm_id, date_created, oof_shard, status
         FROM orders WHERE order_uid = $1`, orderUID).Scan(
 		&order.OrderUID, &order.TrackNumber, &order.Entry, &order.Locale,
 		&order.InternalSignature, &order.CustomerID, &order.DeliveryService,
-		&order.Shardkey, &order.SmID, &order.DateCreated, &order.OofShard)
+		&order.Shardkey, &order.SmID, &order.DateCreated, &order.OofShard, &order.Status)
 
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, model.ErrOrderNotFound
@@ -158,111 +181,87 @@ func (r *OrderRepository) GetByUID(ctx context.Context, orderUID string) (*model
 	return &order, nil
 }
 
+// GetAll loads every order. It is built on Iterate, so the items query stays
+// bounded, but the result still holds the whole table; prefer Iterate.
 func (r *OrderRepository) GetAll(ctx context.Context) ([]*model.Order, error) {
-	query := `SELECT 
-            o.order_uid, o.track_number, o.entry, o.locale, o.internal_signature,
-            o.customer_id, o.delivery_service, o.shardkey, o.sm_id, o.date_created, o.oof_shard,
-            d.name, d.phone, d.zip, d.city, d.address, d.region, d.email,
-            p.transaction, p.request_id, p.currency, p.provider, p.amount,
-            p.payment_dt, p.bank, p.delivery_cost, p.goods_total, p.custom_fee
-        FROM orders o
-        JOIN delivery d ON o.order_uid = d.order_uid
-        JOIN payment p ON o.order_uid = p.order_uid
-        ORDER BY o.order_uid`
-
-	rows, err := r.db.QueryContext(ctx, query)
+	var orders []*model.Order
+	err := r.Iterate(ctx, model.OrderFilter{}, func(chunk []*model.Order, _ model.OrderCursor) error {
+		orders = append(orders, chunk...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+func (r *OrderRepository) Exists(ctx context.Context, orderUID string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM orders WHERE order_uid = $1)",
+		orderUID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check if order exists: %w", err)
+	}
+	return exists, nil
+}
+
+func (r *OrderRepository) UpdateStatus(ctx context.Context, orderUID string, from, to model.OrderStatus, reason string) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get orders: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
 
 	defer func() {
-		if closeErr := rows.Close(); closeErr != nil {
-			r.logger.Warn("Failed to close rows in GetAll", zap.Error(closeErr))
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				r.logger.Error("Failed to rollback transaction",
+					zap.Error(rbErr), zap.String("order_uid", orderUID))
+			}
 		}
 	}()
 
-	ordersMap := make(map[string]*model.Order)
-	var orderUIDs []string
-
-	for rows.Next() {
-		var order model.Order
-
-		if err := rows.Scan(
-			&order.OrderUID, &order.TrackNumber, &order.Entry, &order.Locale,
-			&order.InternalSignature, &order.CustomerID, &order.DeliveryService,
-			&order.Shardkey, &order.SmID, &order.DateCreated, &order.OofShard,
-			&order.Delivery.Name, &order.Delivery.Phone, &order.Delivery.Zip,
-			&order.Delivery.City, &order.Delivery.Address, &order.Delivery.Region,
-			&order.Delivery.Email,
-			&order.Payment.Transaction, &order.Payment.RequestID, &order.Payment.Currency,
-			&order.Payment.Provider, &order.Payment.Amount, &order.Payment.PaymentDt,
-			&order.Payment.Bank, &order.Payment.DeliveryCost, &order.Payment.GoodsTotal,
-			&order.Payment.CustomFee,
-		); err != nil {
-			return nil, fmt.Errorf("failed to scan order: %w", err)
-		}
-		order.Items = []model.Item{}
-		ordersMap[order.OrderUID] = &order
-		orderUIDs = append(orderUIDs, order.OrderUID)
+	res, err := tx.ExecContext(ctx, "UPDATE orders SET status = $1 WHERE order_uid = $2 AND status = $3", to, orderUID, from)
+	if err != nil {
+		return fmt.Errorf("failed to update order status: %w", err)
 	}
-
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("failed iterating orders: %w", err)
+	updated, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check updated order status: %w", err)
 	}
-
-	if len(orderUIDs) == 0 {
-		return []*model.Order{}, nil
+	if updated == 0 {
+		return model.ErrStatusConflict
 	}
 
-	itemsQuery := `
-    SELECT order_uid, chrt_id, track_number, price, rid, name, sale, size, total_price, nm_id, brand, status
-    FROM items
-    WHERE order_uid = ANY($1)
-    ORDER BY order_uid, chrt_id`
-
-	itemsRows, err := r.db.QueryContext(ctx, itemsQuery, orderUIDs)
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO status_history (order_uid, from_status, to_status, reason)
+        VALUES ($1, $2, $3, $4)`,
+		orderUID, from, to, reason)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get items: %w", err)
+		return fmt.Errorf("failed to insert status history: %w", err)
 	}
 
-	defer func() {
-		if closeErr := itemsRows.Close(); closeErr != nil {
-			r.logger.Warn("Failed to close item rows in GetAll", zap.Error(closeErr))
-		}
-	}()
-
-	for itemsRows.Next() {
-		var item model.Item
-		var orderUID string
-
-		if err := itemsRows.Scan(
-			&orderUID, &item.ChrtID, &item.TrackNumber, &item.Price, &item.Rid, &item.Name,
-			&item.Sale, &item.Size, &item.TotalPrice, &item.NmID, &item.Brand, &item.Status,
-		); err != nil {
-			return nil, fmt.Errorf("failed to scan item: %w", err)
-		}
-		if order, exists := ordersMap[orderUID]; exists {
-			order.Items = append(order.Items, item)
-		}
+	event, err := orderStatusChangedEvent(orderUID, from, to, reason)
+	if err != nil {
+		return err
 	}
-	if err := itemsRows.Err(); err != nil {
-		return nil, fmt.Errorf("failed iterating items: %w", err)
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO outbox (aggregate_id, event_type, payload)
+        VALUES ($1, $2, $3)`,
+		event.AggregateID, event.EventType, event.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to insert outbox event: %w", err)
 	}
 
-	orders := make([]*model.Order, 0, len(ordersMap))
-	for _, uid := range orderUIDs {
-		orders = append(orders, ordersMap[uid])
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
-
-	return orders, nil
+	return nil
 }
 
-func (r *OrderRepository) Exists(ctx context.Context, orderUID string) (bool, error) {
-	var exists bool
-	err := r.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM orders WHERE order_uid = $1)",
-		orderUID).Scan(&exists)
-	if err != nil {
-		return false, fmt.Errorf("failed to check if order exists: %w", err)
+// initialStatus is the status a new order is stored with.
+func initialStatus(order *model.Order) model.OrderStatus {
+	if order.Status == "" {
+		return model.OrderStatusCreated
 	}
-	return exists, nil
+	return order.Status
 }
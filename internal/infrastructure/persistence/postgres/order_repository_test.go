@@ -3,10 +3,12 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"l0/internal/domain/model"
 
@@ -81,7 +83,7 @@ func createTestLogger(t *testing.T) *zap.Logger {
 func setupTestDB(t *testing.T) *sql.DB {
 	t.Helper()
 
-	_, err := testDB.ExecContext(context.Background(), "TRUNCATE orders, payment, delivery, items CASCADE")
+	_, err := testDB.ExecContext(context.Background(), "TRUNCATE orders, payment, delivery, items, status_history, outbox CASCADE")
 	require.NoError(t, err, "failed to truncate tables")
 
 	return testDB
@@ -183,7 +185,7 @@ func TestOrderRepository_Save_DuplicateKey(t *testing.T) {
 	require.NoError(t, err)
 
 	err = repo.Save(ctx, &order)
-	assert.Error(t, err)
+	assert.ErrorIs(t, err, model.ErrOrderAlreadyExists)
 }
 
 func TestOrderRepository_Save_MultipleItems(t *testing.T) {
@@ -339,3 +341,301 @@ func TestOrderRepository_Save_BoundaryValues(t *testing.T) {
 	assert.Len(t, retrieved.OrderUID, 50)
 	assert.Len(t, retrieved.TrackNumber, 50)
 }
+
+func TestOrderRepository_SaveBatch_Success(t *testing.T) {
+	db := setupTestDB(t)
+	logger := createTestLogger(t)
+	repo := NewOrderRepository(db, logger)
+	ctx := context.Background()
+
+	first, second := createTestOrder(t), createTestOrder(t)
+
+	errs := repo.SaveBatch(ctx, []*model.Order{&first, &second})
+	require.Len(t, errs, 2)
+	require.NoError(t, errs[0])
+	require.NoError(t, errs[1])
+
+	retrieved, err := repo.GetByUID(ctx, second.OrderUID)
+	require.NoError(t, err)
+	assert.Equal(t, second.Delivery.Name, retrieved.Delivery.Name)
+	assert.ElementsMatch(t, second.Items, retrieved.Items)
+}
+
+func TestOrderRepository_SaveBatch_ExistingOrders(t *testing.T) {
+	db := setupTestDB(t)
+	logger := createTestLogger(t)
+	repo := NewOrderRepository(db, logger)
+	ctx := context.Background()
+
+	existing, fresh := createTestOrder(t), createTestOrder(t)
+	require.NoError(t, repo.Save(ctx, &existing))
+
+	errs := repo.SaveBatch(ctx, []*model.Order{&existing, &fresh, &fresh})
+	require.Len(t, errs, 3)
+	require.ErrorIs(t, errs[0], model.ErrOrderAlreadyExists)
+	require.NoError(t, errs[1])
+	require.ErrorIs(t, errs[2], model.ErrOrderAlreadyExists)
+}
+
+func TestOrderRepository_SaveBatch_BadOrderDoesNotPoisonBatch(t *testing.T) {
+	db := setupTestDB(t)
+	logger := createTestLogger(t)
+	repo := NewOrderRepository(db, logger)
+	ctx := context.Background()
+
+	good, bad := createTestOrder(t), createTestOrder(t)
+	bad.Items[0].Name = strings.Repeat("x", 200)
+
+	errs := repo.SaveBatch(ctx, []*model.Order{&good, &bad})
+	require.Len(t, errs, 2)
+	require.NoError(t, errs[0])
+	require.Error(t, errs[1])
+
+	exists, err := repo.Exists(ctx, good.OrderUID)
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	exists, err = repo.Exists(ctx, bad.OrderUID)
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestOrderRepository_SaveBatch_RepeatedUIDWhoseFirstCopyFails(t *testing.T) {
+	db := setupTestDB(t)
+	logger := createTestLogger(t)
+	repo := NewOrderRepository(db, logger)
+	ctx := context.Background()
+
+	good, bad := createTestOrder(t), createTestOrder(t)
+	bad.Items[0].Name = strings.Repeat("x", 200)
+
+	errs := repo.SaveBatch(ctx, []*model.Order{&good, &bad, &bad})
+	require.Len(t, errs, 3)
+	require.NoError(t, errs[0])
+	require.Error(t, errs[1])
+	require.Error(t, errs[2])
+	assert.NotErrorIs(t, errs[2], model.ErrOrderAlreadyExists)
+
+	exists, err := repo.Exists(ctx, bad.OrderUID)
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestOrderRepository_UpdateStatus(t *testing.T) {
+	db := setupTestDB(t)
+	logger := createTestLogger(t)
+	repo := NewOrderRepository(db, logger)
+	ctx := context.Background()
+
+	order := createTestOrder(t)
+	require.NoError(t, repo.Save(ctx, &order))
+
+	retrieved, err := repo.GetByUID(ctx, order.OrderUID)
+	require.NoError(t, err)
+	assert.Equal(t, model.OrderStatusCreated, retrieved.Status)
+
+	err = repo.UpdateStatus(ctx, order.OrderUID, model.OrderStatusCreated, model.OrderStatusPaid, "payment received")
+	require.NoError(t, err)
+
+	retrieved, err = repo.GetByUID(ctx, order.OrderUID)
+	require.NoError(t, err)
+	assert.Equal(t, model.OrderStatusPaid, retrieved.Status)
+
+	var history int
+	err = db.QueryRowContext(ctx, "SELECT COUNT(*) FROM status_history WHERE order_uid = $1", order.OrderUID).Scan(&history)
+	require.NoError(t, err)
+	assert.Equal(t, 2, history)
+
+	var events int
+	err = db.QueryRowContext(ctx, "SELECT COUNT(*) FROM outbox WHERE aggregate_id = $1 AND event_type = $2",
+		order.OrderUID, model.EventOrderStatusChanged).Scan(&events)
+	require.NoError(t, err)
+	assert.Equal(t, 1, events)
+}
+
+func TestOrderRepository_UpdateStatus_Conflict(t *testing.T) {
+	db := setupTestDB(t)
+	logger := createTestLogger(t)
+	repo := NewOrderRepository(db, logger)
+	ctx := context.Background()
+
+	order := createTestOrder(t)
+	require.NoError(t, repo.Save(ctx, &order))
+
+	err := repo.UpdateStatus(ctx, order.OrderUID, model.OrderStatusPaid, model.OrderStatusAssembling, "")
+	require.ErrorIs(t, err, model.ErrStatusConflict)
+
+	retrieved, err := repo.GetByUID(ctx, order.OrderUID)
+	require.NoError(t, err)
+	assert.Equal(t, model.OrderStatusCreated, retrieved.Status)
+}
+
+func TestOrderRepository_List_FiltersAndKeyset(t *testing.T) {
+	db := setupTestDB(t)
+	logger := createTestLogger(t)
+	repo := NewOrderRepository(db, logger)
+	ctx := context.Background()
+
+	customerID := gofakeit.UUID()
+	base := time.Date(2021, 11, 26, 6, 0, 0, 0, time.UTC)
+	var uids []string
+	for i := range 5 {
+		order := createTestOrder(t)
+		order.CustomerID = customerID
+		order.DateCreated = base.Add(time.Duration(i) * time.Hour).Format(time.RFC3339)
+		require.NoError(t, repo.Save(ctx, &order))
+		uids = append(uids, order.OrderUID)
+	}
+	other := createTestOrder(t)
+	require.NoError(t, repo.Save(ctx, &other))
+
+	first, err := repo.List(ctx, model.OrderFilter{CustomerID: customerID, Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, first, 2)
+	assert.Equal(t, uids[4], first[0].OrderUID)
+	assert.Equal(t, uids[3], first[1].OrderUID)
+	assert.NotEmpty(t, first[0].Items)
+
+	last := first[1]
+	createdAt, err := time.Parse(time.RFC3339Nano, last.DateCreated)
+	require.NoError(t, err)
+
+	rest, err := repo.List(ctx, model.OrderFilter{
+		CustomerID: customerID,
+		Cursor:     &model.OrderCursor{DateCreated: createdAt, OrderUID: last.OrderUID},
+	})
+	require.NoError(t, err)
+	require.Len(t, rest, 3)
+	assert.Equal(t, uids[2], rest[0].OrderUID)
+	assert.Equal(t, uids[0], rest[2].OrderUID)
+
+	ranged, err := repo.List(ctx, model.OrderFilter{
+		CustomerID:  customerID,
+		CreatedFrom: base.Add(time.Hour),
+		CreatedTo:   base.Add(2 * time.Hour),
+	})
+	require.NoError(t, err)
+	require.Len(t, ranged, 2)
+	assert.Equal(t, uids[2], ranged[0].OrderUID)
+	assert.Equal(t, uids[1], ranged[1].OrderUID)
+}
+
+func TestOrderRepository_List_ChangedFrom(t *testing.T) {
+	db := setupTestDB(t)
+	logger := createTestLogger(t)
+	repo := NewOrderRepository(db, logger)
+	ctx := context.Background()
+
+	customerID := gofakeit.UUID()
+	var uids []string
+	for range 3 {
+		order := createTestOrder(t)
+		order.CustomerID = customerID
+		require.NoError(t, repo.Save(ctx, &order))
+		uids = append(uids, order.OrderUID)
+	}
+	// The first two orders were stored long ago; the third one just now.
+	_, err := db.ExecContext(ctx, "UPDATE status_history SET changed_at = NOW() - INTERVAL '1 day' WHERE order_uid = ANY($1)",
+		[]string{uids[0], uids[1]})
+	require.NoError(t, err)
+
+	filter := model.OrderFilter{CustomerID: customerID, ChangedFrom: time.Now().Add(-time.Hour)}
+	changed, err := repo.List(ctx, filter)
+	require.NoError(t, err)
+	require.Len(t, changed, 1)
+	assert.Equal(t, uids[2], changed[0].OrderUID)
+
+	require.NoError(t, repo.UpdateStatus(ctx, uids[0], model.OrderStatusCreated, model.OrderStatusPaid, ""))
+
+	changed, err = repo.List(ctx, filter)
+	require.NoError(t, err)
+	var got []string
+	for _, order := range changed {
+		got = append(got, order.OrderUID)
+	}
+	assert.ElementsMatch(t, []string{uids[0], uids[2]}, got)
+}
+
+func TestOrderRepository_Iterate_ChunksAndResume(t *testing.T) {
+	db := setupTestDB(t)
+	logger := createTestLogger(t)
+	repo := NewOrderRepository(db, logger)
+	ctx := context.Background()
+
+	customerID := gofakeit.UUID()
+	base := time.Date(2021, 11, 26, 6, 0, 0, 0, time.UTC)
+	var uids []string
+	for i := range 5 {
+		order := createTestOrder(t)
+		order.CustomerID = customerID
+		order.DateCreated = base.Add(time.Duration(i) * time.Hour).Format(time.RFC3339)
+		require.NoError(t, repo.Save(ctx, &order))
+		uids = append(uids, order.OrderUID)
+	}
+
+	var (
+		seen    []string
+		cursors []model.OrderCursor
+	)
+	err := repo.Iterate(ctx, model.OrderFilter{CustomerID: customerID, Limit: 2}, func(chunk []*model.Order, last model.OrderCursor) error {
+		for _, order := range chunk {
+			assert.NotEmpty(t, order.Items)
+			seen = append(seen, order.OrderUID)
+		}
+		cursors = append(cursors, last)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{uids[4], uids[3], uids[2], uids[1], uids[0]}, seen)
+	require.Len(t, cursors, 3)
+	assert.Equal(t, uids[3], cursors[0].OrderUID)
+
+	var resumed []string
+	err = repo.Iterate(ctx, model.OrderFilter{CustomerID: customerID, Limit: 2, Cursor: &cursors[0]}, func(chunk []*model.Order, _ model.OrderCursor) error {
+		for _, order := range chunk {
+			resumed = append(resumed, order.OrderUID)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{uids[2], uids[1], uids[0]}, resumed)
+
+	stop := errors.New("stop")
+	calls := 0
+	err = repo.Iterate(ctx, model.OrderFilter{CustomerID: customerID, Limit: 2}, func([]*model.Order, model.OrderCursor) error {
+		calls++
+		return stop
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, 1, calls)
+}
+
+func TestOrderRepository_GetByTrackNumber_OrderAndItemLevel(t *testing.T) {
+	db := setupTestDB(t)
+	logger := createTestLogger(t)
+	repo := NewOrderRepository(db, logger)
+	ctx := context.Background()
+
+	byOrder := createTestOrder(t)
+	byOrder.TrackNumber = "SHAREDTRACK"
+	require.NoError(t, repo.Save(ctx, &byOrder))
+
+	byItem := createTestOrder(t)
+	byItem.Items[0].TrackNumber = "SHAREDTRACK"
+	require.NoError(t, repo.Save(ctx, &byItem))
+
+	unrelated := createTestOrder(t)
+	require.NoError(t, repo.Save(ctx, &unrelated))
+
+	orders, err := repo.GetByTrackNumber(ctx, "SHAREDTRACK")
+	require.NoError(t, err)
+	require.Len(t, orders, 2)
+
+	uids := []string{orders[0].OrderUID, orders[1].OrderUID}
+	assert.ElementsMatch(t, []string{byOrder.OrderUID, byItem.OrderUID}, uids)
+	assert.NotEmpty(t, orders[0].Items)
+
+	none, err := repo.GetByTrackNumber(ctx, "NOSUCHTRACK")
+	require.NoError(t, err)
+	assert.Empty(t, none)
+}
@@ -0,0 +1,145 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"l0/internal/domain/model"
+	"l0/internal/domain/repository"
+
+	"go.uber.org/zap"
+)
+
+type OutboxRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+func NewOutboxRepository(db *sql.DB, logger *zap.Logger) repository.OutboxRepository {
+	return &OutboxRepository{db: db, logger: logger}
+}
+
+// PublishPending claims events with FOR UPDATE SKIP LOCKED and keeps the
+// transaction open while publish runs, so the row locks hold until the
+// events are marked as published. Other relays skip the locked rows and
+// claim the next ones; if this one dies, the rollback releases them.
+func (r *OutboxRepository) PublishPending(ctx context.Context, limit int, publish func(ctx context.Context, events []model.OutboxEvent) error) (n int, err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				r.logger.Error("Failed to rollback outbox transaction", zap.Error(rbErr))
+			}
+		}
+	}()
+
+	events, err := r.claimPending(ctx, tx, limit)
+	if err != nil {
+		return 0, err
+	}
+	if len(events) == 0 {
+		if err = tx.Commit(); err != nil {
+			return 0, fmt.Errorf("failed to commit outbox transaction: %w", err)
+		}
+		return 0, nil
+	}
+
+	if err = publish(ctx, events); err != nil {
+		return 0, err
+	}
+
+	ids := make([]int64, len(events))
+	for i, event := range events {
+		ids[i] = event.ID
+	}
+	if _, err = tx.ExecContext(ctx, "UPDATE outbox SET published_at = NOW() WHERE id = ANY($1)", ids); err != nil {
+		return 0, fmt.Errorf("failed to mark outbox events as published: %w", err)
+	}
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit outbox transaction: %w", err)
+	}
+	return len(events), nil
+}
+
+func (r *OutboxRepository) claimPending(ctx context.Context, tx *sql.Tx, limit int) ([]model.OutboxEvent, error) {
+	rows, err := tx.QueryContext(ctx, `
+        SELECT id, aggregate_id, event_type, payload, created_at
+        FROM outbox
+        WHERE published_at IS NULL
+        ORDER BY id
+        LIMIT $1
+        FOR UPDATE SKIP LOCKED`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim pending outbox events: %w", err)
+	}
+
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			r.logger.Warn("Failed to close rows in claimPending", zap.Error(closeErr))
+		}
+	}()
+
+	var events []model.OutboxEvent
+	for rows.Next() {
+		var event model.OutboxEvent
+		if err := rows.Scan(&event.ID, &event.AggregateID, &event.EventType, &event.Payload, &event.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan outbox event: %w", err)
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed iterating outbox events: %w", err)
+	}
+
+	return events, nil
+}
+
+func (r *OutboxRepository) DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM outbox WHERE published_at < $1", before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete published outbox events: %w", err)
+	}
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count deleted outbox events: %w", err)
+	}
+	return deleted, nil
+}
+
+func orderSavedEvent(order *model.Order) (model.OutboxEvent, error) {
+	payload, err := json.Marshal(model.OrderSavedEvent{
+		OrderUID:    order.OrderUID,
+		TrackNumber: order.TrackNumber,
+		CustomerID:  order.CustomerID,
+		DateCreated: order.DateCreated,
+		SavedAt:     time.Now().UTC(),
+	})
+	if err != nil {
+		return model.OutboxEvent{}, fmt.Errorf("failed to marshal %s event: %w", model.EventOrderSaved, err)
+	}
+	return model.OutboxEvent{AggregateID: order.OrderUID, EventType: model.EventOrderSaved, Payload: payload}, nil
+}
+
+func orderStatusChangedEvent(orderUID string, from, to model.OrderStatus, reason string) (model.OutboxEvent, error) {
+	payload, err := json.Marshal(model.OrderStatusChangedEvent{
+		OrderUID:  orderUID,
+		From:      from,
+		To:        to,
+		Reason:    reason,
+		ChangedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return model.OutboxEvent{}, fmt.Errorf("failed to marshal %s event: %w", model.EventOrderStatusChanged, err)
+	}
+	return model.OutboxEvent{AggregateID: orderUID, EventType: model.EventOrderStatusChanged, Payload: payload}, nil
+}
@@ -0,0 +1,166 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"l0/internal/domain/model"
+	"l0/internal/domain/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errKeepPending = errors.New("keep events pending")
+
+// pendingEvents returns the events PublishPending would publish, leaving them
+// unpublished.
+func pendingEvents(t *testing.T, repo repository.OutboxRepository) []model.OutboxEvent {
+	t.Helper()
+	var events []model.OutboxEvent
+	_, err := repo.PublishPending(context.Background(), 10, func(_ context.Context, claimed []model.OutboxEvent) error {
+		events = claimed
+		return errKeepPending
+	})
+	if len(events) > 0 {
+		require.ErrorIs(t, err, errKeepPending)
+	} else {
+		require.NoError(t, err)
+	}
+	return events
+}
+
+func TestOutboxRepository_SaveWritesOrderSavedEvent(t *testing.T) {
+	db := setupTestDB(t)
+	logger := createTestLogger(t)
+	orderRepo := NewOrderRepository(db, logger)
+	outboxRepo := NewOutboxRepository(db, logger)
+	ctx := context.Background()
+
+	order := createTestOrder(t)
+	require.NoError(t, orderRepo.Save(ctx, &order))
+
+	events := pendingEvents(t, outboxRepo)
+	require.Len(t, events, 1)
+
+	assert.Equal(t, order.OrderUID, events[0].AggregateID)
+	assert.Equal(t, model.EventOrderSaved, events[0].EventType)
+
+	var payload model.OrderSavedEvent
+	require.NoError(t, json.Unmarshal(events[0].Payload, &payload))
+	assert.Equal(t, order.OrderUID, payload.OrderUID)
+	assert.Equal(t, order.CustomerID, payload.CustomerID)
+}
+
+func TestOutboxRepository_SaveBatchWritesEvents(t *testing.T) {
+	db := setupTestDB(t)
+	logger := createTestLogger(t)
+	orderRepo := NewOrderRepository(db, logger)
+	outboxRepo := NewOutboxRepository(db, logger)
+	ctx := context.Background()
+
+	first, second := createTestOrder(t), createTestOrder(t)
+	for _, err := range orderRepo.SaveBatch(ctx, []*model.Order{&first, &second}) {
+		require.NoError(t, err)
+	}
+
+	events := pendingEvents(t, outboxRepo)
+	require.Len(t, events, 2)
+	assert.Equal(t, first.OrderUID, events[0].AggregateID)
+	assert.Equal(t, second.OrderUID, events[1].AggregateID)
+}
+
+func TestOutboxRepository_PublishPendingAndCleanup(t *testing.T) {
+	db := setupTestDB(t)
+	logger := createTestLogger(t)
+	orderRepo := NewOrderRepository(db, logger)
+	outboxRepo := NewOutboxRepository(db, logger)
+	ctx := context.Background()
+
+	order := createTestOrder(t)
+	require.NoError(t, orderRepo.Save(ctx, &order))
+
+	var published []model.OutboxEvent
+	n, err := outboxRepo.PublishPending(ctx, 10, func(_ context.Context, events []model.OutboxEvent) error {
+		published = events
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	require.Len(t, published, 1)
+	assert.Equal(t, order.OrderUID, published[0].AggregateID)
+
+	assert.Empty(t, pendingEvents(t, outboxRepo))
+
+	deleted, err := outboxRepo.DeletePublishedBefore(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, deleted, "recently published events must be retained")
+
+	deleted, err = outboxRepo.DeletePublishedBefore(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+}
+
+func TestOutboxRepository_PublishPending_FailedPublishKeepsEventsPending(t *testing.T) {
+	db := setupTestDB(t)
+	logger := createTestLogger(t)
+	orderRepo := NewOrderRepository(db, logger)
+	outboxRepo := NewOutboxRepository(db, logger)
+	ctx := context.Background()
+
+	order := createTestOrder(t)
+	require.NoError(t, orderRepo.Save(ctx, &order))
+
+	brokerDown := errors.New("broker down")
+	n, err := outboxRepo.PublishPending(ctx, 10, func(context.Context, []model.OutboxEvent) error {
+		return brokerDown
+	})
+	require.ErrorIs(t, err, brokerDown)
+	assert.Zero(t, n)
+
+	assert.Len(t, pendingEvents(t, outboxRepo), 1)
+}
+
+func TestOutboxRepository_PublishPending_SkipsClaimedEvents(t *testing.T) {
+	db := setupTestDB(t)
+	logger := createTestLogger(t)
+	orderRepo := NewOrderRepository(db, logger)
+	outboxRepo := NewOutboxRepository(db, logger)
+	ctx := context.Background()
+
+	first, second := createTestOrder(t), createTestOrder(t)
+	require.NoError(t, orderRepo.Save(ctx, &first))
+	require.NoError(t, orderRepo.Save(ctx, &second))
+
+	// The first relay claims one event and is still publishing it while a
+	// second relay polls.
+	claimed := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		_, err := outboxRepo.PublishPending(ctx, 1, func(context.Context, []model.OutboxEvent) error {
+			close(claimed)
+			<-release
+			return nil
+		})
+		done <- err
+	}()
+	<-claimed
+
+	var published []model.OutboxEvent
+	n, err := outboxRepo.PublishPending(ctx, 10, func(_ context.Context, events []model.OutboxEvent) error {
+		published = events
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	require.Len(t, published, 1)
+	assert.Equal(t, second.OrderUID, published[0].AggregateID, "the event claimed by the first relay is skipped")
+
+	close(release)
+	require.NoError(t, <-done)
+	assert.Empty(t, pendingEvents(t, outboxRepo))
+}
@@ -0,0 +1,92 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+
+	"l0/internal/domain/model"
+	"l0/internal/domain/repository"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const attrCacheHit = attribute.Key("cache.hit")
+
+// OrderCache opens a client span around every call to the wrapped cache and
+// marks lookups with whether they hit.
+type OrderCache struct {
+	next repository.OrderCache
+}
+
+func NewOrderCache(next repository.OrderCache) repository.OrderCache {
+	return &OrderCache{next: next}
+}
+
+func startCache(ctx context.Context, operation string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs, semconv.DBSystemNameRedis, semconv.DBOperationName(operation))
+	return Tracer().Start(ctx, "redis."+operation, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
+func (c *OrderCache) Get(ctx context.Context, orderUID string) (*model.Order, error) {
+	ctx, span := startCache(ctx, "Get", OrderUID(orderUID))
+	order, err := c.next.Get(ctx, orderUID)
+	// An order recorded as missing is a hit that answers "not found", not a
+	// failed lookup.
+	notFound := errors.Is(err, model.ErrOrderNotFound)
+	span.SetAttributes(attrCacheHit.Bool(order != nil || notFound))
+	if notFound {
+		span.End()
+		return nil, err
+	}
+	End(span, err)
+	return order, err
+}
+
+func (c *OrderCache) Set(ctx context.Context, order *model.Order) error {
+	ctx, span := startCache(ctx, "Set", OrderUID(order.OrderUID))
+	err := c.next.Set(ctx, order)
+	End(span, err)
+	return err
+}
+
+func (c *OrderCache) MarkMissing(ctx context.Context, orderUID string) error {
+	ctx, span := startCache(ctx, "MarkMissing", OrderUID(orderUID))
+	err := c.next.MarkMissing(ctx, orderUID)
+	End(span, err)
+	return err
+}
+
+func (c *OrderCache) GetByTrack(ctx context.Context, trackNumber string) ([]*model.Order, error) {
+	ctx, span := startCache(ctx, "GetByTrack")
+	orders, err := c.next.GetByTrack(ctx, trackNumber)
+	span.SetAttributes(attrCacheHit.Bool(orders != nil))
+	End(span, err)
+	return orders, err
+}
+
+func (c *OrderCache) SetByTrack(ctx context.Context, trackNumber string, orders []*model.Order) error {
+	ctx, span := startCache(ctx, "SetByTrack")
+	err := c.next.SetByTrack(ctx, trackNumber, orders)
+	End(span, err)
+	return err
+}
+
+func (c *OrderCache) Fill(ctx context.Context, orders []*model.Order) error {
+	ctx, span := startCache(ctx, "Fill", semconv.DBOperationBatchSize(len(orders)))
+	err := c.next.Fill(ctx, orders)
+	End(span, err)
+	return err
+}
+
+func (c *OrderCache) Delete(ctx context.Context, orderUID string) error {
+	ctx, span := startCache(ctx, "Delete", OrderUID(orderUID))
+	err := c.next.Delete(ctx, orderUID)
+	End(span, err)
+	return err
+}
+
+func (c *OrderCache) Close() error {
+	return c.next.Close()
+}
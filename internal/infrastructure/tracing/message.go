@@ -0,0 +1,55 @@
+package tracing
+
+import (
+	"context"
+
+	"l0/internal/domain/model"
+
+	"go.opentelemetry.io/otel"
+)
+
+// HeaderCarrier lets the propagator read and write trace context in message
+// headers. Set replaces an existing header rather than adding a second one,
+// so a re-injected message carries a single traceparent.
+type HeaderCarrier struct {
+	Headers *[]model.MessageHeader
+}
+
+func (c HeaderCarrier) Get(key string) string {
+	for i := len(*c.Headers) - 1; i >= 0; i-- {
+		if (*c.Headers)[i].Key == key {
+			return string((*c.Headers)[i].Value)
+		}
+	}
+	return ""
+}
+
+func (c HeaderCarrier) Set(key, value string) {
+	out := make([]model.MessageHeader, 0, len(*c.Headers)+1)
+	for _, h := range *c.Headers {
+		if h.Key != key {
+			out = append(out, h)
+		}
+	}
+	*c.Headers = append(out, model.MessageHeader{Key: key, Value: []byte(value)})
+}
+
+func (c HeaderCarrier) Keys() []string {
+	keys := make([]string, 0, len(*c.Headers))
+	for _, h := range *c.Headers {
+		keys = append(keys, h.Key)
+	}
+	return keys
+}
+
+// Extract returns ctx with the remote span context found in headers.
+func Extract(ctx context.Context, headers []model.MessageHeader) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, HeaderCarrier{Headers: &headers})
+}
+
+// Inject returns a copy of headers carrying the span context of ctx.
+func Inject(ctx context.Context, headers []model.MessageHeader) []model.MessageHeader {
+	out := append([]model.MessageHeader(nil), headers...)
+	otel.GetTextMapPropagator().Inject(ctx, HeaderCarrier{Headers: &out})
+	return out
+}
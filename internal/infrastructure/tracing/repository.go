@@ -0,0 +1,97 @@
+package tracing
+
+import (
+	"context"
+
+	"l0/internal/domain/model"
+	"l0/internal/domain/repository"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// OrderRepository opens a client span around every call to the wrapped
+// repository.
+type OrderRepository struct {
+	next repository.OrderRepository
+}
+
+func NewOrderRepository(next repository.OrderRepository) repository.OrderRepository {
+	return &OrderRepository{next: next}
+}
+
+func startDB(ctx context.Context, method string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs, semconv.DBSystemNamePostgreSQL, semconv.DBOperationName(method))
+	return Tracer().Start(ctx, "postgres."+method, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
+func (r *OrderRepository) Save(ctx context.Context, order *model.Order) error {
+	ctx, span := startDB(ctx, "Save", OrderUID(order.OrderUID))
+	err := r.next.Save(ctx, order)
+	End(span, err)
+	return err
+}
+
+func (r *OrderRepository) SaveBatch(ctx context.Context, orders []*model.Order) []error {
+	ctx, span := startDB(ctx, "SaveBatch", semconv.DBOperationBatchSize(len(orders)))
+	errs := r.next.SaveBatch(ctx, orders)
+	End(span, nil)
+	return errs
+}
+
+func (r *OrderRepository) GetByUID(ctx context.Context, orderUID string) (*model.Order, error) {
+	ctx, span := startDB(ctx, "GetByUID", OrderUID(orderUID))
+	order, err := r.next.GetByUID(ctx, orderUID)
+	End(span, err)
+	return order, err
+}
+
+func (r *OrderRepository) GetAll(ctx context.Context) ([]*model.Order, error) {
+	ctx, span := startDB(ctx, "GetAll")
+	orders, err := r.next.GetAll(ctx)
+	End(span, err)
+	return orders, err
+}
+
+func (r *OrderRepository) List(ctx context.Context, filter model.OrderFilter) ([]*model.Order, error) {
+	ctx, span := startDB(ctx, "List")
+	orders, err := r.next.List(ctx, filter)
+	End(span, err)
+	return orders, err
+}
+
+// Iterate keeps a single span open for the whole walk, so the work fn does
+// per chunk shows up as its children.
+func (r *OrderRepository) Iterate(ctx context.Context, filter model.OrderFilter, fn func(chunk []*model.Order, last model.OrderCursor) error) error {
+	ctx, span := startDB(ctx, "Iterate")
+	chunks := 0
+	err := r.next.Iterate(ctx, filter, func(chunk []*model.Order, last model.OrderCursor) error {
+		chunks++
+		return fn(chunk, last)
+	})
+	span.SetAttributes(attribute.Int("db.iterate.chunks", chunks))
+	End(span, err)
+	return err
+}
+
+func (r *OrderRepository) GetByTrackNumber(ctx context.Context, trackNumber string) ([]*model.Order, error) {
+	ctx, span := startDB(ctx, "GetByTrackNumber")
+	orders, err := r.next.GetByTrackNumber(ctx, trackNumber)
+	End(span, err)
+	return orders, err
+}
+
+func (r *OrderRepository) Exists(ctx context.Context, orderUID string) (bool, error) {
+	ctx, span := startDB(ctx, "Exists", OrderUID(orderUID))
+	exists, err := r.next.Exists(ctx, orderUID)
+	End(span, err)
+	return exists, err
+}
+
+func (r *OrderRepository) UpdateStatus(ctx context.Context, orderUID string, from, to model.OrderStatus, reason string) error {
+	ctx, span := startDB(ctx, "UpdateStatus", OrderUID(orderUID))
+	err := r.next.UpdateStatus(ctx, orderUID, from, to, reason)
+	End(span, err)
+	return err
+}
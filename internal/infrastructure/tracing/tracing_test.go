@@ -0,0 +1,172 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"l0/internal/domain/model"
+	"l0/internal/domain/repository/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/mock/gomock"
+)
+
+// recordSpans installs a tracer provider that keeps finished spans in
+// memory. Tests using it change global state and must not run in parallel.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+	return recorder
+}
+
+func TestHeaderCarrier_RoundTrip(t *testing.T) {
+	recordSpans(t)
+
+	ctx, span := Tracer().Start(context.Background(), "send")
+	defer span.End()
+
+	headers := []model.MessageHeader{{Key: "traceparent", Value: []byte("stale")}, {Key: "x-other", Value: []byte("1")}}
+	headers = Inject(ctx, headers)
+
+	var traceparents int
+	for _, h := range headers {
+		if h.Key == "traceparent" {
+			traceparents++
+		}
+	}
+	assert.Equal(t, 1, traceparents, "injecting must replace the existing traceparent")
+
+	got := trace.SpanContextFromContext(Extract(context.Background(), headers))
+	assert.Equal(t, span.SpanContext().TraceID(), got.TraceID())
+	assert.Equal(t, span.SpanContext().SpanID(), got.SpanID())
+	assert.True(t, got.IsRemote())
+}
+
+func TestOrderRepository_Spans(t *testing.T) {
+	recorder := recordSpans(t)
+
+	ctrl := gomock.NewController(t)
+	next := mocks.NewMockOrderRepository(ctrl)
+	repo := NewOrderRepository(next)
+	ctx := context.Background()
+
+	next.EXPECT().GetByUID(gomock.Any(), "missing").Return(nil, model.ErrOrderNotFound)
+	next.EXPECT().Exists(gomock.Any(), "broken").Return(false, errors.New("connection refused"))
+
+	_, _ = repo.GetByUID(ctx, "missing")
+	_, _ = repo.Exists(ctx, "broken")
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+
+	assert.Equal(t, "postgres.GetByUID", spans[0].Name())
+	assert.Equal(t, codes.Unset, spans[0].Status().Code, "not found is not a failure")
+
+	assert.Equal(t, "postgres.Exists", spans[1].Name())
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.Equal(t, trace.SpanKindClient, spans[1].SpanKind())
+}
+
+func TestOrderCache_MarksHits(t *testing.T) {
+	recorder := recordSpans(t)
+
+	ctrl := gomock.NewController(t)
+	next := mocks.NewMockOrderCache(ctrl)
+	c := NewOrderCache(next)
+
+	next.EXPECT().Get(gomock.Any(), "hit").Return(&model.Order{OrderUID: "hit"}, nil)
+	next.EXPECT().Get(gomock.Any(), "miss").Return(nil, nil)
+	next.EXPECT().Get(gomock.Any(), "missing").Return(nil, model.ErrOrderNotFound)
+	next.EXPECT().Get(gomock.Any(), "broken").Return(nil, errors.New("connection refused"))
+
+	_, _ = c.Get(context.Background(), "hit")
+	_, _ = c.Get(context.Background(), "miss")
+	_, err := c.Get(context.Background(), "missing")
+	require.ErrorIs(t, err, model.ErrOrderNotFound)
+	_, _ = c.Get(context.Background(), "broken")
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 4)
+	hits := make([]bool, len(spans))
+	for i, s := range spans {
+		for _, attr := range s.Attributes() {
+			if attr.Key == attrCacheHit {
+				hits[i] = attr.Value.AsBool()
+			}
+		}
+	}
+	assert.Equal(t, []bool{true, false, true, false}, hits)
+
+	assert.Equal(t, codes.Unset, spans[2].Status().Code, "a recorded missing order is not a failure")
+	assert.Empty(t, spans[2].Events())
+	assert.Equal(t, codes.Error, spans[3].Status().Code)
+}
+
+func TestGinMiddleware_ContinuesIncomingTrace(t *testing.T) {
+	recorder := recordSpans(t)
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(GinMiddleware())
+	var handlerSpan trace.SpanContext
+	r.GET("/order/:order_uid", func(c *gin.Context) {
+		handlerSpan = trace.SpanContextFromContext(c.Request.Context())
+		c.Status(http.StatusInternalServerError)
+	})
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest(http.MethodGet, "/order/abc", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "GET /order/:order_uid", span.Name())
+	assert.Equal(t, traceID, span.SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", span.Parent().SpanID().String())
+	assert.Equal(t, codes.Error, span.Status().Code)
+	assert.Equal(t, span.SpanContext().SpanID(), handlerSpan.SpanID(), "handlers must see the server span")
+}
+
+func TestOrderValidator_Spans(t *testing.T) {
+	recorder := recordSpans(t)
+
+	ctrl := gomock.NewController(t)
+	next := mocks.NewMockOrderValidator(ctrl)
+	validator := NewOrderValidator(next)
+	ctx, parent := Tracer().Start(context.Background(), "process")
+
+	invalid := &model.ValidationError{Violations: []model.FieldViolation{{Field: "order_uid", Rule: "required"}}}
+	next.EXPECT().Validate(gomock.Any(), &model.Order{OrderUID: "valid"}).Return(nil)
+	next.EXPECT().Validate(gomock.Any(), &model.Order{}).Return(invalid)
+
+	require.NoError(t, validator.Validate(ctx, &model.Order{OrderUID: "valid"}))
+	require.ErrorIs(t, validator.Validate(ctx, &model.Order{}), invalid)
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	for _, span := range spans[:2] {
+		assert.Equal(t, "validate order", span.Name())
+		assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+	}
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Equal(t, codes.Error, spans[1].Status().Code, "a rejected order fails its validation step")
+}
@@ -0,0 +1,25 @@
+package tracing
+
+import (
+	"context"
+
+	"l0/internal/domain/model"
+	"l0/internal/domain/repository"
+)
+
+// OrderValidator runs the wrapped validator under its own span, so a
+// rejected order shows up as a failed validation step in its trace.
+type OrderValidator struct {
+	next repository.OrderValidator
+}
+
+func NewOrderValidator(next repository.OrderValidator) repository.OrderValidator {
+	return &OrderValidator{next: next}
+}
+
+func (v *OrderValidator) Validate(ctx context.Context, order *model.Order) error {
+	ctx, span := Tracer().Start(ctx, "validate order")
+	err := v.next.Validate(ctx, order)
+	End(span, err)
+	return err
+}
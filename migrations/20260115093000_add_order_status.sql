@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'created';
+
+CREATE TABLE IF NOT EXISTS status_history (
+            id BIGSERIAL PRIMARY KEY,
+            order_uid VARCHAR(50) NOT NULL,
+            from_status VARCHAR(20),
+            to_status VARCHAR(20) NOT NULL,
+            reason VARCHAR(200),
+            changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+            FOREIGN KEY (order_uid) REFERENCES orders(order_uid)
+        );
+
+CREATE INDEX IF NOT EXISTS idx_status_history_order_uid ON status_history(order_uid, changed_at);
+
+INSERT INTO status_history (order_uid, from_status, to_status)
+SELECT order_uid, NULL, status FROM orders;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_status_history_order_uid;
+DROP TABLE status_history;
+ALTER TABLE orders DROP COLUMN status;
+-- +goose StatementEnd